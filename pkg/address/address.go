@@ -2,19 +2,50 @@ package address
 
 import (
 	"BrunoCoin/pkg/proto"
-	"time"
+	"sync"
+
+	"google.golang.org/grpc"
 )
 
+// Conns (Connections) hands out a connection to an
+// address that is kept open and shared by every request
+// to it (see peer.Conn).
+type Conns interface {
+	Get() (*grpc.ClientConn, error)
+}
+
+// Address is the address of a node on the network.
+// conns is where requests to the address get their
+// connection, or nil if each request makes its own
+// (see SetConns)
 type Address struct {
 	Addr     string
 	LastSeen uint32
-	SentVer  time.Time
+	conns    Conns
+	mutex    sync.Mutex
 }
 
 func New(addr string, lastSeen uint32) *Address {
-	return &Address{Addr: addr, LastSeen: lastSeen, SentVer: time.Time{}}
+	return &Address{Addr: addr, LastSeen: lastSeen}
 }
 
 func (a *Address) Serialize() *proto.Address {
 	return &proto.Address{Addr: a.Addr, LastSeen: a.LastSeen}
 }
+
+// SetConns (SetConnections) makes requests to the
+// address share the connection from c, or make their
+// own if c is nil.
+func (a *Address) SetConns(c Conns) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.conns = c
+}
+
+// GetConns (GetConnections) returns where requests to
+// the address get their connection (see SetConns).
+func (a *Address) GetConns() Conns {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.conns
+}
@@ -5,12 +5,95 @@ import (
 	"fmt"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/metadata"
+	"net"
+	"strconv"
 	"time"
 )
 
 // RPCTimeout is default timeout for rpc client calls
 const RPCTimeout = 2 * time.Second
 
+// AddrMeKey is the metadata key under which a node
+// puts its own address when making a request, so that
+// the receiving node knows which peer a request came from.
+const AddrMeKey = "addr-me"
+
+// addrMe attaches the address of the calling node
+// to every request it is used for.
+type addrMe string
+
+func (a addrMe) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{AddrMeKey: string(a)}, nil
+}
+
+func (a addrMe) RequireTransportSecurity() bool {
+	return false
+}
+
+// From returns a call option that tells the receiving
+// node that a request came from the node at addr.
+func From(addr string) grpc.CallOption {
+	return grpc.PerRPCCredentials(addrMe(addr))
+}
+
+// Sender returns the address that the calling node
+// attached to a request with From, or "" if it did not
+// attach one (or attached something that isn't a
+// host:port address).
+func Sender(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get(AddrMeKey); len(v) > 0 {
+		if _, _, err := net.SplitHostPort(v[0]); err == nil {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// TknKey (TokenKey) is the metadata key under which a
+// node puts the token a peer gave it during the handshake,
+// so that the peer can tell its requests apart from ones
+// that only claim its address.
+const TknKey = "tkn"
+
+// tkn (token) attaches a token to every request made on
+// a connection.
+type tkn uint64
+
+func (t tkn) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{TknKey: strconv.FormatUint(uint64(t), 10)}, nil
+}
+
+func (t tkn) RequireTransportSecurity() bool {
+	return false
+}
+
+// WithTkn (WithToken) returns a dial option that attaches
+// a token to every request made on the connection.
+func WithTkn(t uint64) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(tkn(t))
+}
+
+// SenderTkn (SenderToken) returns the token that the
+// calling node attached to a request (see WithTkn), or
+// 0 if it did not attach one.
+func SenderTkn(ctx context.Context) uint64 {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0
+	}
+	if v := md.Get(TknKey); len(v) > 0 {
+		t, _ := strconv.ParseUint(v[0], 10, 64)
+		return t
+	}
+	return 0
+}
+
 // clientUnaryInterceptor is a client unary interceptor that injects a default timeout
 func clientUnaryInterceptor(
 	ctx context.Context,
@@ -25,132 +108,283 @@ func clientUnaryInterceptor(
 	return invoker(ctx, method, req, reply, cc, opts...)
 }
 
-func connectToServer(addr string) (*grpc.ClientConn, error) {
-	return grpc.Dial(addr, []grpc.DialOption{
+// MxBckoff (MaxBackoff) is the longest a connection
+// waits between attempts to reconnect to a peer.
+const MxBckoff = 2 * time.Second
+
+// Dial opens a connection to the node at addr. The
+// connection is made in the background, and when it
+// drops it is made again, waiting longer after each
+// failed attempt (up to MxBckoff).
+// Inputs:
+// addr string the address of the node
+// opts ...grpc.DialOption more options, like WithTkn
+func Dial(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return grpc.Dial(addr, append([]grpc.DialOption{
 		grpc.WithInsecure(),
 		grpc.FailOnNonTempDialError(true),
 		grpc.WithUnaryInterceptor(clientUnaryInterceptor),
-	}...)
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.Config{BaseDelay: 100 * time.Millisecond, Multiplier: 1.6, Jitter: 0.2, MaxDelay: MxBckoff},
+			MinConnectTimeout: RPCTimeout,
+		}),
+	}, opts...)...)
 }
 
 // Returns callback to close connection
 func (a *Address) GetConnection() (proto.BrunoCoinClient, *grpc.ClientConn, error) {
-	cc, err := connectToServer(a.Addr)
+	cc, err := Dial(a.Addr)
 	if err != nil {
 		return nil, nil, err
 	}
 	return proto.NewBrunoCoinClient(cc), cc, err
 }
 
-func (a *Address) VersionRPC(request *proto.VersionRequest) (*proto.Empty, error) {
-	c, cc, err := a.GetConnection()
+// conn (connection) returns the connection to make a
+// request on: the shared connection from the address's
+// Conns if it has one, otherwise a new connection.
+// Inputs:
+// rpc string the name of the request, for errors
+// Returns:
+// *grpc.ClientConn the connection
+// func() to call when the request is done, which closes
+// the connection unless it is shared
+// error if the connection could not be made
+func (a *Address) conn(rpc string) (*grpc.ClientConn, func(), error) {
+	if c := a.GetConns(); c != nil {
+		if cc, err := c.Get(); err == nil {
+			return cc, func() {}, nil
+		}
+	}
+	cc, err := Dial(a.Addr)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer func() {
-		err := cc.Close()
-		if err != nil {
-			fmt.Printf("ERROR {Address.VersionRPC}: " +
-				"error when closing connection")
+	return cc, func() {
+		if err := cc.Close(); err != nil {
+			fmt.Printf("ERROR {Address.%v}: "+
+				"error when closing connection", rpc)
 		}
-	}()
-	reply, err := c.Version(context.Background(), request)
-	a.SentVer = time.Now()
+	}, nil
+}
+
+func (a *Address) VersionRPC(request *proto.VersionRequest, opts ...grpc.CallOption) (*proto.Verack, error) {
+	cc, done, err := a.conn("VersionRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).Version(context.Background(), request, opts...)
 	return reply, err
 }
 
-func (a *Address) GetBlocksRPC(request *proto.GetBlocksRequest) (*proto.GetBlocksResponse, error) {
-	c, cc, err := a.GetConnection()
+func (a *Address) SendVerackRPC(request *proto.Empty, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("SendVerackRPC")
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		err := cc.Close()
-		if err != nil {
-			fmt.Printf("ERROR {Address.GetBlocksRPC}: " +
-				"error when closing connection")
-		}
-	}()
-	reply, err := c.GetBlocks(context.Background(), request)
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).SendVerack(context.Background(), request, opts...)
 	return reply, err
 }
 
-func (a *Address) GetDataRPC(request *proto.GetDataRequest) (*proto.GetDataResponse, error) {
-	c, cc, err := a.GetConnection()
+func (a *Address) GetBlocksRPC(request *proto.GetBlocksRequest, opts ...grpc.CallOption) (*proto.GetBlocksResponse, error) {
+	cc, done, err := a.conn("GetBlocksRPC")
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		err := cc.Close()
-		if err != nil {
-			fmt.Printf("ERROR {Address.GetDataRPC}: " +
-				"error when closing connection")
-		}
-	}()
-	reply, err := c.GetData(context.Background(), request)
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).GetBlocks(context.Background(), request, opts...)
 	return reply, err
 }
 
-func (a *Address) GetAddressesRPC(request *proto.Empty) (*proto.Addresses, error) {
-	c, cc, err := a.GetConnection()
+func (a *Address) GetHeadersRPC(request *proto.GetHeadersRequest, opts ...grpc.CallOption) (*proto.Headers, error) {
+	cc, done, err := a.conn("GetHeadersRPC")
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		err := cc.Close()
-		if err != nil {
-			fmt.Printf("ERROR {Address.GetAddressesRPC}: " +
-				"error when closing connection")
-		}
-	}()
-	reply, err := c.GetAddresses(context.Background(), request)
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).GetHeaders(context.Background(), request, opts...)
 	return reply, err
 }
 
-func (a *Address) SendAddressesRPC(request *proto.Addresses) (*proto.Empty, error) {
-	c, cc, err := a.GetConnection()
+func (a *Address) GetDataRPC(request *proto.GetDataRequest, opts ...grpc.CallOption) (*proto.GetDataResponse, error) {
+	cc, done, err := a.conn("GetDataRPC")
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		err := cc.Close()
-		if err != nil {
-			fmt.Printf("ERROR {Address.SendAddressesRPC}: " +
-				"error when closing connection")
-		}
-	}()
-	reply, err := c.SendAddresses(context.Background(), request)
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).GetData(context.Background(), request, opts...)
 	return reply, err
 }
 
-func (a *Address) ForwardTransactionRPC(request *proto.Transaction) (*proto.Empty, error) {
-	c, cc, err := a.GetConnection()
+func (a *Address) GetMerkleProofRPC(request *proto.GetMerkleProofRequest, opts ...grpc.CallOption) (*proto.MerkleProof, error) {
+	cc, done, err := a.conn("GetMerkleProofRPC")
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		err := cc.Close()
-		if err != nil {
-			fmt.Printf("ERROR {Address.ForwardTransactionRPC}: " +
-				"error when closing connection")
-		}
-	}()
-	reply, err := c.ForwardTransaction(context.Background(), request)
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).GetMerkleProof(context.Background(), request, opts...)
 	return reply, err
 }
 
-func (a *Address) ForwardBlockRPC(request *proto.Block) (*proto.Empty, error) {
-	c, cc, err := a.GetConnection()
+func (a *Address) GetMerkleProofsRPC(request *proto.GetMerkleProofsRequest, opts ...grpc.CallOption) (*proto.MerkleProofs, error) {
+	cc, done, err := a.conn("GetMerkleProofsRPC")
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		err := cc.Close()
-		if err != nil {
-			fmt.Printf("ERROR {Address.ForwardBlockRPC}: " +
-				"error when closing connection")
-		}
-	}()
-	reply, err := c.ForwardBlock(context.Background(), request)
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).GetMerkleProofs(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) GetAddressesRPC(request *proto.Empty, opts ...grpc.CallOption) (*proto.Addresses, error) {
+	cc, done, err := a.conn("GetAddressesRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).GetAddresses(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) SendAddressesRPC(request *proto.Addresses, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("SendAddressesRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).SendAddresses(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) ForwardTransactionRPC(request *proto.Transaction, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("ForwardTransactionRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).ForwardTransaction(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) ForwardBlockRPC(request *proto.Block, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("ForwardBlockRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).ForwardBlock(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) SendInvRPC(request *proto.Inv, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("SendInvRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).SendInv(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) SendCmpctBlkRPC(request *proto.CompactBlock, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("SendCmpctBlkRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).SendCmpctBlk(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) GetBlockTxnRPC(request *proto.GetBlockTxnRequest, opts ...grpc.CallOption) (*proto.BlockTxn, error) {
+	cc, done, err := a.conn("GetBlockTxnRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewBrunoCoinClient(cc).GetBlockTxn(context.Background(), request, opts...)
+	return reply, err
+}
+
+// GetChnlConnection (GetChannelConnection) is
+// GetConnection for the payment channel service.
+// Returns callback to close connection
+func (a *Address) GetChnlConnection() (proto.PaymentChannelClient, *grpc.ClientConn, error) {
+	cc, err := Dial(a.Addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proto.NewPaymentChannelClient(cc), cc, err
+}
+
+func (a *Address) OpenChannelRPC(request *proto.ChannelOpen, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("OpenChannelRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewPaymentChannelClient(cc).OpenChannel(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) UpdateChannelRPC(request *proto.ChannelUpdate, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("UpdateChannelRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewPaymentChannelClient(cc).UpdateChannel(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) CloseChannelRPC(request *proto.ChannelClose, opts ...grpc.CallOption) (*proto.Transaction, error) {
+	cc, done, err := a.conn("CloseChannelRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewPaymentChannelClient(cc).CloseChannel(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) ListBansRPC(request *proto.Empty, opts ...grpc.CallOption) (*proto.Bans, error) {
+	cc, done, err := a.conn("ListBansRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewAdminClient(cc).ListBans(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) AddBanRPC(request *proto.BanRequest, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("AddBanRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewAdminClient(cc).AddBan(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) RmvBanRPC(request *proto.BanRequest, opts ...grpc.CallOption) (*proto.Empty, error) {
+	cc, done, err := a.conn("RmvBanRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewAdminClient(cc).RmvBan(context.Background(), request, opts...)
+	return reply, err
+}
+
+func (a *Address) GetRejectionsRPC(request *proto.Empty, opts ...grpc.CallOption) (*proto.RejectionCounts, error) {
+	cc, done, err := a.conn("GetRejectionsRPC")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	reply, err := proto.NewAdminClient(cc).GetRejections(context.Background(), request, opts...)
 	return reply, err
 }
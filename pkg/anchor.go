@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"errors"
+)
+
+// Anchr (Anchor) is where a payload was published on
+// the main chain.
+// Hght (Height) is the height of the block
+// Blk (Block) is the block
+// Tx (Transaction) is the transaction carrying the
+// payload
+// Idx (Index) is the index of Tx in the block
+// Prf (Proof) is the merkle branch of Tx, which leads to
+// the block's merkle root (see block.MrklPrf)
+type Anchr struct {
+	Hght uint32
+	Blk  *block.Block
+	Tx   *tx.Transaction
+	Idx  int
+	Prf  []string
+}
+
+// PubData (PublishData) publishes a payload, such as the
+// hash of a document, on the chain in a data output (see
+// script.Data). The wallet pays the fee.
+// Inputs:
+// d []byte the payload, at most script.MxDataSz bytes
+// fee uint32 the fee
+// Returns:
+// *tx.Transaction the transaction carrying the payload
+// error if the node has no wallet, or the wallet can't
+// make the transaction
+func (n *Node) PubData(d []byte, fee uint32) (*tx.Transaction, error) {
+	if n.Wallet == nil {
+		return nil, errors.New("node has no wallet")
+	}
+	return n.Wallet.PubData(d, fee)
+}
+
+// FndData (FindData) finds the first block on the main
+// chain with a transaction carrying a payload.
+// Inputs:
+// d []byte the payload
+// Returns:
+// *Anchr where the payload was published
+// bool False if no block on the main chain carries it
+func (n *Node) FndData(d []byte) (*Anchr, bool) {
+	b, h, i, ok := n.Chain.FndData(d)
+	if !ok {
+		return nil, false
+	}
+	prf, _ := block.MrklPrf(b.Transactions, i)
+	return &Anchr{Hght: h, Blk: b, Tx: b.Transactions[i], Idx: i, Prf: prf}, true
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"context"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// innocent is the reasons a transaction or block can be
+// rejected without the node that sent it misbehaving,
+// such as it knowing about blocks this node doesn't, or
+// having a different clock or config.
+var innocent = map[valerr.Code]bool{
+	valerr.MissingInput: true,
+	valerr.UnknownPrv:   true,
+	valerr.NonFinal:     true,
+	valerr.TimeTooNew:   true,
+	valerr.BadVer:       true,
+	valerr.Oversize:     true,
+}
+
+// Misbhv (Misbehaving) adds an offense to the
+// misbehavior score of the peer at addr, if the
+// validation error it sent something with isn't innocent.
+// addr must be known to be the sender (see prSndr), or
+// any node could get an honest peer banned.
+// At Conf.BanScr the peer is disconnected and banned.
+// Inputs:
+// addr string the address of the peer, may be ""
+// o peer.Ofns the offense
+// err error why what the peer sent was rejected
+func (n *Node) Misbhv(addr string, o peer.Ofns, err error) {
+	p := n.PeerDb.Get(addr)
+	if p == nil || innocent[valerr.CodeOf(err)] {
+		return
+	}
+	s := p.Misbhv(o)
+	utils.Debug.Printf("%v scored %v from %v for %v, now %v", utils.FmtAddr(n.Addr), o,
+		utils.FmtAddr(addr), err, s)
+	if s >= n.Conf.BanScr {
+		n.Ban(addr, n.Conf.BanTm, s)
+	}
+}
+
+// Ban disconnects from the peers on the host of an
+// address, and refuses to peer with the host until the
+// ban ends (see Version and SendAddresses).
+// Inputs:
+// addr string the address
+// d time.Duration how long the ban lasts
+// scr uint32 the misbehavior score it is banned for, 0
+// if it is banned by hand
+func (n *Node) Ban(addr string, d time.Duration, scr uint32) {
+	utils.Debug.Printf("%v banned %v for %v", utils.FmtAddr(n.Addr), utils.FmtAddr(addr), d)
+	n.Bans.Add(addr, d, scr)
+	for _, p := range n.PeerDb.List() {
+		if n.Bans.Has(p.Addr.Addr) {
+			n.PeerDb.Rmv(p.Addr.Addr)
+		}
+	}
+}
+
+// Handles list bans request (request for the addresses the node refuses to peer with)
+func (n *Node) ListBans(ctx context.Context, in *proto.Empty) (*proto.Bans, error) {
+	res := &proto.Bans{}
+	for a, b := range n.Bans.List() {
+		res.Bans = append(res.Bans, &proto.Ban{Addr: a, Until: b.Until.Unix(), Score: b.Scr})
+	}
+	sort.Slice(res.Bans, func(i, j int) bool { return res.Bans[i].Addr < res.Bans[j].Addr })
+	return res, nil
+}
+
+// Handles add ban request (request to disconnect from an address and refuse to peer with it)
+func (n *Node) AddBan(ctx context.Context, in *proto.BanRequest) (*proto.Empty, error) {
+	if in.Addr == "" || in.Addr == n.Addr {
+		return nil, status.Errorf(codes.InvalidArgument, "can't ban %q", in.Addr)
+	}
+	d := n.Conf.BanTm
+	if in.Seconds > 0 {
+		d = time.Duration(in.Seconds) * time.Second
+	}
+	n.Ban(in.Addr, d, 0)
+	return &proto.Empty{}, nil
+}
+
+// Handles remove ban request (request to let a banned address peer again)
+func (n *Node) RmvBan(ctx context.Context, in *proto.BanRequest) (*proto.Empty, error) {
+	if !n.Bans.Rmv(in.Addr) {
+		return nil, status.Errorf(codes.NotFound, "%v is not banned", in.Addr)
+	}
+	utils.Debug.Printf("%v unbanned %v", utils.FmtAddr(n.Addr), utils.FmtAddr(in.Addr))
+	return &proto.Empty{}, nil
+}
+
+// Handles get rejections request (request for how many transactions and blocks the node rejected, by reason)
+func (n *Node) GetRejections(ctx context.Context, in *proto.Empty) (*proto.RejectionCounts, error) {
+	res := &proto.RejectionCounts{Counts: make(map[string]uint64)}
+	for cd, ct := range n.Rjcts.Snapshot() {
+		res.Counts[string(cd)] = ct
+	}
+	return res, nil
+}
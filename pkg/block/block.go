@@ -1,6 +1,7 @@
 package block
 
 import (
+	"BrunoCoin/pkg/block/enc"
 	"BrunoCoin/pkg/block/tx"
 	"BrunoCoin/pkg/proto"
 	"BrunoCoin/pkg/utils"
@@ -9,6 +10,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Header is a wrapper around the
@@ -50,6 +52,19 @@ type Block struct {
 	Transactions []*tx.Transaction
 }
 
+// CurVer (CurrentVersion) is the newest block version
+// that this software knows the rules for. Blocks with
+// a higher version are rejected.
+const CurVer uint32 = 1
+
+// EncVer (EncodingVersion) is the first block version
+// whose header is hashed, and whose size is counted,
+// with the canonical encoding. Older blocks keep their
+// legacy hash, so chains made before the encoding stay
+// valid. Since a block can't have an older version than
+// the block before it, a chain switches over once.
+const EncVer uint32 = 1
+
 func New(prvHsh string, txs []*tx.Transaction, target string) *Block {
 	txsd := make([]*proto.Transaction, len(txs))
 	for i := range txsd {
@@ -57,9 +72,10 @@ func New(prvHsh string, txs []*tx.Transaction, target string) *Block {
 	}
 	b := &proto.Block{
 		Header: &proto.BlockHeader{
-			Version:          0,
+			Version:          CurVer,
 			PrevBlockHash:    prvHsh,
 			MerkleRoot:       CalcMrklRt(txs),
+			Timestamp:        uint32(time.Now().Unix()),
 			DifficultyTarget: target,
 		},
 		Transactions: txsd,
@@ -75,18 +91,24 @@ func Deserialize(b *proto.Block) *Block {
 		txs[i] = tx.Deserialize(b.Transactions[i])
 	}
 	return &Block{
-		Hdr: Header{
-			Ver:       b.Header.Version,
-			PrvBlkHsh: b.Header.PrevBlockHash,
-			MrklRt:    b.Header.MerkleRoot,
-			Timestamp: b.Header.Timestamp,
-			DiffTarg:  b.Header.DifficultyTarget,
-			Nonce:     b.Header.Nonce,
-		},
+		Hdr:          DeserializeHdr(b.Header),
 		Transactions: txs,
 	}
 }
 
+// DeserializeHdr (DeserializeHeader) creates a
+// new Header from a protobuf block header.
+func DeserializeHdr(h *proto.BlockHeader) Header {
+	return Header{
+		Ver:       h.Version,
+		PrvBlkHsh: h.PrevBlockHash,
+		MrklRt:    h.MerkleRoot,
+		Timestamp: h.Timestamp,
+		DiffTarg:  h.DifficultyTarget,
+		Nonce:     h.Nonce,
+	}
+}
+
 // Serialize unwraps the block and returns
 // the underlying protobuf block.
 func (b *Block) Serialize() *proto.Block {
@@ -95,18 +117,24 @@ func (b *Block) Serialize() *proto.Block {
 		txs[i] = b.Transactions[i].Serialize()
 	}
 	return &proto.Block{
-		Header: &proto.BlockHeader{
-			Version:          b.Hdr.Ver,
-			PrevBlockHash:    b.Hdr.PrvBlkHsh,
-			MerkleRoot:       b.Hdr.MrklRt,
-			Timestamp:        b.Hdr.Timestamp,
-			DifficultyTarget: b.Hdr.DiffTarg,
-			Nonce:            b.Hdr.Nonce,
-		},
+		Header:       b.Hdr.Serialize(),
 		Transactions: txs,
 	}
 }
 
+// Serialize returns the header as a
+// protobuf block header.
+func (h *Header) Serialize() *proto.BlockHeader {
+	return &proto.BlockHeader{
+		Version:          h.Ver,
+		PrevBlockHash:    h.PrvBlkHsh,
+		MerkleRoot:       h.MrklRt,
+		Timestamp:        h.Timestamp,
+		DifficultyTarget: h.DiffTarg,
+		Nonce:            h.Nonce,
+	}
+}
+
 // SatisfiesPOW tests a hash and a difficulty
 // target to see if the hash satisfies the
 // difficulty target (hash < dif target).
@@ -137,9 +165,52 @@ func (b *Block) SatisfiesPOW(dt string) bool {
 // block in bytes
 // Returns:
 // uint32 the number of bytes the block
-// takes up
+// takes up, which is the size of its canonical
+// encoding (or its protobuf size if the block is
+// older than EncVer)
 func (b *Block) Sz() uint32 {
-	return proto.SzOfBlk(b.Serialize())
+	if b.Hdr.Ver < EncVer {
+		return proto.SzOfBlk(b.Serialize())
+	}
+	return uint32(len(b.Enc()))
+}
+
+// Enc (Encode) returns the canonical encoding of the
+// header (see docs/encoding.md).
+// Returns:
+// []byte the encoded header
+func (h *Header) Enc() []byte {
+	w := &enc.Wtr{}
+	h.EncTo(w)
+	return w.Bytes()
+}
+
+// EncTo (EncodeTo) writes the canonical encoding of
+// the header.
+// Inputs:
+// w *enc.Wtr the writer to write to
+func (h *Header) EncTo(w *enc.Wtr) {
+	w.U32(h.Ver)
+	w.Hex(h.PrvBlkHsh)
+	w.Hex(h.MrklRt)
+	w.U32(h.Timestamp)
+	w.Hex(h.DiffTarg)
+	w.U32(h.Nonce)
+}
+
+// Enc (Encode) returns the canonical encoding of the
+// block, which is the header followed by the
+// transactions.
+// Returns:
+// []byte the encoded block
+func (b *Block) Enc() []byte {
+	w := &enc.Wtr{}
+	b.Hdr.EncTo(w)
+	w.Cnt(uint64(len(b.Transactions)))
+	for _, t := range b.Transactions {
+		t.EncTo(w)
+	}
+	return w.Bytes()
 }
 
 // CalcMrklRt (CalculateMerkleRoot) calculates
@@ -181,15 +252,87 @@ func CalcMrklRt(txs []*tx.Transaction) string {
 	return hshs[0]
 }
 
+// MrklPrf (MerkleProof) returns the merkle branch of
+// a transaction: the hash it is paired with at each
+// level of the tree built by CalcMrklRt, from the leaves
+// up to the root.
+// Inputs:
+// txs	[]*tx.Transaction the transactions of a block
+// i	int the index of the transaction in txs
+// Returns:
+// []string	the branch, as hex strings
+// bool	False if i is not an index into txs
+func MrklPrf(txs []*tx.Transaction, i int) ([]string, bool) {
+	if i < 0 || i >= len(txs) {
+		return nil, false
+	}
+	hshs := make([]string, len(txs))
+	for j, t := range txs {
+		hshs[j] = t.Hash()
+	}
+	prf := make([]string, 0)
+	for len(hshs) > 1 {
+		if len(hshs)%2 != 0 {
+			hshs = append(hshs, hshs[len(hshs)-1])
+		}
+		prf = append(prf, hshs[i^1])
+		var nxt []string
+		for j := 0; j < len(hshs); j += 2 {
+			l, _ := hex.DecodeString(hshs[j])
+			r, _ := hex.DecodeString(hshs[j+1])
+			nxt = append(nxt, utils.Hash(append(l, r...)))
+		}
+		hshs, i = nxt, i/2
+	}
+	return prf, true
+}
+
+// ChkMrklPrf (CheckMerkleProof) checks that a merkle
+// branch (see MrklPrf) leads from the hash of a
+// transaction to the merkle root of the header, which
+// proves the transaction is on the header's block
+// without the rest of its transactions.
+// Inputs:
+// txHsh	string the hash of the transaction
+// i	int the index of the transaction on the block
+// prf	[]string the branch, as hex strings
+// Returns:
+// bool	True if the branch leads to the merkle root
+func (h *Header) ChkMrklPrf(txHsh string, i int, prf []string) bool {
+	// Every level halves the index, so a valid index
+	// runs out by the root
+	if i < 0 || i>>uint(len(prf)) != 0 {
+		return false
+	}
+	hsh := txHsh
+	for _, s := range prf {
+		l, err1 := hex.DecodeString(hsh)
+		r, err2 := hex.DecodeString(s)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if i%2 != 0 {
+			l, r = r, l
+		}
+		hsh, i = utils.Hash(append(l, r...)), i/2
+	}
+	return hsh == h.MrklRt
+}
+
 func (b *Block) String() string {
 	return fmt.Sprintf("%v", b.Hash())
 }
 
-// Hash returns the hash of a block.
+// Hash returns the hash of a block, which is the
+// sha256 of the canonical encoding of its header.
+// Blocks older than EncVer are hashed the legacy way.
 // Returns:
 // string	the hash of the block represented
 // as a hex string
 func (b *Block) Hash() string {
+	if b.Hdr.Ver >= EncVer {
+		return utils.Hash(b.Hdr.Enc())
+	}
 	pureData := []byte(fmt.Sprintf("%v", b.Hdr))
 	return utils.Hash(pureData)
 }
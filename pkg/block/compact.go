@@ -0,0 +1,108 @@
+package block
+
+import (
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/proto"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+// ShrtID (ShortID) returns the short id of a transaction
+// in a compact block: the first 6 bytes of the sha256 of
+// the block's hash, the compact block's nonce and the
+// transaction's hash, as a number. The nonce changes the
+// ids every time a block is sent, so a collision between
+// two transactions doesn't keep happening.
+// Inputs:
+// blkHsh string the hash of the block
+// nonce uint64 the nonce of the compact block
+// txHsh string the hash of the transaction
+// Returns:
+// uint64 the short id
+func ShrtID(blkHsh string, nonce uint64, txHsh string) uint64 {
+	b, _ := hex.DecodeString(blkHsh)
+	t, _ := hex.DecodeString(txHsh)
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], nonce)
+	h := sha256.Sum256(append(append(b, n[:]...), t...))
+	var id [8]byte
+	copy(id[2:], h[:6])
+	return binary.BigEndian.Uint64(id[:])
+}
+
+// Cmpct (Compact) returns the block in compact form: its
+// header, the coinbase and the transactions the receiver
+// likely doesn't have in full, and short ids (see ShrtID)
+// for the rest.
+// Inputs:
+// nonce uint64 salts the short ids
+// prefill func(*tx.Transaction) bool returns whether a
+// transaction should be sent in full
+// Returns:
+// *proto.CompactBlock the compact block
+func (b *Block) Cmpct(nonce uint64, prefill func(*tx.Transaction) bool) *proto.CompactBlock {
+	h := b.Hash()
+	cb := &proto.CompactBlock{Header: b.Hdr.Serialize(), Nonce: nonce}
+	for i, t := range b.Transactions {
+		if i == 0 || prefill(t) {
+			cb.Prefilled = append(cb.Prefilled, &proto.PrefilledTransaction{Index: uint32(i), Transaction: t.Serialize()})
+		} else {
+			cb.ShortIds = append(cb.ShortIds, ShrtID(h, nonce, t.Hash()))
+		}
+	}
+	return cb
+}
+
+// Rcnstr (Reconstruct) fills in the transactions of a
+// compact block, from its prefilled transactions and the
+// transactions the receiver has whose short ids match.
+// A short id that more than one of them match is left
+// missing.
+// Inputs:
+// cb *proto.CompactBlock the compact block
+// have []*tx.Transaction the transactions the receiver
+// has, such as its transaction pool
+// Returns:
+// []*tx.Transaction the transactions of the block, nil
+// where they are missing
+// []uint32 the indexes of the missing transactions
+// error if the compact block is malformed
+func Rcnstr(cb *proto.CompactBlock, have []*tx.Transaction) ([]*tx.Transaction, []uint32, error) {
+	if cb.Header == nil {
+		return nil, nil, errors.New("compact block has no header")
+	}
+	txs := make([]*tx.Transaction, len(cb.ShortIds)+len(cb.Prefilled))
+	for i, p := range cb.Prefilled {
+		if int(p.Index) >= len(txs) || p.Transaction == nil || (i > 0 && p.Index <= cb.Prefilled[i-1].Index) {
+			return nil, nil, errors.New("prefilled transactions are out of order or range")
+		}
+		txs[p.Index] = tx.Deserialize(p.Transaction)
+	}
+	h := (&Block{Hdr: DeserializeHdr(cb.Header)}).Hash()
+	ids := make(map[uint64]*tx.Transaction)
+	dup := make(map[uint64]bool)
+	for _, t := range have {
+		id := ShrtID(h, cb.Nonce, t.Hash())
+		if o, found := ids[id]; found && o.Hash() != t.Hash() {
+			dup[id] = true
+		}
+		ids[id] = t
+	}
+	var miss []uint32
+	j := 0
+	for i := range txs {
+		if txs[i] != nil {
+			continue
+		}
+		id := cb.ShortIds[j]
+		j++
+		if t, found := ids[id]; found && !dup[id] {
+			txs[i] = t
+		} else {
+			miss = append(miss, uint32(i))
+		}
+	}
+	return txs, miss, nil
+}
@@ -0,0 +1,93 @@
+package enc
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// Wtr (Writer) builds the canonical byte encoding of
+// blocks and transactions. The format is described in
+// docs/encoding.md. Every value has exactly one
+// encoding, so the encoding can be hashed.
+type Wtr struct {
+	b []byte
+}
+
+// U32 (Uint32) writes a uint32 as 4 little endian
+// bytes.
+func (w *Wtr) U32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	w.b = append(w.b, b[:]...)
+}
+
+// Cnt (Count) writes a count (the length of a list or
+// of bytes) as a compact size: values below 0xfd take
+// a single byte, larger values are a marker byte (0xfd,
+// 0xfe or 0xff) followed by 2, 4 or 8 little endian bytes.
+// The shortest form must be used.
+func (w *Wtr) Cnt(n uint64) {
+	switch {
+	case n < 0xfd:
+		w.b = append(w.b, byte(n))
+	case n <= 0xffff:
+		var b [2]byte
+		binary.LittleEndian.PutUint16(b[:], uint16(n))
+		w.b = append(append(w.b, 0xfd), b[:]...)
+	case n <= 0xffffffff:
+		w.b = append(w.b, 0xfe)
+		w.U32(uint32(n))
+	default:
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], n)
+		w.b = append(append(w.b, 0xff), b[:]...)
+	}
+}
+
+// Hex writes a hex string (a hash, a script, ...) as
+// the bytes it encodes, preceded by their count as a
+// compact size. Only lowercase hex (see IsHex) is
+// written this way, so each value has one encoding.
+// Anything else can't be on a valid block or
+// transaction, but so that it still never encodes like
+// a valid value, its text is written with the 8 byte
+// form of its count, which the shortest form never uses
+// for a count that small.
+func (w *Wtr) Hex(s string) {
+	if !IsHex(s) {
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(len(s)))
+		w.b = append(append(append(w.b, 0xff), b[:]...), s...)
+		return
+	}
+	b, _ := hex.DecodeString(s)
+	w.Cnt(uint64(len(b)))
+	w.b = append(w.b, b...)
+}
+
+// IsHex returns whether a string is canonical hex: an
+// even count of the digits 0-9 and a-f. hex.DecodeString
+// also takes upper case, which would let one value be
+// written more than one way.
+func IsHex(s string) bool {
+	if len(s)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Byts (Bytes) writes raw bytes with no length in
+// front of them.
+func (w *Wtr) Byts(b []byte) {
+	w.b = append(w.b, b...)
+}
+
+// Bytes returns everything written so far.
+func (w *Wtr) Bytes() []byte {
+	return w.b
+}
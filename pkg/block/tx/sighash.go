@@ -0,0 +1,176 @@
+package tx
+
+import (
+	"BrunoCoin/pkg/block/enc"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/utils"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Sighash flags decide which parts of a transaction
+// a signature covers. The flag is the last byte of the
+// signature.
+// SigHshAll covers every input and output.
+// SigHshNone covers every input and none of the outputs,
+// so anyone can decide where the money goes.
+// SigHshSingle covers every input and only the output with
+// the same index as the signed input.
+// SigHshACP (AnyoneCanPay) can be added to any of the other
+// flags, and makes the signature only cover its own input,
+// so others can add inputs to the transaction.
+const (
+	SigHshAll    byte = 0x01
+	SigHshNone   byte = 0x02
+	SigHshSingle byte = 0x03
+	SigHshACP    byte = 0x80
+)
+
+// SigHsh (SignatureHash) computes the hash that the
+// signature for an input has to sign. Unlocking scripts
+// are left out, since they hold the signatures. The input
+// being signed also covers the output it spends.
+// Transactions from EncVer on hash the canonical
+// encoding of those parts (see docs/encoding.md).
+// Inputs:
+// i int the index of the input being signed
+// o *txo.TransactionOutput the output that input i spends
+// ht byte the sighash flag
+// Returns:
+// string the hash as a hex string
+// error if the flag is unknown, i is out of range, or
+// SigHshSingle is used without a matching output
+func (t *Transaction) SigHsh(i int, o *txo.TransactionOutput, ht byte) (string, error) {
+	if i < 0 || i >= len(t.Inputs) {
+		return "", fmt.Errorf("input %v is out of range", i)
+	}
+	base := ht &^ SigHshACP
+	if base < SigHshAll || base > SigHshSingle {
+		return "", fmt.Errorf("unknown sighash flag %#x", ht)
+	}
+	if base == SigHshSingle && i >= len(t.Outputs) {
+		return "", errors.New("no output to match the input for SigHshSingle")
+	}
+	if t.Version >= EncVer {
+		return t.sigHshEnc(i, o, ht), nil
+	}
+	ins := make([]string, 0)
+	for j, inp := range t.Inputs {
+		if ht&SigHshACP != 0 && j != i {
+			continue
+		}
+		d := fmt.Sprintf("%v-%v-%v", inp.TransactionHash, inp.OutputIndex, inp.Amount)
+		if j == i {
+			d += "-" + o.Hash()
+		}
+		ins = append(ins, d)
+	}
+	outs := make([]string, 0)
+	switch base {
+	case SigHshAll:
+		for _, out := range t.Outputs {
+			outs = append(outs, out.Hash())
+		}
+	case SigHshSingle:
+		outs = append(outs, t.Outputs[i].Hash())
+	}
+	pureData := []byte(fmt.Sprintf("%v/%v/%v/%v/%v", t.Version, t.LockTime,
+		strings.Join(ins, "/"), strings.Join(outs, "/"), ht))
+	return utils.Hash(pureData), nil
+}
+
+// sigHshEnc (SignatureHashEncoded) is SigHsh for
+// transactions from EncVer on. The flag and index
+// have already been checked.
+func (t *Transaction) sigHshEnc(i int, o *txo.TransactionOutput, ht byte) string {
+	w := &enc.Wtr{}
+	w.U32(t.Version)
+	ins, pos := t.Inputs, i
+	if ht&SigHshACP != 0 {
+		ins, pos = ins[i:i+1], 0
+	}
+	w.Cnt(uint64(len(ins)))
+	for _, inp := range ins {
+		w.Hex(inp.TransactionHash)
+		w.U32(inp.OutputIndex)
+		w.U32(inp.Amount)
+	}
+	w.U32(uint32(pos))
+	o.EncTo(w)
+	switch ht &^ SigHshACP {
+	case SigHshAll:
+		w.Cnt(uint64(len(t.Outputs)))
+		for _, out := range t.Outputs {
+			out.EncTo(w)
+		}
+	case SigHshSingle:
+		w.Cnt(1)
+		t.Outputs[i].EncTo(w)
+	default:
+		w.Cnt(0)
+	}
+	w.U32(t.LockTime)
+	w.U32(uint32(ht))
+	return utils.Hash(w.Bytes())
+}
+
+// MkSig (MakeSignature) generates the unlocking script
+// (a.k.a. signature) for an input of the transaction.
+// The outputs (and, without SigHshACP, the other inputs)
+// should already be on the transaction, since changing
+// what the signature covers makes it invalid.
+// Inputs:
+// i int the index of the input
+// o *txo.TransactionOutput the output that input i spends
+// id id.ID the id of the person that owns o
+// ht byte the sighash flag
+// Returns:
+// string the signature with the flag appended as its last
+// byte, represented as a hex string
+// error if the signature could not be produced
+func (t *Transaction) MkSig(i int, o *txo.TransactionOutput, id id.ID, ht byte) (string, error) {
+	hsh, err := t.SigHsh(i, o, ht)
+	if err != nil {
+		return "", err
+	}
+	hB, err := hex.DecodeString(hsh)
+	if err != nil {
+		return "", err
+	}
+	sig, err := utils.Sign(id.GetPrivateKey(), hB)
+	if err != nil {
+		return "", err
+	}
+	return sig + hex.EncodeToString([]byte{ht}), nil
+}
+
+// IsUnlckd (IsUnlocked) checks that the unlocking script
+// of an input unlocks the output it spends, with the
+// signatures bound to this transaction.
+// Inputs:
+// i int the index of the input
+// o *txo.TransactionOutput the output that input i spends
+// Returns:
+// bool True if the input unlocks o
+func (t *Transaction) IsUnlckd(i int, o *txo.TransactionOutput) bool {
+	return t.UnlckErr(i, o) == nil
+}
+
+// UnlckErr (UnlockError) is IsUnlckd, but returns why
+// the input doesn't unlock o.
+// Returns:
+// error why the scripts failed, or nil if they didn't
+func (t *Transaction) UnlckErr(i int, o *txo.TransactionOutput) error {
+	if i < 0 || i >= len(t.Inputs) {
+		return fmt.Errorf("input %v is out of range", i)
+	}
+	return o.UnlckErr(t.Inputs[i].UnlockingScript, &txo.Spnd{
+		SigHsh: func(ht byte) (string, error) {
+			return t.SigHsh(i, o, ht)
+		},
+		LckTm: t.LockTime,
+	})
+}
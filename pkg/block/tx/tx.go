@@ -1,9 +1,11 @@
 package tx
 
 import (
+	"BrunoCoin/pkg/block/enc"
 	"BrunoCoin/pkg/block/tx/txi"
 	"BrunoCoin/pkg/block/tx/txo"
 	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
 	"BrunoCoin/pkg/utils"
 	"fmt"
 	"strconv"
@@ -25,13 +27,50 @@ type Transaction struct {
 	LockTime uint32
 }
 
+// EncVer (EncodingVersion) is the first transaction
+// version that is hashed and sized with the canonical
+// encoding. Older transactions keep their legacy hash,
+// so chains made before the encoding stay valid.
+const EncVer uint32 = 1
+
 // Sz (Size) returns the size of the
-// underlying protobuf transaction.
+// transaction.
 // Returns:
-// uint32	size in bytes of the underlying
-// protobuf transaction.
+// uint32	size in bytes of the canonical encoding,
+// or of the underlying protobuf transaction if the
+// transaction is older than EncVer.
 func (t *Transaction) Sz() uint32 {
-	return proto.SzOfTx(t.Serialize())
+	if t.Version < EncVer {
+		return proto.SzOfTx(t.Serialize())
+	}
+	return uint32(len(t.Enc()))
+}
+
+// Enc (Encode) returns the canonical encoding of the
+// transaction (see docs/encoding.md).
+// Returns:
+// []byte the encoded transaction
+func (t *Transaction) Enc() []byte {
+	w := &enc.Wtr{}
+	t.EncTo(w)
+	return w.Bytes()
+}
+
+// EncTo (EncodeTo) writes the canonical encoding of
+// the transaction.
+// Inputs:
+// w *enc.Wtr the writer to write to
+func (t *Transaction) EncTo(w *enc.Wtr) {
+	w.U32(t.Version)
+	w.Cnt(uint64(len(t.Inputs)))
+	for _, i := range t.Inputs {
+		i.EncTo(w)
+	}
+	w.Cnt(uint64(len(t.Outputs)))
+	for _, o := range t.Outputs {
+		o.EncTo(w)
+	}
+	w.U32(t.LockTime)
 }
 
 // SumInputs returns the sum of the inputs.
@@ -58,12 +97,17 @@ func (t *Transaction) SumOutputs() uint32 {
 	return r
 }
 
-// Hash returns the hash of the underlying
-// protobuf transaction.
+// Hash returns the hash of the transaction,
+// which is the sha256 of its canonical encoding.
+// Transactions older than EncVer are hashed the
+// legacy way.
 // Returns:
 // string	the hash of the transaction
 // represented as a hex string
 func (t *Transaction) Hash() string {
+	if t.Version >= EncVer {
+		return utils.Hash(t.Enc())
+	}
 	pureInputs := make([]string, 0)
 	for _, i := range t.Inputs {
 		pureInputs = append(pureInputs, i.Hash())
@@ -76,6 +120,29 @@ func (t *Transaction) Hash() string {
 	return utils.Hash(pureData)
 }
 
+// IsFinal returns whether the transaction's lock time
+// has passed, so that it can be on a block. A lock time
+// of 0 is always final. Otherwise the lock time has to
+// be less than the height of the block, or, for a
+// timestamp (see script.LckTmThrsh), less than the
+// median time past of the blocks before it.
+// Inputs:
+// h uint32 the height of the block (the genesis block
+// is at height 0)
+// tm uint32 the median time past of the previous block
+// Returns:
+// bool True if the transaction can be on the block
+func (t *Transaction) IsFinal(h uint32, tm uint32) bool {
+	if t.LockTime == 0 {
+		return true
+	}
+	lim := h
+	if t.LockTime >= script.LckTmThrsh {
+		lim = tm
+	}
+	return t.LockTime < lim
+}
+
 // IsCoinbase returns whether or not the
 // transaction is a coinbase transaction.
 // Returns:
@@ -1,6 +1,7 @@
 package txi
 
 import (
+	"BrunoCoin/pkg/block/enc"
 	"BrunoCoin/pkg/proto"
 	"BrunoCoin/pkg/utils"
 	"fmt"
@@ -47,6 +48,21 @@ func Deserialize(inp *proto.TransactionInput) *TransactionInput {
 	return ip
 }
 
+// EncTo (EncodeTo) writes the canonical encoding of
+// the input (see docs/encoding.md).
+// Inputs:
+// w *enc.Wtr the writer to write to
+func (txi *TransactionInput) EncTo(w *enc.Wtr) {
+	w.Hex(txi.TransactionHash)
+	w.U32(txi.OutputIndex)
+	w.Hex(txi.UnlockingScript)
+	w.U32(txi.Amount)
+}
+
+// Hash is the legacy hash of the input, which is only
+// used to hash transactions older than tx.EncVer.
+// Returns:
+// string the hash as a hex string
 func (txi *TransactionInput) Hash() string {
 	pureData := []byte(fmt.Sprintf("%v/%v/%v/%v", txi.TransactionHash, txi.OutputIndex, txi.UnlockingScript, txi.Amount))
 	return utils.Hash(pureData)
@@ -1,11 +1,10 @@
 package txo
 
 import (
-	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/block/enc"
 	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
 	"BrunoCoin/pkg/utils"
-	"crypto/ecdsa"
-	"encoding/hex"
 	"fmt"
 	"strconv"
 	"strings"
@@ -21,47 +20,38 @@ type TransactionOutput struct {
 	Liminal       bool
 }
 
+// SigHshr (SignatureHasher) returns the hash that a
+// signature with a particular sighash flag has to sign.
+// It is the same as script.SigHshr.
+type SigHshr = script.SigHshr
+
+// Spnd (Spend) is the input spending an output, as the
+// scripts see it. It is the same as script.Spnd.
+type Spnd = script.Spnd
+
 // IsUnlckd (IsUnlocked) tests whether an unlocking
-// script successfully unlocks a locking script
-// on the transaction output. It does this by using
-// a public key, a message, and a signature to verify
-// whether the signature is valid.
+// script successfully unlocks the locking script
+// on the transaction output, by running the two
+// scripts (see script.Run).
 // Inputs:
-// sig	string	signature a.k.a. unlocking script
-// represented as a hex string.
+// unlck	string	the unlocking script of the input
+// spending the output, represented as a hex string.
+// sp	*Spnd	makes the message (the signature hash of
+// the spending transaction) for a sighash flag, and has
+// the lock time of the spending transaction
 // Returns:
 // bool	true if the unlocking script actually
 // unlocks the locking script. False otherwise.
-func (o *TransactionOutput) IsUnlckd(sig string) bool {
-	pkb, err := hex.DecodeString(o.LockingScript)
-	if err != nil {
-		fmt.Printf("ERROR {IsUnlckd}: Locking"+
-			"script on the transaction {%v} couldn't"+
-			"decode properly.\n", o.LockingScript)
-		return false
-	}
-	pk, err := utils.Byt2PK(pkb)
-	if err != nil {
-		fmt.Printf("ERROR {IsUnlckd}:" +
-			" utils.Byt2PK errored.\n")
-		return false
-	}
-	h, err := hex.DecodeString(o.Hash())
-	if err != nil {
-		fmt.Printf("ERROR {IsUnlckd}: Could not"+
-			" properly decode the hash {%v} of the"+
-			" transaction output. ->"+
-			" TransactionOutput.Hash() errored.\n", o.Hash())
-		return false
-	}
-	sigB, err := hex.DecodeString(sig)
-	if err != nil {
-		fmt.Printf("ERROR {IsUnlckd}: Received "+
-			"an unlocking script {%v} that couldn't "+
-			"decode properly.\n", sig)
-		return false
-	}
-	return ecdsa.VerifyASN1(pk, h, sigB)
+func (o *TransactionOutput) IsUnlckd(unlck string, sp *Spnd) bool {
+	return o.UnlckErr(unlck, sp) == nil
+}
+
+// UnlckErr (UnlockError) is IsUnlckd, but returns why
+// the locking script was not unlocked.
+// Returns:
+// error why the scripts failed, or nil if they didn't
+func (o *TransactionOutput) UnlckErr(unlck string, sp *Spnd) error {
+	return script.Run(unlck, o.LockingScript, sp)
 }
 
 // PrsTXOLoc (ParseTransactionOutputLocator) parses
@@ -110,35 +100,6 @@ func MkTXOLoc(h string, i uint32) string {
 	return fmt.Sprintf("%v-%v", h, i)
 }
 
-// MkSig (MakeSignature) generates
-// an unlocking script (a.k.a. signature) for the
-// transaction output based on a private key.
-// Inputs:
-// id	id.ID	the id of the person wanting to
-// unlock the particular transaction output.
-// Returns:
-// string	The signature represented as a hex string.
-// error	Errors if the signature could not be
-// produced or there was a decoding error.
-func (o *TransactionOutput) MkSig(id id.ID) (string, error) {
-	sk := id.GetPrivateKey()
-	hB, err := hex.DecodeString(o.Hash())
-	if err != nil {
-		fmt.Printf("ERROR {TransactionOutput.MkSig}: "+
-			"The hash of the transaction output {%v} could "+
-			"not decode -> errored in "+
-			"TransactionOutput.Hash().\n", o.Hash())
-		return "", nil
-	}
-	sig, err := utils.Sign(sk, hB)
-	if err != nil {
-		fmt.Printf("ERROR {TransactionOutput.MkSig}: " +
-			"The signature could not be formed.\n")
-		return "", nil
-	}
-	return sig, nil
-}
-
 // Serialize serializes a transaction output
 // into a protobuf transaction output so it
 // can properly be sent over the network.
@@ -167,7 +128,19 @@ func Deserialize(ptx *proto.TransactionOutput) *TransactionOutput {
 	return op
 }
 
-// Hash hashes a transaction output.
+// EncTo (EncodeTo) writes the canonical encoding of
+// the output (see docs/encoding.md). Liminal is local
+// to the wallet and is not encoded.
+// Inputs:
+// w *enc.Wtr the writer to write to
+func (o *TransactionOutput) EncTo(w *enc.Wtr) {
+	w.U32(o.Amount)
+	w.Hex(o.LockingScript)
+}
+
+// Hash hashes a transaction output. It is the legacy
+// hash, which is only used by transactions older than
+// tx.EncVer.
 // Returns:
 // string	the hash of the transaction output
 // represented as a hex string.
@@ -5,27 +5,77 @@ import (
 	"BrunoCoin/pkg/block/tx"
 	"BrunoCoin/pkg/block/tx/txi"
 	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain/blockdb"
 	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
 	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
 	"fmt"
+	"math/big"
+	"sort"
 	"strings"
 	"sync"
 )
 
 // BlockchainNode represents a collection of information
 // relevant to one block in the chain.
-// Block is the particular block
+// Block is the particular block. Once a block is stored
+// in the block database, only its header is kept here,
+// and the whole block is read from the database when it
+// is needed (see Blockchain.blk).
 // PrevNode is the node that this block references before
 // it
-// utxo is a map of txo identifiers to transaction outputs
-// It represents all UTXO on the chain of this block up
-// until this block.
+// undo records the utxo the block spent and created,
+// so the block can be taken back out of the utxo set.
 // depth is how far the block is down in its chain.
+// work is the cumulative work of all blocks on the
+// chain up until (and including) this block.
 type BlockchainNode struct {
 	*block.Block
 	PrevNode *BlockchainNode
-	utxo     map[string]*txo.TransactionOutput
+	undo     *undo
 	depth    int
+	work     *big.Int
+}
+
+// ChnUpd (ChainUpdate) describes how the main chain
+// changed after a block was added.
+// Disconnected are the blocks that are no longer on
+// the main chain, starting from the old last block.
+// Connected are the blocks that became part of the
+// main chain, starting right after the fork point and
+// ending with the new last block. When a block simply
+// extends the main chain, it is the only connected
+// block and nothing is disconnected.
+type ChnUpd struct {
+	Disconnected []*block.Block
+	Connected    []*block.Block
+}
+
+// Unconfirmed returns the transactions that were on
+// the disconnected blocks but are not on any of the
+// connected blocks, meaning they are no longer on the
+// main chain. Coinbase transactions are left out, since
+// they can't exist outside of their block.
+// Returns:
+// []*tx.Transaction the transactions that became
+// unconfirmed, in the order they were mined
+func (u *ChnUpd) Unconfirmed() []*tx.Transaction {
+	conn := make(map[string]bool)
+	for _, b := range u.Connected {
+		for _, t := range b.Transactions {
+			conn[t.Hash()] = true
+		}
+	}
+	var txs []*tx.Transaction
+	for i := len(u.Disconnected) - 1; i >= 0; i-- {
+		for _, t := range u.Disconnected[i].Transactions {
+			if !t.IsCoinbase() && !conn[t.Hash()] {
+				txs = append(txs, t)
+			}
+		}
+	}
+	return txs
 }
 
 // Blockchain not only stores the main blockchain, but
@@ -33,34 +83,69 @@ type BlockchainNode struct {
 // like structure using a map.
 // Addr is the address of the node storing the blockchain.
 // blocks are all blocks (forked or not) stored in a tree
-// using a map
+// using a map. It is an index of the headers; the blocks
+// themselves are in db.
 // LastBlock is the last block of the main chain
+// utxo is a map of txo identifiers to transaction outputs.
+// It represents all UTXO on the main chain up until
+// LastBlock. The utxo of forks is found from it using
+// the undo records of each block.
+// txIdx (transactionIndex) maps the hash of every
+// transaction on the main chain to where it is (see
+// FndTx)
+// dataIdx (dataIndex) maps every payload on the main
+// chain to where it is, first block first (see FndData)
+// db is the storage backend that every added block
+// is written to and read from
+// conf is the configuration of the blockchain
 type Blockchain struct {
 	Addr      string
+	conf      *Config
 	blocks    map[string]*BlockchainNode
 	LastBlock *BlockchainNode
+	utxo      utxoMap
+	txIdx     map[string]txLoc
+	dataIdx   map[string][]txLoc
+	db        blockdb.BlockDb
 	sync.Mutex
 }
 
 // New creates the initial blockchain with 1 starting block,
 // which is the GENESIS_BLOCK. This block is static is
 // hardcoded into every blockchain as the first block.
+// If the block database already holds blocks (the node
+// was restarted), they are added back on top of the
+// genesis block, restoring the main chain and all forks.
 // Inputs:
 // conf *Config the configuration for the blockchain.
 func New(conf *Config) *Blockchain {
 	genBlock := GenesisBlock(conf)
-	genTx := genBlock.Transactions[0]
-	genTxKey := fmt.Sprintf("%v-%v", genTx.Hash(), 0)
+	utxo := make(utxoMap)
 	GenesisBlock := &BlockchainNode{
 		Block:    genBlock,
 		PrevNode: nil,
-		utxo:     map[string]*txo.TransactionOutput{genTxKey: genTx.Outputs[0]},
+		undo:     connect(utxo, genBlock),
 		depth:    0,
+		work:     utils.CalcWork(genBlock.Hdr.DiffTarg),
 	}
-	return &Blockchain{
+	db, err := blockdb.New(conf.EphDb, conf.DbPath)
+	if err != nil {
+		panic(fmt.Sprintf("ERROR {blockchain.New}: could not open block database {%v}: %v", conf.DbPath, err))
+	}
+	bc := &Blockchain{
 		blocks:    map[string]*BlockchainNode{GenesisBlock.Hash(): GenesisBlock},
 		LastBlock: GenesisBlock,
+		utxo:      utxo,
+		txIdx:     make(map[string]txLoc),
+		dataIdx:   make(map[string][]txLoc),
+		db:        db,
+		conf:      conf,
+	}
+	bc.index(GenesisBlock, genBlock)
+	for _, b := range db.List() {
+		bc.add(b, false)
 	}
+	return bc
 }
 
 // SetAddr sets the address of the node storing the
@@ -74,55 +159,156 @@ func (bc *Blockchain) SetAddr(a string) {
 }
 
 // Add adds a block to the blockchain in the correct
-// spot.
+// spot and writes it to the block database. The main
+// chain is always the chain with the most cumulative
+// work, so if the block makes a fork heavier than the
+// current main chain, the fork becomes the main chain.
+// Data outputs (see script.Data) never become utxo.
 // Inputs:
 // b *block.Block the block to be added
-func (bc *Blockchain) Add(b *block.Block) {
+// Returns:
+// *ChnUpd how the main chain changed, or nil if the
+// main chain stayed the same
+func (bc *Blockchain) Add(b *block.Block) *ChnUpd {
 	bc.Lock()
+	defer bc.Unlock()
+	_, upd := bc.add(b, true)
+	return upd
+}
 
-	newUTXO := make(map[string]*txo.TransactionOutput)
-
-	if len(b.Transactions) == 0 || b == nil {
-		return
+// add links a block into the tree of blocks. It is also
+// used to replay the database when the chain is loaded,
+// in which case the block is already stored.
+// Inputs:
+// b *block.Block the block to be added
+// put bool True if the block has to be written to the
+// block database first. If it can't be, it isn't added.
+// Returns:
+// *BlockchainNode the node for the new block, or nil
+// if the block was empty, already known or not stored
+// *ChnUpd how the main chain changed, or nil if the
+// main chain stayed the same
+func (bc *Blockchain) add(b *block.Block, put bool) (*BlockchainNode, *ChnUpd) {
+	if b == nil || len(b.Transactions) == 0 {
+		return nil, nil
 	}
 
-	prevNode := bc.blocks[b.Hdr.PrvBlkHsh]
-
-	for k, v := range prevNode.utxo {
-		newUTXO[k] = v
+	if _, found := bc.blocks[b.Hash()]; found {
+		return nil, nil
 	}
 
-	for x := range b.Transactions {
-		for y := range b.Transactions[x].Inputs {
-			index := txo.MkTXOLoc(b.Transactions[x].Inputs[y].TransactionHash,
-				b.Transactions[x].Inputs[y].OutputIndex)
-			delete(newUTXO, index)
-		}
+	prevNode, found := bc.blocks[b.Hdr.PrvBlkHsh]
+	if !found {
+		utils.Debug.Printf("%v can't add orphan %v", utils.FmtAddr(bc.Addr), b.NameTag())
+		return nil, nil
 	}
 
-	for x := range b.Transactions {
-		for y := range b.Transactions[x].Outputs {
-			index := txo.MkTXOLoc(b.Transactions[x].Hash(), uint32(y))
-			newUTXO[index] = b.Transactions[x].Outputs[y]
+	if put {
+		if err := bc.db.Put(b); err != nil {
+			utils.Err.Printf("%v could not store %v: %v", utils.FmtAddr(bc.Addr), b.NameTag(), err)
+			return nil, nil
 		}
 	}
 
 	newNode := &BlockchainNode{
-		b,
+		&block.Block{Hdr: b.Hdr},
 		prevNode,
-		newUTXO,
+		nil,
 		prevNode.depth + 1,
+		new(big.Int).Add(prevNode.work, utils.CalcWork(b.Hdr.DiffTarg)),
 	}
 
-	if bc.IsEndMainChain(b) {
+	bc.blocks[newNode.Hash()] = newNode
+
+	utils.Debug.Printf("Address " + utils.FmtAddr(bc.Addr) + " -> " + b.NameTag())
+
+	heavier := newNode.work.Cmp(bc.LastBlock.work) > 0
+	var upd *ChnUpd
+	if heavier && prevNode == bc.LastBlock {
+		// Simply extending the main chain, so the
+		// main utxo set is updated in place.
+		newNode.undo = connect(bc.utxo, b)
+		bc.index(newNode, b)
 		bc.LastBlock = newNode
+		upd = &ChnUpd{Connected: []*block.Block{b}}
+	} else {
+		newNode.undo = connect(bc.viewAt(prevNode), b)
+		if heavier {
+			upd = bc.reorg(newNode)
+		}
 	}
 
-	bc.blocks[newNode.Hash()] = newNode
+	return newNode, upd
+}
 
-	utils.Debug.Printf("Address " + utils.FmtAddr(bc.Addr) + " -> " + b.NameTag())
+// reorg (reorganize) makes a new node the last block
+// of the main chain. The blocks leaving the main chain
+// are disconnected from the utxo set using their undo
+// records, and then the new branch is connected. The
+// transaction and data indexes follow along.
+// Inputs:
+// newTip *BlockchainNode the new last block
+// Returns:
+// *ChnUpd the blocks that left and joined the main chain
+func (bc *Blockchain) reorg(newTip *BlockchainNode) *ChnUpd {
+	upd := &ChnUpd{}
+	old, nw := bc.LastBlock, newTip
+	var dis, conn []*BlockchainNode
+	for old.depth > nw.depth {
+		dis = append(dis, old)
+		old = old.PrevNode
+	}
+	for nw.depth > old.depth {
+		conn = append(conn, nw)
+		nw = nw.PrevNode
+	}
+	for old != nw {
+		dis = append(dis, old)
+		conn = append(conn, nw)
+		old, nw = old.PrevNode, nw.PrevNode
+	}
+	for _, d := range dis {
+		b := bc.blk(d)
+		disconnect(bc.utxo, d.undo)
+		bc.unindex(d, b)
+		upd.Disconnected = append(upd.Disconnected, b)
+	}
+	for i := len(conn) - 1; i >= 0; i-- {
+		b := bc.blk(conn[i])
+		connect(bc.utxo, b)
+		bc.index(conn[i], b)
+		upd.Connected = append(upd.Connected, b)
+	}
+	if len(upd.Disconnected) > 0 {
+		utils.Debug.Printf("%v reorganized: %v blocks disconnected, %v connected, new top %v",
+			utils.FmtAddr(bc.Addr), len(upd.Disconnected), len(upd.Connected), newTip.NameTag())
+	}
+	bc.LastBlock = newTip
+	return upd
+}
 
-	bc.Unlock()
+// blk (block) returns the whole block of a node, read
+// from the block database. Blocks that aren't stored
+// there (the genesis block, and the headers of a light
+// node) are kept whole in their node. The caller must
+// hold the blockchain's lock.
+// Inputs:
+// n *BlockchainNode the node of the block
+// Returns:
+// *block.Block the block
+func (bc *Blockchain) blk(n *BlockchainNode) *block.Block {
+	if b := bc.db.Get(n.Hash()); b != nil {
+		return b
+	}
+	return n.Block
+}
+
+// Close closes the block database. The blockchain
+// should not be added to afterwards.
+func (bc *Blockchain) Close() error {
+	bc.Lock()
+	defer bc.Unlock()
+	return bc.db.Close()
 }
 
 // Length returns the count of blocks on the
@@ -141,11 +327,28 @@ func (bc *Blockchain) Length() int {
 // hash string the hash of the block wanting to
 // be returned
 // Returns:
-// *block.Block the block corresponding to the hash
+// *block.Block the block corresponding to the hash,
+// or nil if there is no such block
 func (bc *Blockchain) Get(hash string) *block.Block {
 	bc.Lock()
 	defer bc.Unlock()
-	return bc.blocks[hash].Block
+	if bc.blocks[hash] == nil {
+		return nil
+	}
+	return bc.blk(bc.blocks[hash])
+}
+
+// Has returns whether a block is on the blockchain
+// (either on the main chain or on a fork).
+// Inputs:
+// hash string the hash of the block
+// Returns:
+// bool True if the block is on the blockchain
+func (bc *Blockchain) Has(hash string) bool {
+	bc.Lock()
+	defer bc.Unlock()
+	_, found := bc.blocks[hash]
+	return found
 }
 
 // IndexOf (GetIndex) gets the index in the blockchain
@@ -164,13 +367,43 @@ func (bc *Blockchain) IndexOf(hash string) int {
 	return bc.blocks[hash].depth
 }
 
+// MedTmPst (MedianTimePast) returns the median of the
+// timestamps of a block and the 10 blocks before it
+// (or fewer, close to the genesis block). A block on
+// top of it must not have an earlier timestamp.
+// Inputs:
+// hash string the hash of the block
+// Returns:
+// uint32 the median timestamp
+// bool False if the block is unknown
+func (bc *Blockchain) MedTmPst(hash string) (uint32, bool) {
+	bc.Lock()
+	defer bc.Unlock()
+	b, found := bc.blocks[hash]
+	if !found {
+		return 0, false
+	}
+	return medTmPst(b), true
+}
+
+// medTmPst (medianTimePast) is MedTmPst for a node in
+// the tree. The caller must hold the blockchain's lock.
+func medTmPst(b *BlockchainNode) uint32 {
+	var tms []uint32
+	for ; b != nil && len(tms) < 11; b = b.PrevNode {
+		tms = append(tms, b.Hdr.Timestamp)
+	}
+	sort.Slice(tms, func(i, j int) bool { return tms[i] < tms[j] })
+	return tms[len(tms)/2]
+}
+
 // GetLastBlock is a getter for LastBlock
 // Returns:
 // *block.Block the last block of the main chain.
 func (bc *Blockchain) GetLastBlock() *block.Block {
 	bc.Lock()
 	defer bc.Unlock()
-	return bc.LastBlock.Block
+	return bc.blk(bc.LastBlock)
 }
 
 // List returns all blocks on the main chain in order.
@@ -182,7 +415,7 @@ func (bc *Blockchain) List() []*block.Block {
 	b := bc.LastBlock
 	slice := make([]*block.Block, 0)
 	for ct := bc.LastBlock.depth + 1; ct > 0; ct-- {
-		slice = append([]*block.Block{b.Block}, slice...)
+		slice = append([]*block.Block{bc.blk(b)}, slice...)
 		b = b.PrevNode
 	}
 	return slice
@@ -203,7 +436,7 @@ func (bc *Blockchain) Slice(s int, e int) []*block.Block {
 	slice := make([]*block.Block, 0)
 	for b.depth >= s {
 		if b.depth < e {
-			slice = append([]*block.Block{b.Block}, slice...)
+			slice = append([]*block.Block{bc.blk(b)}, slice...)
 		}
 		if b.PrevNode == nil {
 			break
@@ -229,16 +462,30 @@ func (bc *Blockchain) GetUTXO(txi *txi.TransactionInput) *txo.TransactionOutput
 	bc.Lock()
 	defer bc.Unlock()
 	key := txo.MkTXOLoc(txi.TransactionHash, txi.OutputIndex)
-	utxo, _ := bc.LastBlock.utxo[key]
-	return utxo
+	return bc.utxo[key]
+}
+
+// RlsUTXO (ReleaseUTXO) marks the utxo that an
+// input spends as no longer liminal, so the wallet
+// can use it again. It does nothing if the utxo
+// was spent.
+// Inputs:
+// txi *txi.TransactionInput the input spending the utxo
+func (bc *Blockchain) RlsUTXO(txi *txi.TransactionInput) {
+	bc.Lock()
+	defer bc.Unlock()
+	if u, found := bc.utxo[txo.MkTXOLoc(txi.TransactionHash, txi.OutputIndex)]; found {
+		u.Liminal = false
+	}
 }
 
 func (bc *Blockchain) GetUTXOLen(pk string) int {
 	bc.Lock()
 	defer bc.Unlock()
 	ct := 0
-	for _, v := range bc.LastBlock.utxo {
-		if v.LockingScript == pk {
+	scrs := script.PKScrs(pk)
+	for _, v := range bc.utxo {
+		if scrs[v.LockingScript] {
 			ct++
 		}
 	}
@@ -257,14 +504,16 @@ func (bc *Blockchain) IsInvalidInput(txi *txi.TransactionInput) bool {
 	bc.Lock()
 	defer bc.Unlock()
 	key := txo.MkTXOLoc(txi.TransactionHash, txi.OutputIndex)
-	_, found := bc.LastBlock.utxo[key]
+	_, found := bc.utxo[key]
 	return !found
 }
 
 // ChkChainsUTXO (checkchainsutxo) checks to see that
 // the transactions all reference valid UTXO on whatever
 // forked chain that the transactions belonging to a block
-// are being added to.
+// are being added to, and that the amount on each input
+// is the amount of the UTXO it references. No UTXO may
+// be spent by more than one input.
 // Inputs:
 // txs []*tx.Transaction the txs on a new block wanting to
 // be added to the chain.
@@ -274,22 +523,56 @@ func (bc *Blockchain) IsInvalidInput(txi *txi.TransactionInput) bool {
 // bool True if each input from the txs reference a valid
 // utxo
 func (bc *Blockchain) ChkChainsUTXO(txs []*tx.Transaction, prevHash string) bool {
-	var keys []string
+	return bc.UTXOErr(txs, prevHash) == nil
+}
+
+// UTXOErr (UTXOError) is ChkChainsUTXO, but returns
+// why the transactions can't spend their utxo. Each
+// transaction also has to be final at the height of the
+// block (see tx.Transaction.IsFinal), and each input
+// has to unlock the utxo it spends. Since the lock time
+// of a transaction can't be after the height or median
+// time past of its block, neither can the lock times its
+// scripts check (see script.OpChkLckTmVfy).
+// Returns:
+// error a valerr.MissingInput, valerr.DblSpnd, valerr.BadAmt,
+// valerr.NonFinal, valerr.BadSig or valerr.UnknownPrv error,
+// or nil if each input unlocks a valid utxo with the same
+// amount as the input, and no utxo is spent twice
+func (bc *Blockchain) UTXOErr(txs []*tx.Transaction, prevHash string) error {
+	bc.Lock()
+	defer bc.Unlock()
 	lastBlock, found := bc.blocks[prevHash]
-	// If not found, this is an orphan, try to compare it against the last node
+	// Orphans can't be checked until their previous block arrives
 	if !found {
-		lastBlock = bc.LastBlock
+		return valerr.New(valerr.UnknownPrv, "previous block %v is unknown", prevHash)
 	}
+	utxo := bc.viewAt(lastBlock)
+	h, mtp := uint32(lastBlock.depth+1), medTmPst(lastBlock)
+	spnt := make(map[string]string)
 	for _, t := range txs {
-		for _, txii := range t.Inputs {
+		if !t.IsFinal(h, mtp) {
+			return valerr.New(valerr.NonFinal, "%v is locked until %v", t.NameTag(), t.LockTime)
+		}
+		for i, txii := range t.Inputs {
 			key := txo.MkTXOLoc(txii.TransactionHash, txii.OutputIndex)
-			if _, found := lastBlock.utxo[key]; !found {
-				return false
+			if by, found := spnt[key]; found {
+				return valerr.New(valerr.DblSpnd, "%v spends utxo %v already spent by %v", t.NameTag(), key, by)
+			}
+			spnt[key] = t.NameTag()
+			o := utxo.get(key)
+			if o == nil {
+				return valerr.New(valerr.MissingInput, "%v spends missing utxo %v", t.NameTag(), key)
+			}
+			if o.Amount != txii.Amount {
+				return valerr.New(valerr.BadAmt, "%v claims %v for utxo %v worth %v", t.NameTag(), txii.Amount, key, o.Amount)
+			}
+			if err := t.UnlckErr(i, o); err != nil {
+				return valerr.New(valerr.BadSig, "%v input %v does not unlock utxo %v: %v", t.NameTag(), i, key, err)
 			}
-			keys = append(keys, key)
 		}
 	}
-	return true
+	return nil
 }
 
 // UTXOInfo holds the information about a utxo
@@ -325,11 +608,87 @@ type UTXOInfo struct {
 // false otherwise.
 
 func (bc *Blockchain) GetUTXOForAmt(amt uint32, pubKey string) ([]*UTXOInfo, uint32, bool) {
+	scrs := script.PKScrs(pubKey)
+	return bc.utxoFor(amt, func(lck string) bool {
+		return scrs[lck]
+	})
+}
+
+// GetUTXOForScr (GetUTXOForScript) is GetUTXOForAmt
+// for utxo locked by a particular locking script,
+// such as a multisig script.
+// Inputs:
+// amt uint32 the amount of money needed
+// lck string the locking script as a hex string
+func (bc *Blockchain) GetUTXOForScr(amt uint32, lck string) ([]*UTXOInfo, uint32, bool) {
+	return bc.utxoFor(amt, func(l string) bool {
+		return l == lck
+	})
+}
+
+// UTXOForScr (UTXOForScript) gets all of the utxo
+// locked by a particular locking script that isn't
+// liminal. Nothing is marked, so it has to be reserved
+// (see RsrvUTXO) before it is spent. This is used to
+// spend outputs that have to be spent whole, such as a
+// hash time locked contract.
+// Inputs:
+// lck string the locking script as a hex string
+// Returns:
+// []*UTXOInfo the utxo information
+// uint32 the total amount of the utxo
+func (bc *Blockchain) UTXOForScr(lck string) ([]*UTXOInfo, uint32) {
+	bc.Lock()
+	defer bc.Unlock()
+
+	var UTXOInfos []*UTXOInfo
+	var amt uint32
+	for i, Txo := range bc.utxo {
+		if Txo.Liminal || Txo.LockingScript != lck {
+			continue
+		}
+		tHash, index := txo.PrsTXOLoc(i)
+		UTXOInfos = append(UTXOInfos, &UTXOInfo{
+			TxHsh:  tHash,
+			OutIdx: index,
+			UTXO:   Txo,
+			Amt:    Txo.Amount,
+		})
+		amt += Txo.Amount
+	}
+	return UTXOInfos, amt
+}
+
+// RsrvUTXO (ReserveUTXO) marks utxo liminal, so that
+// nothing else spends it (see RlsUTXO). Either all of
+// it is marked, or, if any of it was spent or already
+// liminal, none of it.
+// Inputs:
+// us []*UTXOInfo the utxo
+// Returns:
+// bool True if the utxo was reserved
+func (bc *Blockchain) RsrvUTXO(us []*UTXOInfo) bool {
+	bc.Lock()
+	defer bc.Unlock()
+	for _, u := range us {
+		if o, found := bc.utxo[txo.MkTXOLoc(u.TxHsh, u.OutIdx)]; !found || o.Liminal {
+			return false
+		}
+	}
+	for _, u := range us {
+		bc.utxo[txo.MkTXOLoc(u.TxHsh, u.OutIdx)].Liminal = true
+	}
+	return true
+}
+
+// utxoFor gets enough utxo with a locking script that
+// matches for the amount, and marks it liminal.
+func (bc *Blockchain) utxoFor(amt uint32, match func(lck string) bool) ([]*UTXOInfo, uint32, bool) {
 	bc.Lock()
 	defer bc.Unlock()
 
 	var UTXOInfos []*UTXOInfo
-	prevUTXO := bc.LastBlock.utxo
+	prevUTXO := bc.utxo
 
 	amtN := amt
 
@@ -341,7 +700,7 @@ func (bc *Blockchain) GetUTXOForAmt(amt uint32, pubKey string) ([]*UTXOInfo, uin
 		if Txo.Liminal {
 			continue
 		}
-		if Txo.LockingScript == pubKey {
+		if match(Txo.LockingScript) {
 			tHash, index := txo.PrsTXOLoc(i)
 
 			newUTXOInfo := &UTXOInfo{
@@ -351,7 +710,7 @@ func (bc *Blockchain) GetUTXOForAmt(amt uint32, pubKey string) ([]*UTXOInfo, uin
 				Amt:    Txo.Amount,
 			}
 
-			bc.LastBlock.utxo[i].Liminal = true
+			bc.utxo[i].Liminal = true
 
 			UTXOInfos = append(UTXOInfos, newUTXOInfo)
 
@@ -363,8 +722,8 @@ func (bc *Blockchain) GetUTXOForAmt(amt uint32, pubKey string) ([]*UTXOInfo, uin
 		}
 	}
 
-	for _, Txo := range bc.LastBlock.utxo {
-		Txo.Liminal = false
+	for _, u := range UTXOInfos {
+		u.UTXO.Liminal = false
 	}
 
 	return UTXOInfos, 0, false
@@ -404,9 +763,12 @@ func GenesisBlock(conf *Config) *block.Block {
 // Returns:
 // uint32 the balance that the person has
 func (bc *Blockchain) GetBalance(pk string) uint32 {
+	bc.Lock()
+	defer bc.Unlock()
 	var bal uint32 = 0
-	for _, v := range bc.LastBlock.utxo {
-		if v.LockingScript == pk {
+	scrs := script.PKScrs(pk)
+	for _, v := range bc.utxo {
+		if scrs[v.LockingScript] {
 			bal += v.Amount
 		}
 	}
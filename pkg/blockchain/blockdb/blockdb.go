@@ -0,0 +1,30 @@
+package blockdb
+
+import "BrunoCoin/pkg/block"
+
+// BlockDb is the storage backend for the blockchain.
+// Every block that is added to the blockchain (on the
+// main chain or on a fork) is handed to Put, and List
+// returns all stored blocks in the order they were put,
+// so that a blockchain can be rebuilt block by block
+// when a node restarts. The blockchain itself only keeps
+// the headers of stored blocks, and reads the blocks
+// through Get.
+type BlockDb interface {
+	Put(*block.Block) error
+	Get(string) *block.Block
+	List() []*block.Block
+	Len() int
+	Close() error
+}
+
+// New creates a block database. If eph is true, the
+// database only lives in memory. Otherwise, blocks are
+// kept in an append-only file at path, which is created
+// if it does not exist and reloaded if it does.
+func New(eph bool, path string) (BlockDb, error) {
+	if eph {
+		return &EphemeralBlockDb{blocks: make(map[string]*block.Block)}, nil
+	}
+	return OpenFileBlockDb(path)
+}
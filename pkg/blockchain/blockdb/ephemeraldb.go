@@ -0,0 +1,49 @@
+package blockdb
+
+import (
+	"BrunoCoin/pkg/block"
+	"sync"
+)
+
+// EphemeralBlockDb keeps blocks in memory only,
+// so nothing survives a restart of the node.
+type EphemeralBlockDb struct {
+	blocks map[string]*block.Block
+	order  []*block.Block
+	sync.Mutex
+}
+
+func (db *EphemeralBlockDb) Put(b *block.Block) error {
+	db.Lock()
+	defer db.Unlock()
+	if _, ok := db.blocks[b.Hash()]; ok {
+		return nil
+	}
+	db.blocks[b.Hash()] = b
+	db.order = append(db.order, b)
+	return nil
+}
+
+func (db *EphemeralBlockDb) Get(hash string) *block.Block {
+	db.Lock()
+	defer db.Unlock()
+	return db.blocks[hash]
+}
+
+func (db *EphemeralBlockDb) List() []*block.Block {
+	db.Lock()
+	defer db.Unlock()
+	blocks := make([]*block.Block, len(db.order))
+	copy(blocks, db.order)
+	return blocks
+}
+
+func (db *EphemeralBlockDb) Len() int {
+	db.Lock()
+	defer db.Unlock()
+	return len(db.order)
+}
+
+func (db *EphemeralBlockDb) Close() error {
+	return nil
+}
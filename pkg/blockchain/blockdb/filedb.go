@@ -0,0 +1,258 @@
+package blockdb
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// recHdrSz is the size of the header in front of
+// every record: the length of the payload followed
+// by the CRC-32 (IEEE) checksum of the payload, both
+// as little endian uint32s.
+const recHdrSz = 8
+
+// mxRecSz bounds the payload size of a single record
+// so a corrupted length field can't make us allocate
+// an absurd amount of memory while loading.
+const mxRecSz = 64 << 20
+
+// cacheSz (cacheSize) is how many of the blocks last
+// written or read are kept in memory, so that the blocks
+// at the top of the chain, which are read the most, don't
+// have to be read from the file each time.
+const cacheSz = 16
+
+// FileBlockDb stores blocks in an append-only file.
+// Each record is a header (length, checksum) and a
+// protobuf serialized block. A record is only
+// considered written once it is fully on disk with a
+// matching checksum, so if the node crashes partway
+// through a write, the torn record at the end of the
+// file is discarded (and truncated away) the next time
+// the file is opened, leaving every earlier block intact.
+// Only where each block's record is kept in memory, and
+// blocks are read from the file when they are asked for.
+// offs (offsets) maps the hash of every block to the
+// offset of its record
+// order holds the hashes of the blocks in the order they
+// were put
+// cache holds the last blocks written or read, and cchd
+// (cached) their hashes, oldest first
+type FileBlockDb struct {
+	f     *os.File
+	off   int64
+	offs  map[string]int64
+	order []string
+	cache map[string]*block.Block
+	cchd  []string
+	sync.Mutex
+}
+
+// OpenFileBlockDb opens (or creates) the block file at
+// path and loads every complete record in it.
+func OpenFileBlockDb(path string) (*FileBlockDb, error) {
+	if path == "" {
+		return nil, errors.New("no path given for block file")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	db := &FileBlockDb{f: f, offs: make(map[string]int64), cache: make(map[string]*block.Block)}
+	good, err := db.load()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	// Drop whatever follows the last complete record,
+	// which can only be the remains of an interrupted write.
+	if info, err := f.Stat(); err == nil && info.Size() > good {
+		utils.Debug.Printf("block file %v has %v trailing bytes from an interrupted write, truncating",
+			path, info.Size()-good)
+		if err := f.Truncate(good); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+	}
+	if _, err := f.Seek(good, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	db.off = good
+	return db, nil
+}
+
+// load reads records from the start of the file until
+// the end or the first incomplete/corrupt record, and
+// notes where each block is.
+// Returns:
+// int64 the offset right after the last good record
+func (db *FileBlockDb) load() (int64, error) {
+	var off int64
+	for {
+		b, sz, err := db.read(off)
+		if err != nil {
+			return off, nil
+		}
+		if _, ok := db.offs[b.Hash()]; !ok {
+			db.offs[b.Hash()] = off
+			db.order = append(db.order, b.Hash())
+		}
+		off += int64(recHdrSz) + int64(sz)
+	}
+}
+
+// read reads the record at an offset of the file.
+// Returns:
+// *block.Block the block in the record
+// uint32 the size of the record's payload
+// error if the record is incomplete or corrupt
+func (db *FileBlockDb) read(off int64) (*block.Block, uint32, error) {
+	hdr := make([]byte, recHdrSz)
+	if _, err := db.f.ReadAt(hdr, off); err != nil {
+		return nil, 0, err
+	}
+	sz := binary.LittleEndian.Uint32(hdr[:4])
+	sum := binary.LittleEndian.Uint32(hdr[4:])
+	if sz > mxRecSz {
+		return nil, 0, fmt.Errorf("record of %v bytes is over the limit", sz)
+	}
+	data := make([]byte, sz)
+	if _, err := db.f.ReadAt(data, off+recHdrSz); err != nil {
+		return nil, 0, err
+	}
+	if crc32.ChecksumIEEE(data) != sum {
+		return nil, 0, errors.New("record does not match its checksum")
+	}
+	pb := &proto.Block{}
+	if err := protobuf.Unmarshal(data, pb); err != nil {
+		return nil, 0, err
+	}
+	if pb.Header == nil {
+		return nil, 0, errors.New("record has no header")
+	}
+	return block.Deserialize(pb), sz, nil
+}
+
+// cch (cache) keeps a block in memory, dropping the
+// oldest cached block if the cache is full. The caller
+// must hold the lock.
+func (db *FileBlockDb) cch(h string, b *block.Block) {
+	if _, ok := db.cache[h]; ok {
+		return
+	}
+	if len(db.cchd) >= cacheSz {
+		delete(db.cache, db.cchd[0])
+		db.cchd = db.cchd[1:]
+	}
+	db.cache[h] = b
+	db.cchd = append(db.cchd, h)
+}
+
+// Put appends the block to the file and syncs it to
+// disk before returning.
+func (db *FileBlockDb) Put(b *block.Block) error {
+	db.Lock()
+	defer db.Unlock()
+	if _, ok := db.offs[b.Hash()]; ok {
+		return nil
+	}
+	data, err := protobuf.Marshal(b.Serialize())
+	if err != nil {
+		return err
+	}
+	rec := make([]byte, recHdrSz+len(data))
+	binary.LittleEndian.PutUint32(rec[:4], uint32(len(data)))
+	binary.LittleEndian.PutUint32(rec[4:recHdrSz], crc32.ChecksumIEEE(data))
+	copy(rec[recHdrSz:], data)
+	if _, err := db.f.Write(rec); err != nil {
+		db.rollback()
+		return err
+	}
+	if err := db.f.Sync(); err != nil {
+		db.rollback()
+		return err
+	}
+	db.offs[b.Hash()] = db.off
+	db.off += int64(len(rec))
+	db.order = append(db.order, b.Hash())
+	db.cch(b.Hash(), b)
+	return nil
+}
+
+// rollback cuts off a record that failed to be written,
+// so that the next record is appended right after the
+// last good one instead of after the partial write.
+func (db *FileBlockDb) rollback() {
+	if err := db.f.Truncate(db.off); err != nil {
+		utils.Err.Printf("could not truncate block file after failed write: %v", err)
+	}
+	if _, err := db.f.Seek(db.off, io.SeekStart); err != nil {
+		utils.Err.Printf("could not seek block file after failed write: %v", err)
+	}
+}
+
+// Get reads a block from the file, unless it is cached.
+// Returns:
+// *block.Block the block, or nil if it isn't stored or
+// can't be read
+func (db *FileBlockDb) Get(hash string) *block.Block {
+	db.Lock()
+	defer db.Unlock()
+	if b, ok := db.cache[hash]; ok {
+		return b
+	}
+	off, ok := db.offs[hash]
+	if !ok {
+		return nil
+	}
+	b, _, err := db.read(off)
+	if err != nil {
+		utils.Err.Printf("could not read block %v from the block file: %v", hash, err)
+		return nil
+	}
+	db.cch(hash, b)
+	return b
+}
+
+// List reads every block from the file, in the order
+// they were put. The blocks aren't cached.
+func (db *FileBlockDb) List() []*block.Block {
+	db.Lock()
+	defer db.Unlock()
+	blocks := make([]*block.Block, 0, len(db.order))
+	for _, h := range db.order {
+		b, ok := db.cache[h]
+		if !ok {
+			var err error
+			if b, _, err = db.read(db.offs[h]); err != nil {
+				utils.Err.Printf("could not read block %v from the block file: %v", h, err)
+				continue
+			}
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+func (db *FileBlockDb) Len() int {
+	db.Lock()
+	defer db.Unlock()
+	return len(db.order)
+}
+
+func (db *FileBlockDb) Close() error {
+	db.Lock()
+	defer db.Unlock()
+	return db.f.Close()
+}
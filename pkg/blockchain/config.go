@@ -1,5 +1,7 @@
 package blockchain
 
+import "BrunoCoin/pkg/utils"
+
 // GENPK is the public key that was used
 // for the genesis transaction on the
 // genesis block.
@@ -14,14 +16,51 @@ var GENPVK = "307702010104202456b0e8bed5c27dcadb044df1af8eaf714084b61a23d17359fb
 // blockchain.
 // HasChn True if the node wants to store
 // a copy of the blockchain.
+// HdrsOnly (HeadersOnly) True if the chain only
+// keeps the headers of blocks, as on a light node.
+// It has no utxo set, and blocks are added with
+// AddHdr.
 // InitSbsdy is the amount of money given
 // to GenPK in the genesis transaction.
 // GenPK is the public key for the genesis
 // transaction.
+// EphDb (EphemeralDatabase) True if blocks should
+// only be kept in memory.
+// DbPath is the file that blocks are stored in
+// when EphDb is false.
+// POWLmt (ProofOfWorkLimit) is the easiest difficulty
+// target any block is allowed to have.
+// InitTrg (InitialTarget) is the difficulty target of
+// the blocks in the first retarget interval, starting
+// with the first block after the genesis block.
+// RtrgtIntvl (RetargetInterval) is how many blocks
+// share a difficulty target before it is recalculated.
+// TrgtBlkTm (TargetBlockTime) is the amount of seconds
+// that a block should take to be mined. Retargeting
+// moves the difficulty towards it.
+// MntRwrd (MintingReward) is the subsidy a block's
+// coinbase may pay on top of the fees, before any
+// halvings (see CalcSubsdy).
+// SbsdyHlvRt (SubsidyHalvingRate) is how many blocks
+// are added between each halving of the subsidy.
+// MxHlvgs (MaxHalvings) is the number of halvings after
+// which the subsidy becomes 0.
 type Config struct {
 	HasChn    bool
+	HdrsOnly  bool
 	InitSbsdy uint32
 	GenPK     string
+	EphDb     bool
+	DbPath    string
+
+	POWLmt     string
+	InitTrg    string
+	RtrgtIntvl uint32
+	TrgtBlkTm  uint32
+
+	MntRwrd    uint32
+	SbsdyHlvRt uint32
+	MxHlvgs    uint32
 }
 
 // DefaultConfig returns the default
@@ -30,8 +69,20 @@ type Config struct {
 func DefaultConfig() *Config {
 	return &Config{
 		HasChn:    true,
+		HdrsOnly:  false,
 		InitSbsdy: 100000,
 		GenPK:     GENPK,
+		EphDb:     true,
+		DbPath:    "",
+
+		POWLmt:     utils.CalcPOWD(0),
+		InitTrg:    utils.CalcPOWD(1),
+		RtrgtIntvl: 10,
+		TrgtBlkTm:  10,
+
+		MntRwrd:    10,
+		SbsdyHlvRt: 10,
+		MxHlvgs:    10,
 	}
 }
 
@@ -41,7 +92,40 @@ func DefaultConfig() *Config {
 func NilConfig() *Config {
 	return &Config{
 		HasChn:    false,
+		HdrsOnly:  false,
 		InitSbsdy: 100000,
 		GenPK:     GENPK,
+		EphDb:     true,
+		DbPath:    "",
+
+		POWLmt:     utils.CalcPOWD(0),
+		InitTrg:    utils.CalcPOWD(1),
+		RtrgtIntvl: 10,
+		TrgtBlkTm:  10,
+
+		MntRwrd:    10,
+		SbsdyHlvRt: 10,
+		MxHlvgs:    10,
 	}
 }
+
+// PersistentConfig returns the default settings,
+// except that blocks are stored in the file at
+// path, so the chain survives restarts.
+// Inputs:
+// path string the file to store blocks in
+func PersistentConfig(path string) *Config {
+	c := DefaultConfig()
+	c.EphDb = false
+	c.DbPath = path
+	return c
+}
+
+// LightConfig returns the default settings,
+// except that the chain only keeps block
+// headers (see HdrsOnly).
+func LightConfig() *Config {
+	c := DefaultConfig()
+	c.HdrsOnly = true
+	return c
+}
@@ -0,0 +1,104 @@
+package blockchain
+
+import (
+	"BrunoCoin/pkg/block"
+	"fmt"
+	"math/big"
+)
+
+// NextDifTrg (NextDifficultyTarget) returns the
+// difficulty target that the next block on the main
+// chain has to have.
+// Returns:
+// string the difficulty target as a hex string
+func (bc *Blockchain) NextDifTrg() string {
+	bc.Lock()
+	defer bc.Unlock()
+	return bc.difTrgAt(bc.LastBlock)
+}
+
+// ChkDifTrg (CheckDifficultyTarget) checks that a
+// block has the difficulty target required at its
+// spot in the chain.
+// Inputs:
+// b *block.Block the block being checked
+// Returns:
+// bool True if the block's target is the expected
+// target, false otherwise or if its previous block is
+// unknown
+func (bc *Blockchain) ChkDifTrg(b *block.Block) bool {
+	bc.Lock()
+	defer bc.Unlock()
+	prv, found := bc.blocks[b.Hdr.PrvBlkHsh]
+	if !found {
+		return false
+	}
+	return bc.chkDifTrg(prv, b.Hdr.DiffTarg)
+}
+
+// chkDifTrg (checkDifficultyTarget) checks that a
+// difficulty target is the one required on top of prv.
+// The caller must hold the blockchain's lock.
+func (bc *Blockchain) chkDifTrg(prv *BlockchainNode, dt string) bool {
+	return dt == bc.difTrgAt(prv)
+}
+
+// difTrgAt (difficultyTargetAt) calculates the
+// difficulty target for a block on top of prv.
+// The caller must hold the blockchain's lock.
+// Every RtrgtIntvl blocks, the target is scaled by
+// how long the last RtrgtIntvl blocks actually took
+// compared to how long they should have taken (by at
+// most a factor of 4 either way). Otherwise, a block
+// keeps the target of the block before it. The first
+// block after the genesis block has InitTrg.
+// Inputs:
+// prv *BlockchainNode the previous block
+// Returns:
+// string the difficulty target as a hex string
+func (bc *Blockchain) difTrgAt(prv *BlockchainNode) string {
+	if prv.depth == 0 {
+		return bc.conf.InitTrg
+	}
+	n := int(bc.conf.RtrgtIntvl)
+	if n <= 1 || prv.depth%n != 0 {
+		return prv.Hdr.DiffTarg
+	}
+	first := prv
+	for i := 1; i < n; i++ {
+		first = first.PrevNode
+	}
+	exp := int64(n-1) * int64(bc.conf.TrgtBlkTm)
+	act := int64(prv.Hdr.Timestamp) - int64(first.Hdr.Timestamp)
+	if exp <= 0 {
+		return prv.Hdr.DiffTarg
+	}
+	if act < exp/4 {
+		act = exp / 4
+	} else if act > exp*4 {
+		act = exp * 4
+	}
+	trg, ok := new(big.Int).SetString(prv.Hdr.DiffTarg, 16)
+	if !ok {
+		return prv.Hdr.DiffTarg
+	}
+	trg.Mul(trg, big.NewInt(act))
+	trg.Div(trg, big.NewInt(exp))
+	if lmt := bc.powLmt(); trg.Cmp(lmt) > 0 {
+		trg = lmt
+	}
+	if trg.Sign() <= 0 {
+		trg.SetInt64(1)
+	}
+	return fmt.Sprintf("%064x", trg)
+}
+
+// powLmt (proofOfWorkLimit) returns the easiest
+// allowed difficulty target as a number.
+func (bc *Blockchain) powLmt() *big.Int {
+	lmt, ok := new(big.Int).SetString(bc.conf.POWLmt, 16)
+	if !ok {
+		return new(big.Int)
+	}
+	return lmt
+}
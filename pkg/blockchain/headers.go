@@ -0,0 +1,187 @@
+package blockchain
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"math/big"
+)
+
+// HdrsOnly (HeadersOnly) returns whether the chain only
+// keeps the headers of blocks (see Config.HdrsOnly).
+func (bc *Blockchain) HdrsOnly() bool {
+	return bc.conf.HdrsOnly
+}
+
+// AddHdr (AddHeader) adds a block to a light node's chain
+// by only its header. The block is kept without its
+// transactions, and the chain with the most cumulative
+// work is the main chain, like in Add. Headers are not
+// written to the block database.
+// To be added, the header must be on a block that is on
+// the chain, and pass the checks of hdrErr.
+// Inputs:
+// h block.Header the header
+// Returns:
+// bool True if the header is the new last block of the
+// main chain
+// error why the header is invalid (a *valerr.Err), or nil
+// if it was added or was already on the chain
+func (bc *Blockchain) AddHdr(h block.Header) (bool, error) {
+	b := &block.Block{Hdr: h}
+	hsh := b.Hash()
+	bc.Lock()
+	defer bc.Unlock()
+	if _, found := bc.blocks[hsh]; found {
+		return false, nil
+	}
+	prv, found := bc.blocks[h.PrvBlkHsh]
+	if !found {
+		return false, valerr.New(valerr.UnknownPrv, "previous block %v is unknown", h.PrvBlkHsh)
+	}
+	if err := bc.hdrErr(prv, b); err != nil {
+		return false, err
+	}
+
+	n := hdrNd(prv, b)
+	bc.blocks[hsh] = n
+	utils.Debug.Printf("Address " + utils.FmtAddr(bc.Addr) + " -> header " + b.NameTag())
+	if n.work.Cmp(bc.LastBlock.work) <= 0 {
+		return false, nil
+	}
+	bc.LastBlock = n
+	return true, nil
+}
+
+// Work returns the cumulative work of the chain up to a
+// block, or 0 if the block is unknown.
+// Inputs:
+// hsh string the hash of the block
+func (bc *Blockchain) Work(hsh string) *big.Int {
+	bc.Lock()
+	defer bc.Unlock()
+	if n, found := bc.blocks[hsh]; found {
+		return new(big.Int).Set(n.work)
+	}
+	return new(big.Int)
+}
+
+// ChkHdrs (CheckHeaders) validates a chain of headers
+// before their blocks are downloaded. The first header
+// must be on a block that is on the chain, and every
+// other header on the header before it. Each header is
+// checked as in AddHdr, on top of the headers before it,
+// but nothing is added to the chain.
+// Inputs:
+// hdrs []block.Header the headers, in chain order
+// Returns:
+// error why a header is invalid (a *valerr.Err), or nil
+// if they all are valid
+func (bc *Blockchain) ChkHdrs(hdrs []block.Header) error {
+	if len(hdrs) == 0 {
+		return nil
+	}
+	bc.Lock()
+	defer bc.Unlock()
+	prv, found := bc.blocks[hdrs[0].PrvBlkHsh]
+	if !found {
+		return valerr.New(valerr.UnknownPrv, "previous block %v is unknown", hdrs[0].PrvBlkHsh)
+	}
+	for i, h := range hdrs {
+		b := &block.Block{Hdr: h}
+		if h.PrvBlkHsh != prv.Hash() {
+			return valerr.New(valerr.UnknownPrv, "header %v does not follow the header before it", i)
+		}
+		if err := bc.hdrErr(prv, b); err != nil {
+			return err
+		}
+		prv = hdrNd(prv, b)
+	}
+	return nil
+}
+
+// hdrErr (headerError) checks a block's header on top
+// of prv: it must have a known version that isn't older
+// than prv's, the difficulty target the chain expects,
+// satisfy that target and not be before the median time
+// past of prv. The caller must hold the blockchain's lock.
+func (bc *Blockchain) hdrErr(prv *BlockchainNode, b *block.Block) error {
+	h := b.Hdr
+	if h.Ver > block.CurVer || h.Ver < prv.Hdr.Ver {
+		return valerr.New(valerr.BadVer, "block version %v is not allowed after %v", h.Ver, prv.Hdr.Ver)
+	}
+	if !bc.chkDifTrg(prv, h.DiffTarg) {
+		return valerr.New(valerr.BadDifTrg, "difficulty target %v is not the expected target", h.DiffTarg)
+	}
+	if !b.SatisfiesPOW(h.DiffTarg) {
+		return valerr.New(valerr.BadPOW, "block does not satisfy its difficulty target")
+	}
+	if mtp := medTmPst(prv); h.Timestamp < mtp {
+		return valerr.New(valerr.TimeTooOld, "timestamp %v is before the median time past %v", h.Timestamp, mtp)
+	}
+	return nil
+}
+
+// hdrNd (headerNode) makes the node of a block on top of
+// prv, without utxo undo records.
+func hdrNd(prv *BlockchainNode, b *block.Block) *BlockchainNode {
+	return &BlockchainNode{
+		Block:    b,
+		PrevNode: prv,
+		depth:    prv.depth + 1,
+		work:     new(big.Int).Add(prv.work, utils.CalcWork(b.Hdr.DiffTarg)),
+	}
+}
+
+// Locator returns the hashes of blocks on the main chain
+// that a peer can use to find where its main chain forks
+// from this one (see FndFork). They start with the last
+// 10 blocks, then step back twice as far each time, and
+// end with the genesis block.
+// Returns:
+// []string the hashes, from the last block down
+func (bc *Blockchain) Locator() []string {
+	bc.Lock()
+	defer bc.Unlock()
+	var loc []string
+	stp := 1
+	n := bc.LastBlock
+	for {
+		loc = append(loc, n.Hash())
+		if n.PrevNode == nil {
+			return loc
+		}
+		if len(loc) >= 10 {
+			stp *= 2
+		}
+		for i := 0; i < stp && n.PrevNode != nil; i++ {
+			n = n.PrevNode
+		}
+	}
+}
+
+// FndFork (FindFork) finds the first block of a locator
+// (see Locator) that is on the main chain.
+// Inputs:
+// loc []string the hashes of the locator
+// Returns:
+// int the height of the block, or -1 if none of them
+// are on the main chain
+func (bc *Blockchain) FndFork(loc []string) int {
+	bc.Lock()
+	defer bc.Unlock()
+	for _, h := range loc {
+		n, found := bc.blocks[h]
+		if !found || n.depth > bc.LastBlock.depth {
+			continue
+		}
+		m := bc.LastBlock
+		for m.depth > n.depth {
+			m = m.PrevNode
+		}
+		if m == n {
+			return n.depth
+		}
+	}
+	return -1
+}
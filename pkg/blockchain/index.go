@@ -0,0 +1,101 @@
+package blockchain
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/script"
+)
+
+// txLoc (transactionLocation) is where a transaction is
+// on the main chain.
+// nd (node) is the block holding it
+// idx (index) is the index of the transaction on the
+// block
+type txLoc struct {
+	nd  *BlockchainNode
+	idx int
+}
+
+// index adds the transactions of a block joining the
+// main chain to the transaction index, and its payloads
+// (see script.Data) to the data index. Blocks have to
+// be indexed in chain order. The caller must hold the
+// blockchain's lock.
+// Inputs:
+// n *BlockchainNode the node of the block
+// b *block.Block the block
+func (bc *Blockchain) index(n *BlockchainNode, b *block.Block) {
+	for i, t := range b.Transactions {
+		bc.txIdx[t.Hash()] = txLoc{n, i}
+		for _, o := range t.Outputs {
+			if d, ok := script.DataOf(o.LockingScript); ok {
+				bc.dataIdx[string(d)] = append(bc.dataIdx[string(d)], txLoc{n, i})
+			}
+		}
+	}
+}
+
+// unindex takes the transactions and payloads of a block
+// leaving the main chain back out of the indexes. Blocks
+// have to be unindexed from the last block back. The
+// caller must hold the blockchain's lock.
+func (bc *Blockchain) unindex(n *BlockchainNode, b *block.Block) {
+	for _, t := range b.Transactions {
+		if l, ok := bc.txIdx[t.Hash()]; ok && l.nd == n {
+			delete(bc.txIdx, t.Hash())
+		}
+		for _, o := range t.Outputs {
+			d, ok := script.DataOf(o.LockingScript)
+			if !ok {
+				continue
+			}
+			ls := bc.dataIdx[string(d)]
+			if len(ls) > 0 && ls[len(ls)-1].nd == n {
+				ls = ls[:len(ls)-1]
+			}
+			if len(ls) == 0 {
+				delete(bc.dataIdx, string(d))
+			} else {
+				bc.dataIdx[string(d)] = ls
+			}
+		}
+	}
+}
+
+// FndTx (FindTransaction) finds a transaction on the
+// main chain.
+// Inputs:
+// h string the hash of the transaction
+// Returns:
+// *block.Block the block holding the transaction
+// uint32 the height of the block
+// int the index of the transaction on the block
+// bool False if the transaction isn't on the main chain
+func (bc *Blockchain) FndTx(h string) (*block.Block, uint32, int, bool) {
+	bc.Lock()
+	defer bc.Unlock()
+	l, ok := bc.txIdx[h]
+	if !ok {
+		return nil, 0, 0, false
+	}
+	return bc.blk(l.nd), uint32(l.nd.depth), l.idx, true
+}
+
+// FndData (FindData) finds the first transaction on the
+// main chain carrying a payload (see script.Data).
+// Inputs:
+// d []byte the payload
+// Returns:
+// *block.Block the block holding the transaction
+// uint32 the height of the block
+// int the index of the transaction on the block
+// bool False if no transaction on the main chain
+// carries it
+func (bc *Blockchain) FndData(d []byte) (*block.Block, uint32, int, bool) {
+	bc.Lock()
+	defer bc.Unlock()
+	ls := bc.dataIdx[string(d)]
+	if len(ls) == 0 {
+		return nil, 0, 0, false
+	}
+	return bc.blk(ls[0].nd), uint32(ls[0].nd.depth), ls[0].idx, true
+}
@@ -0,0 +1,143 @@
+package blockchain
+
+import (
+	"BrunoCoin/pkg/block"
+	"sync"
+)
+
+// OrphanPool holds blocks whose previous block is
+// not on the blockchain yet. Orphans are keyed by the
+// hash of the block they are missing, so once that block
+// arrives, every orphan waiting on it can be found.
+// orphs maps the hash of a missing block to the orphans
+// that reference it as their previous block.
+// hshs is the set of hashes of all orphans in the pool.
+// order holds the hashes of the orphans in the order
+// they arrived, so the oldest can be evicted first.
+// Cap is the maximum number of orphans kept.
+type OrphanPool struct {
+	orphs map[string][]*block.Block
+	hshs  map[string]*block.Block
+	order []string
+	Cap   int
+	mutex sync.Mutex
+}
+
+// NewOrphanPool creates an empty orphan pool.
+// Inputs:
+// cap int the maximum number of orphans to hold
+func NewOrphanPool(cap int) *OrphanPool {
+	return &OrphanPool{
+		orphs: make(map[string][]*block.Block),
+		hshs:  make(map[string]*block.Block),
+		Cap:   cap,
+	}
+}
+
+// Add adds an orphan to the pool. If the pool is full,
+// the orphan that has been waiting the longest is
+// evicted to make room.
+// Inputs:
+// b *block.Block the orphan block
+// Returns:
+// bool True if the orphan was added, false if it
+// was already in the pool
+func (op *OrphanPool) Add(b *block.Block) bool {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	h := b.Hash()
+	if _, found := op.hshs[h]; found || op.Cap <= 0 {
+		return false
+	}
+	for len(op.hshs) >= op.Cap && len(op.order) > 0 {
+		op.evict(op.order[0])
+	}
+	op.hshs[h] = b
+	op.order = append(op.order, h)
+	op.orphs[b.Hdr.PrvBlkHsh] = append(op.orphs[b.Hdr.PrvBlkHsh], b)
+	return true
+}
+
+// evict removes a single orphan from the pool.
+// The caller must hold the mutex.
+func (op *OrphanPool) evict(h string) {
+	for i, o := range op.order {
+		if o == h {
+			op.order = append(op.order[:i], op.order[i+1:]...)
+			break
+		}
+	}
+	b, found := op.hshs[h]
+	if !found {
+		return
+	}
+	delete(op.hshs, h)
+	sibs := op.orphs[b.Hdr.PrvBlkHsh]
+	for i, s := range sibs {
+		if s.Hash() == h {
+			sibs = append(sibs[:i], sibs[i+1:]...)
+			break
+		}
+	}
+	if len(sibs) == 0 {
+		delete(op.orphs, b.Hdr.PrvBlkHsh)
+	} else {
+		op.orphs[b.Hdr.PrvBlkHsh] = sibs
+	}
+}
+
+// Rmv (Remove) removes and returns every orphan that
+// was waiting on a particular block.
+// Inputs:
+// prv string the hash of the block that arrived
+// Returns:
+// []*block.Block the orphans whose previous block
+// is prv, in the order they arrived
+func (op *OrphanPool) Rmv(prv string) []*block.Block {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	children := append([]*block.Block(nil), op.orphs[prv]...)
+	for _, c := range children {
+		op.evict(c.Hash())
+	}
+	return children
+}
+
+// Has returns whether a block is in the pool.
+// Inputs:
+// h string the hash of the block
+func (op *OrphanPool) Has(h string) bool {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	_, found := op.hshs[h]
+	return found
+}
+
+// Len (Length) returns the number of orphans.
+func (op *OrphanPool) Len() int {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	return len(op.hshs)
+}
+
+// Missing follows an orphan back through its orphaned
+// ancestors in the pool, and returns the hash of the
+// first block that is not in the pool. That is the block
+// that needs to be fetched to connect the orphan.
+// Inputs:
+// b *block.Block an orphan block
+// Returns:
+// string the hash of the oldest missing ancestor
+func (op *OrphanPool) Missing(b *block.Block) string {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	h := b.Hdr.PrvBlkHsh
+	for i := 0; i <= len(op.hshs); i++ {
+		prv, found := op.hshs[h]
+		if !found {
+			break
+		}
+		h = prv.Hdr.PrvBlkHsh
+	}
+	return h
+}
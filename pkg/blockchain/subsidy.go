@@ -0,0 +1,21 @@
+package blockchain
+
+import "math"
+
+// CalcSubsdy (CalculateSubsidy) calculates the
+// minting reward for a block according to the halving
+// schedule. The subsidy halves every SbsdyHlvRt blocks,
+// and is 0 after MxHlvgs halvings.
+// Inputs:
+// c *Config the blockchain configuration with the
+// schedule
+// h uint32 the height (index on the chain) of the block
+// Returns:
+// uint32 the minting reward
+func CalcSubsdy(c *Config, h uint32) uint32 {
+	divisor := h / c.SbsdyHlvRt
+	if divisor > c.MxHlvgs {
+		return 0
+	}
+	return c.MntRwrd / uint32((math.Pow(2, float64(divisor))))
+}
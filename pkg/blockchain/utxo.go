@@ -0,0 +1,152 @@
+package blockchain
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/script"
+)
+
+// utxoSet is a set of utxo keyed by their txo
+// locators (see txo.MkTXOLoc). It is either the
+// utxo set of the main chain or a view on top of it.
+type utxoSet interface {
+	get(l string) *txo.TransactionOutput
+	set(l string, o *txo.TransactionOutput)
+	del(l string)
+}
+
+// utxoMap is the utxo set of the last block on the
+// main chain. There is only one of these per
+// blockchain.
+type utxoMap map[string]*txo.TransactionOutput
+
+func (m utxoMap) get(l string) *txo.TransactionOutput { return m[l] }
+
+func (m utxoMap) set(l string, o *txo.TransactionOutput) { m[l] = o }
+
+func (m utxoMap) del(l string) { delete(m, l) }
+
+// utxoView is the utxo set of some other block,
+// made by recording the changes from the main chain's
+// utxo set without copying it.
+// base is the utxo set of the main chain
+// mod holds every locator that differs from base. A
+// nil output means the utxo doesn't exist in the view.
+type utxoView struct {
+	base utxoMap
+	mod  map[string]*txo.TransactionOutput
+}
+
+func (v *utxoView) get(l string) *txo.TransactionOutput {
+	if o, found := v.mod[l]; found {
+		return o
+	}
+	return v.base[l]
+}
+
+func (v *utxoView) set(l string, o *txo.TransactionOutput) { v.mod[l] = o }
+
+func (v *utxoView) del(l string) { v.mod[l] = nil }
+
+// undo holds what is needed to take a block's
+// changes back out of a utxo set.
+// Spent maps the locator of every utxo the block
+// removed (or replaced) to the output it had before
+// the block.
+// Created are the locators of the outputs the block
+// added.
+type undo struct {
+	Spent   map[string]*txo.TransactionOutput
+	Created []string
+}
+
+// connect applies a block to a utxo set. Inputs are
+// spent first, then the outputs of every transaction
+// are added, except data outputs (see script.Data),
+// which can never be spent.
+// Inputs:
+// s utxoSet the utxo set of the previous block
+// b *block.Block the block being applied
+// Returns:
+// *undo the record needed to disconnect the block
+func connect(s utxoSet, b *block.Block) *undo {
+	u := &undo{Spent: make(map[string]*txo.TransactionOutput)}
+	for _, t := range b.Transactions {
+		for _, i := range t.Inputs {
+			l := txo.MkTXOLoc(i.TransactionHash, i.OutputIndex)
+			if o := s.get(l); o != nil {
+				if _, found := u.Spent[l]; !found {
+					u.Spent[l] = o
+				}
+				s.del(l)
+			}
+		}
+	}
+	for _, t := range b.Transactions {
+		h := t.Hash()
+		for i, o := range t.Outputs {
+			if script.IsData(o.LockingScript) {
+				continue
+			}
+			l := txo.MkTXOLoc(h, uint32(i))
+			if prv := s.get(l); prv != nil {
+				if _, found := u.Spent[l]; !found {
+					u.Spent[l] = prv
+				}
+			}
+			s.set(l, o)
+			u.Created = append(u.Created, l)
+		}
+	}
+	return u
+}
+
+// disconnect takes a block's changes back out of a
+// utxo set, leaving the utxo set of the previous block.
+// Inputs:
+// s utxoSet the utxo set of the block
+// u *undo the record made when the block was connected
+func disconnect(s utxoSet, u *undo) {
+	for _, l := range u.Created {
+		s.del(l)
+	}
+	for l, o := range u.Spent {
+		s.set(l, o)
+	}
+}
+
+// viewAt returns the utxo set of any block in the
+// tree. The caller must hold the blockchain's lock.
+// For the last block on the main chain it is just the
+// main utxo set. For a block on a fork, the blocks after
+// the fork point are disconnected from a view, and the
+// fork's blocks are connected to it.
+// Inputs:
+// n *BlockchainNode the block whose utxo set is wanted
+// Returns:
+// utxoSet the utxo set up until (and including) n
+func (bc *Blockchain) viewAt(n *BlockchainNode) utxoSet {
+	if n == bc.LastBlock {
+		return bc.utxo
+	}
+	v := &utxoView{base: bc.utxo, mod: make(map[string]*txo.TransactionOutput)}
+	old, nw := bc.LastBlock, n
+	var conn []*BlockchainNode
+	for old.depth > nw.depth {
+		disconnect(v, old.undo)
+		old = old.PrevNode
+	}
+	for nw.depth > old.depth {
+		conn = append(conn, nw)
+		nw = nw.PrevNode
+	}
+	for old != nw {
+		disconnect(v, old.undo)
+		conn = append(conn, nw)
+		old, nw = old.PrevNode, nw.PrevNode
+	}
+	for i := len(conn) - 1; i >= 0; i-- {
+		connect(v, bc.blk(conn[i]))
+	}
+	return v
+}
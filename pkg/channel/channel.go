@@ -0,0 +1,144 @@
+package channel
+
+import (
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"errors"
+	"fmt"
+)
+
+// Chnl (Channel) is a unidirectional payment channel.
+// The payer pays into an output that needs both of
+// their signatures (see script.Chnl), and then pays the
+// payee by signing commitments: transactions spending
+// that output that pay the payee more each time. The
+// payee holds on to the last commitment, and closes
+// the channel by adding its signature and broadcasting
+// it. If the payee never does, the payer can take the
+// money back once the lock time has passed.
+// ID is the locator of the channel's output (see
+// txo.MkTXOLoc)
+// Rdm (Redeem) is the channel's script
+// Payer and Payee are their public keys
+// Cap (Capacity) is the amount in the channel
+// Lck (Lock) is the block height after which the payer
+// can take back the channel
+// Fee is the fee of the transaction closing the channel
+// Ver (Version) is the version of the commitments
+// Addr is the address of the other side's node
+// IsPayer is whether this side is the payer
+// Paid is the amount paid to the payee so far
+// Cls (Close) is the transaction that spent the channel's
+// output on the main chain, or nil
+// sig (signature) is the payer's signature of the
+// commitment paying Paid
+// sgnd (signed) holds the amounts of every commitment
+// signed by the payer
+// sent is the transaction this side sent out to close
+// the channel, or nil
+type Chnl struct {
+	ID      string
+	Rdm     []byte
+	Payer   []byte
+	Payee   []byte
+	Cap     uint32
+	Lck     uint32
+	Fee     uint32
+	Ver     uint32
+	Addr    string
+	IsPayer bool
+
+	Paid uint32
+	Cls  *tx.Transaction
+
+	sig  []byte
+	sgnd map[uint32]bool
+	sent *tx.Transaction
+}
+
+// Out (Output) returns the channel's output.
+func (c *Chnl) Out() *txo.TransactionOutput {
+	return &txo.TransactionOutput{Amount: c.Cap, LockingScript: script.P2SH(c.Rdm)}
+}
+
+// Cmt (Commitment) makes the unsigned commitment that
+// pays the payee paid. The rest, less the fee, goes back
+// to the payer.
+// Inputs:
+// paid uint32 the total amount paid to the payee
+// Returns:
+// *tx.Transaction the commitment
+// error if the channel doesn't have paid and the fee
+func (c *Chnl) Cmt(paid uint32) (*tx.Transaction, error) {
+	if uint64(paid)+uint64(c.Fee) > uint64(c.Cap) {
+		return nil, fmt.Errorf("channel of %v can't pay %v and the fee of %v", c.Cap, paid, c.Fee)
+	}
+	h, i := txo.PrsTXOLoc(c.ID)
+	ins := []*proto.TransactionInput{proto.NewTxInpt(h, i, "", c.Cap)}
+	var outs []*proto.TransactionOutput
+	if paid > 0 {
+		outs = append(outs, proto.NewTxOutpt(paid, script.P2PKH(c.Payee)))
+	}
+	if rest := c.Cap - c.Fee - paid; rest > 0 {
+		outs = append(outs, proto.NewTxOutpt(rest, script.P2PKH(c.Payer)))
+	}
+	return tx.Deserialize(proto.NewTx(c.Ver, ins, outs, 0)), nil
+}
+
+// Rfnd (Refund) makes the unsigned transaction that
+// gives the whole channel, less the fee, back to the
+// payer. It has the channel's lock time, so it can't be
+// mined before then.
+func (c *Chnl) Rfnd() *tx.Transaction {
+	h, i := txo.PrsTXOLoc(c.ID)
+	ins := []*proto.TransactionInput{proto.NewTxInpt(h, i, "", c.Cap)}
+	outs := []*proto.TransactionOutput{proto.NewTxOutpt(c.Cap-c.Fee, script.P2PKH(c.Payer))}
+	return tx.Deserialize(proto.NewTx(c.Ver, ins, outs, c.Lck))
+}
+
+// chkSig (checkSignature) checks a signature of the
+// channel's output on a transaction spending it.
+// Inputs:
+// t *tx.Transaction the transaction spending the channel
+// pk []byte the public key that signed
+// sig []byte the signature (with its sighash flag)
+func (c *Chnl) chkSig(t *tx.Transaction, pk, sig []byte) error {
+	sp := &script.Spnd{
+		SigHsh: func(ht byte) (string, error) {
+			return t.SigHsh(0, c.Out(), ht)
+		},
+		LckTm: t.LockTime,
+	}
+	if script.Run(script.New().Push(sig).Hex(), script.P2PK(pk), sp) != nil {
+		return errors.New("signature is not valid")
+	}
+	return nil
+}
+
+// pays returns how much a transaction spending the
+// channel pays the payee.
+func (c *Chnl) pays(t *tx.Transaction) uint32 {
+	var amt uint32
+	lck := script.P2PKH(c.Payee)
+	for _, o := range t.Outputs {
+		if o.LockingScript == lck {
+			amt += o.Amount
+		}
+	}
+	return amt
+}
+
+// Dspt (Dispute) is a transaction on the main chain that
+// closed a channel without its last update.
+// Chnl (Channel) is the channel
+// Tx (Transaction) is the transaction that closed it
+// Paid is how much the transaction paid the payee
+// Rsn (Reason) is why it doesn't match
+type Dspt struct {
+	Chnl *Chnl
+	Tx   *tx.Transaction
+	Paid uint32
+	Rsn  string
+}
@@ -0,0 +1,45 @@
+package channel
+
+// Config represents the settings for payment
+// channels.
+// HasChnl (HasChannel) defines whether the node
+// opens and accepts payment channels. It needs
+// a wallet.
+// FundFee (FundingFee) is the fee of the
+// transaction paying into a channel.
+// ClsFee (CloseFee) is the fee of the transaction
+// that closes a channel, which comes out of the
+// payer's side of the channel.
+// ClsMrgn (CloseMargin) is how many blocks before a
+// channel's lock time the payee closes it, so that
+// the payer can't take back money it already paid.
+// The payee doesn't accept channels that are locked
+// for less than this.
+type Config struct {
+	HasChnl bool
+	FundFee uint32
+	ClsFee  uint32
+	ClsMrgn uint32
+}
+
+// DefaultConfig returns the standard settings
+// for payment channels.
+func DefaultConfig() *Config {
+	return &Config{
+		HasChnl: true,
+		FundFee: 20,
+		ClsFee:  20,
+		ClsMrgn: 3,
+	}
+}
+
+// NilConfig returns settings that say the node
+// has no payment channels.
+func NilConfig() *Config {
+	return &Config{
+		HasChnl: false,
+		FundFee: 0,
+		ClsFee:  0,
+		ClsMrgn: 0,
+	}
+}
@@ -0,0 +1,481 @@
+package channel
+
+import (
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/wallet"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Svc (Service) keeps track of a node's payment
+// channels, as the payer or the payee, and serves the
+// PaymentChannel gRPC service that the other side of
+// each channel talks to.
+// Conf is the configuration for channels
+// Id is the identity of the node
+// Chain is the blockchain, which is watched for
+// transactions closing channels
+// Wallet pays into channels and broadcasts the
+// transactions closing them
+// Addr is the address of the node
+// chnls (channels) maps the ID of every channel to it
+// dspts (disputes) are the channels that were closed
+// without their last update
+type Svc struct {
+	*proto.UnimplementedPaymentChannelServer
+	Conf   *Config
+	Id     id.ID
+	Chain  *blockchain.Blockchain
+	Wallet *wallet.Wallet
+	Addr   string
+
+	chnls map[string]*Chnl
+	dspts []*Dspt
+	mutex sync.Mutex
+}
+
+// New creates the payment channel service.
+// Inputs:
+// c *Config the configuration for channels
+// i id.ID the id of the node
+// chain *blockchain.Blockchain the blockchain
+// w *wallet.Wallet the wallet, which can't be nil
+// Returns:
+// *Svc the service, or nil if the node doesn't have
+// channels
+func New(c *Config, i id.ID, chain *blockchain.Blockchain, w *wallet.Wallet) *Svc {
+	if !c.HasChnl || w == nil {
+		return nil
+	}
+	return &Svc{
+		Conf:   c,
+		Id:     i,
+		Chain:  chain,
+		Wallet: w,
+		chnls:  make(map[string]*Chnl),
+	}
+}
+
+// SetAddr (SetAddress) sets the address
+// of the node in the service.
+func (s *Svc) SetAddr(a string) {
+	s.mutex.Lock()
+	s.Addr = a
+	s.mutex.Unlock()
+}
+
+// Get returns a copy of a channel, or nil if there is
+// no channel with the ID.
+func (s *Svc) Get(cid string) *Chnl {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c, ok := s.chnls[cid]
+	if !ok {
+		return nil
+	}
+	cp := *c
+	return &cp
+}
+
+// Dspts (Disputes) returns the channels that were closed
+// on the main chain without their last update.
+func (s *Svc) Dspts() []*Dspt {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*Dspt(nil), s.dspts...)
+}
+
+// hght (height) returns the height of the last block on
+// the main chain.
+func (s *Svc) hght() uint32 {
+	return uint32(s.Chain.Length() - 1)
+}
+
+// Opn (Open) opens a channel to a payee, as the payer.
+// The wallet pays amt into the channel, and the payee's
+// node is told about it. If the payee doesn't take the
+// channel, the wallet can take the money back with Rfnd
+// once lck has passed.
+// Inputs:
+// addr string the address of the payee's node
+// payee []byte the public key of the payee
+// amt uint32 the amount to put in the channel
+// lck uint32 the block height after which the payer can
+// take back the channel
+// Returns:
+// *Chnl the channel
+// error if the wallet doesn't have enough money, or the
+// payee's node didn't take the channel
+func (s *Svc) Opn(addr string, payee []byte, amt, lck uint32) (*Chnl, error) {
+	if lck >= script.LckTmThrsh || lck <= s.hght() {
+		return nil, fmt.Errorf("lock time %v is not a height after the last block", lck)
+	}
+	payer := s.Id.GetPublicKeyBytes()
+	rdm := script.Chnl(payer, payee, lck)
+	t, err := s.Wallet.PayScr(script.P2SH(rdm), amt, s.Conf.FundFee)
+	if err != nil {
+		return nil, err
+	}
+	c := &Chnl{
+		ID:      txo.MkTXOLoc(t.Hash(), 0),
+		Rdm:     rdm,
+		Payer:   payer,
+		Payee:   payee,
+		Cap:     amt,
+		Lck:     lck,
+		Fee:     s.Conf.ClsFee,
+		Ver:     s.Wallet.Conf.TxVer,
+		Addr:    addr,
+		IsPayer: true,
+		sgnd:    make(map[uint32]bool),
+	}
+	s.mutex.Lock()
+	s.chnls[c.ID] = c
+	s.mutex.Unlock()
+
+	_, err = address.New(addr, 0).OpenChannelRPC(&proto.ChannelOpen{
+		Funding:     t.Serialize(),
+		OutputIndex: 0,
+		Payer:       hex.EncodeToString(payer),
+		Payee:       hex.EncodeToString(payee),
+		LockTime:    lck,
+		Fee:         c.Fee,
+	}, address.From(s.Addr))
+	if err != nil {
+		return nil, fmt.Errorf("payee didn't take channel %v: %v", c.ID, err)
+	}
+	utils.Debug.Printf("%v opened channel %v to %v", utils.FmtAddr(s.Addr), c.ID, utils.FmtAddr(addr))
+	return c, nil
+}
+
+// Pay pays the payee of a channel, as the payer, by
+// signing a commitment that pays it amt more and
+// sending the signature to the payee's node.
+// Inputs:
+// cid string the ID of the channel
+// amt uint32 the amount to pay
+// Returns:
+// error if the channel doesn't have enough left, or the
+// payee's node didn't take the payment
+func (s *Svc) Pay(cid string, amt uint32) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c, ok := s.chnls[cid]
+	if !ok || !c.IsPayer {
+		return fmt.Errorf("not the payer of channel %v", cid)
+	}
+	if c.Cls != nil {
+		return fmt.Errorf("channel %v is closed", cid)
+	}
+	paid := c.Paid + amt
+	if paid < c.Paid {
+		return errors.New("payment overflows")
+	}
+	t, err := c.Cmt(paid)
+	if err != nil {
+		return err
+	}
+	sig, err := t.MkSig(0, c.Out(), s.Id, tx.SigHshAll)
+	if err != nil {
+		return err
+	}
+	_, err = address.New(c.Addr, 0).UpdateChannelRPC(&proto.ChannelUpdate{
+		ChannelId: cid,
+		Paid:      paid,
+		Signature: sig,
+	}, address.From(s.Addr))
+	if err != nil {
+		return fmt.Errorf("payee didn't take the payment: %v", err)
+	}
+	c.Paid = paid
+	c.sgnd[paid] = true
+	return nil
+}
+
+// Cls (Close) closes a channel with its last update.
+// The payee closes it on its own. The payer asks the
+// payee's node to close it.
+// Inputs:
+// cid string the ID of the channel
+// Returns:
+// *tx.Transaction the transaction closing the channel
+// error if the channel can't be closed
+func (s *Svc) Cls(cid string) (*tx.Transaction, error) {
+	s.mutex.Lock()
+	c, ok := s.chnls[cid]
+	if !ok {
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("no channel %v", cid)
+	}
+	if !c.IsPayer {
+		defer s.mutex.Unlock()
+		return s.cls(c)
+	}
+	addr := c.Addr
+	s.mutex.Unlock()
+
+	pt, err := address.New(addr, 0).CloseChannelRPC(&proto.ChannelClose{ChannelId: cid}, address.From(s.Addr))
+	if err != nil {
+		return nil, fmt.Errorf("payee didn't close channel %v: %v", cid, err)
+	}
+	return tx.Deserialize(pt), nil
+}
+
+// cls (close) closes a channel as the payee, by adding
+// its signature to the last commitment and broadcasting
+// it. It has to be called with the lock held.
+func (s *Svc) cls(c *Chnl) (*tx.Transaction, error) {
+	if c.sent != nil {
+		return c.sent, nil
+	}
+	if c.sig == nil {
+		return nil, fmt.Errorf("nothing was paid on channel %v", c.ID)
+	}
+	t, err := c.Cmt(c.Paid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := t.MkSig(0, c.Out(), s.Id, tx.SigHshAll)
+	if err != nil {
+		return nil, err
+	}
+	sigB, _ := hex.DecodeString(sig)
+	t.Inputs[0].UnlockingScript = script.ChnlCls(c.sig, sigB, c.Rdm)
+	if err := t.UnlckErr(0, c.Out()); err != nil {
+		return nil, err
+	}
+	c.sent = t
+	s.Wallet.Snd(t)
+	utils.Debug.Printf("%v closing channel %v with %v paid", utils.FmtAddr(s.Addr), c.ID, c.Paid)
+	return t, nil
+}
+
+// Rfnd (Refund) takes back a channel, as the payer,
+// without the payee. The transaction can't be on a
+// block until the channel's lock time has passed, even
+// one mined by the payer (see script.Chnl), and by then
+// the payee should have closed the channel if it was
+// paid anything.
+// Inputs:
+// cid string the ID of the channel
+// Returns:
+// *tx.Transaction the refund
+// error if the wallet isn't the payer
+func (s *Svc) Rfnd(cid string) (*tx.Transaction, error) {
+	s.mutex.Lock()
+	c, ok := s.chnls[cid]
+	if !ok || !c.IsPayer {
+		s.mutex.Unlock()
+		return nil, fmt.Errorf("not the payer of channel %v", cid)
+	}
+	t := c.Rfnd()
+	out := c.Out()
+	rdm := c.Rdm
+	s.mutex.Unlock()
+
+	sig, err := t.MkSig(0, out, s.Id, tx.SigHshAll)
+	if err != nil {
+		return nil, err
+	}
+	sigB, _ := hex.DecodeString(sig)
+	t.Inputs[0].UnlockingScript = script.ChnlRfnd(sigB, rdm)
+	if err := t.UnlckErr(0, out); err != nil {
+		return nil, err
+	}
+	s.Wallet.Snd(t)
+	return t, nil
+}
+
+// HndlBlks (HandleBlocks) watches the blocks joining
+// and leaving the main chain for transactions that close
+// channels. A close that doesn't pay the payee what the
+// last update did (an old commitment, or a refund) is a
+// dispute. A close whose block leaves the main chain is
+// forgotten, along with its dispute, since the channel
+// is open again. The payee closes its channels that are
+// about to reach their lock time.
+// Inputs:
+// u *blockchain.ChnUpd how the main chain changed
+func (s *Svc) HndlBlks(u *blockchain.ChnUpd) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, b := range u.Disconnected {
+		for _, t := range b.Transactions {
+			for _, i := range t.Inputs {
+				c, ok := s.chnls[txo.MkTXOLoc(i.TransactionHash, i.OutputIndex)]
+				if ok && c.Cls != nil && c.Cls.Hash() == t.Hash() {
+					s.hndlOpn(c)
+				}
+			}
+		}
+	}
+	for _, b := range u.Connected {
+		for _, t := range b.Transactions {
+			for _, i := range t.Inputs {
+				c, ok := s.chnls[txo.MkTXOLoc(i.TransactionHash, i.OutputIndex)]
+				if ok && c.Cls == nil {
+					s.hndlCls(c, t)
+				}
+			}
+		}
+	}
+
+	h := s.hght()
+	for _, c := range s.chnls {
+		if c.IsPayer || c.Cls != nil || c.sent != nil || c.sig == nil || h+s.Conf.ClsMrgn < c.Lck {
+			continue
+		}
+		if _, err := s.cls(c); err != nil {
+			utils.Debug.Printf("%v could not close channel %v: %v", utils.FmtAddr(s.Addr), c.ID, err)
+		}
+	}
+}
+
+// hndlCls (handleClose) records the transaction that
+// closed a channel, and whether it is a dispute. It has
+// to be called with the lock held.
+func (s *Svc) hndlCls(c *Chnl, t *tx.Transaction) {
+	c.Cls = t
+	paid := c.pays(t)
+	var rsn string
+	switch {
+	case paid == c.Paid:
+		utils.Debug.Printf("%v channel %v closed with %v paid", utils.FmtAddr(s.Addr), c.ID, paid)
+		return
+	case paid == 0:
+		rsn = "refunded"
+	case !c.sgnd[paid]:
+		rsn = "closed with a commitment the payer didn't sign"
+	default:
+		rsn = "closed with an old commitment"
+	}
+	d := &Dspt{Chnl: c, Tx: t, Paid: paid, Rsn: rsn}
+	s.dspts = append(s.dspts, d)
+	utils.Debug.Printf("%v dispute on channel %v: %v (paid %v of %v)", utils.FmtAddr(s.Addr), c.ID, rsn, paid, c.Paid)
+}
+
+// hndlOpn (handleOpen) forgets the transaction that
+// closed a channel, and its dispute, once it is no
+// longer on the main chain. It has to be called with
+// the lock held.
+func (s *Svc) hndlOpn(c *Chnl) {
+	h := c.Cls.Hash()
+	c.Cls = nil
+	if c.sent != nil && c.sent.Hash() == h {
+		c.sent = nil
+	}
+	for i, d := range s.dspts {
+		if d.Tx.Hash() == h {
+			s.dspts = append(s.dspts[:i], s.dspts[i+1:]...)
+			break
+		}
+	}
+	utils.Debug.Printf("%v channel %v is open again", utils.FmtAddr(s.Addr), c.ID)
+}
+
+// OpenChannel is called by the payer's node to open a
+// channel to this node's wallet.
+func (s *Svc) OpenChannel(ctx context.Context, in *proto.ChannelOpen) (*proto.Empty, error) {
+	payer, err1 := hex.DecodeString(in.Payer)
+	payee, err2 := hex.DecodeString(in.Payee)
+	if err1 != nil || err2 != nil || !bytes.Equal(payee, s.Id.GetPublicKeyBytes()) {
+		return &proto.Empty{}, status.Error(codes.InvalidArgument, "channel doesn't pay this node")
+	}
+	if in.Funding == nil || int(in.OutputIndex) >= len(in.Funding.Outputs) {
+		return &proto.Empty{}, status.Error(codes.InvalidArgument, "no channel output")
+	}
+	if in.LockTime >= script.LckTmThrsh || in.LockTime < s.hght()+s.Conf.ClsMrgn {
+		return &proto.Empty{}, status.Errorf(codes.InvalidArgument, "lock time %v is too soon", in.LockTime)
+	}
+	t := tx.Deserialize(in.Funding)
+	rdm := script.Chnl(payer, payee, in.LockTime)
+	o := t.Outputs[in.OutputIndex]
+	if o.LockingScript != script.P2SH(rdm) {
+		return &proto.Empty{}, status.Error(codes.InvalidArgument, "output isn't locked by the channel's script")
+	}
+	if in.Fee >= o.Amount {
+		return &proto.Empty{}, status.Error(codes.InvalidArgument, "fee is more than the channel")
+	}
+	c := &Chnl{
+		ID:    txo.MkTXOLoc(t.Hash(), in.OutputIndex),
+		Rdm:   rdm,
+		Payer: payer,
+		Payee: payee,
+		Cap:   o.Amount,
+		Lck:   in.LockTime,
+		Fee:   in.Fee,
+		Ver:   t.Version,
+		Addr:  address.Sender(ctx),
+		sgnd:  make(map[uint32]bool),
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.chnls[c.ID]; ok {
+		return &proto.Empty{}, status.Errorf(codes.AlreadyExists, "channel %v is already open", c.ID)
+	}
+	s.chnls[c.ID] = c
+	utils.Debug.Printf("%v accepted channel %v of %v", utils.FmtAddr(s.Addr), c.ID, c.Cap)
+	return &proto.Empty{}, nil
+}
+
+// UpdateChannel is called by the payer's node with its
+// signature of a commitment that pays this node more.
+func (s *Svc) UpdateChannel(ctx context.Context, in *proto.ChannelUpdate) (*proto.Empty, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c, ok := s.chnls[in.ChannelId]
+	if !ok || c.IsPayer {
+		return &proto.Empty{}, status.Errorf(codes.NotFound, "not the payee of channel %v", in.ChannelId)
+	}
+	if c.Cls != nil || c.sent != nil {
+		return &proto.Empty{}, status.Errorf(codes.FailedPrecondition, "channel %v is closed", c.ID)
+	}
+	if in.Paid <= c.Paid {
+		return &proto.Empty{}, status.Errorf(codes.InvalidArgument, "update pays %v, but %v was already paid", in.Paid, c.Paid)
+	}
+	t, err := c.Cmt(in.Paid)
+	if err != nil {
+		return &proto.Empty{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sig, err := hex.DecodeString(in.Signature)
+	if err == nil {
+		err = c.chkSig(t, c.Payer, sig)
+	}
+	if err != nil {
+		return &proto.Empty{}, status.Error(codes.InvalidArgument, "signature isn't the payer's")
+	}
+	c.Paid = in.Paid
+	c.sig = sig
+	c.sgnd[in.Paid] = true
+	return &proto.Empty{}, nil
+}
+
+// CloseChannel is called by the payer's node to close
+// a channel with its last update.
+func (s *Svc) CloseChannel(ctx context.Context, in *proto.ChannelClose) (*proto.Transaction, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c, ok := s.chnls[in.ChannelId]
+	if !ok || c.IsPayer {
+		return nil, status.Errorf(codes.NotFound, "not the payee of channel %v", in.ChannelId)
+	}
+	t, err := s.cls(c)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return t.Serialize(), nil
+}
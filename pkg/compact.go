@@ -0,0 +1,195 @@
+package pkg
+
+import (
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"context"
+	"math/rand"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sndCmpct (sendCompact) sends a block as a compact block
+// to every peer that isn't known to have it. Each peer
+// gets the transactions it isn't known to have in full,
+// and is only marked as knowing the block once it got
+// through.
+// Inputs:
+// b *block.Block the block
+func (n *Node) sndCmpct(b *block.Block) {
+	h := b.Hash()
+	for _, p := range n.PeerDb.List() {
+		if p.Known.Has(h) {
+			continue
+		}
+		cb := b.Cmpct(rand.Uint64(), func(t *tx.Transaction) bool { return !p.Known.Has(t.Hash()) })
+		utils.Debug.Printf("%v sending compact %v to %v with %v of %v transactions", utils.FmtAddr(n.Addr), b.NameTag(),
+			utils.FmtAddr(p.Addr.Addr), len(cb.Prefilled), len(b.Transactions))
+		go func(p *peer.Peer) {
+			_, err := p.Addr.SendCmpctBlkRPC(cb, address.From(n.Addr))
+			if r := valerr.FromStatus(err); r != nil {
+				utils.Debug.Printf("%v had %v rejected by %v: %v",
+					utils.FmtAddr(n.Addr), b.NameTag(), utils.FmtAddr(p.Addr.Addr), r.Reason)
+			} else if err != nil {
+				utils.Debug.Printf("%v recieved no response from SendCmpctBlkRPC to %v",
+					utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr))
+			} else {
+				p.Known.Add(h)
+			}
+		}(p)
+	}
+}
+
+// Handles send compact block request (block propagation with short transaction ids)
+func (n *Node) SendCmpctBlk(ctx context.Context, in *proto.CompactBlock) (*proto.Empty, error) {
+	p := n.PeerDb.Get(n.prSndr(ctx))
+	if p == nil {
+		return nil, status.Error(codes.PermissionDenied, "compact block is not from a peer")
+	}
+	from := p.Addr.Addr
+	if in.Header == nil {
+		return nil, status.Error(codes.InvalidArgument, "compact block has no header")
+	}
+	b := &block.Block{Hdr: block.DeserializeHdr(in.Header)}
+	h := b.Hash()
+	n.mrkKnwn(from, h)
+	if err := n.cmpctErr(b); err != nil {
+		n.Rjcts.Inc(err)
+		utils.Debug.Printf("%v rejected compact %v from %v: %v", utils.FmtAddr(n.Addr), b.NameTag(), utils.FmtAddr(from), err)
+		n.Misbhv(from, peer.BadBlk, err)
+		return nil, valerr.Status(err, h)
+	}
+	if n.hasInv(&proto.InvItem{Type: proto.InvType_BLOCK, Hash: h}) || !n.reqInv(h) {
+		return &proto.Empty{}, nil
+	}
+	go n.hndlCmpct(p.Addr, in, h)
+	return &proto.Empty{}, nil
+}
+
+// Handles get block transactions request (request for the transactions a compact block was missing)
+func (n *Node) GetBlockTxn(ctx context.Context, in *proto.GetBlockTxnRequest) (*proto.BlockTxn, error) {
+	b := n.Chain.Get(in.BlockHash)
+	if b == nil || n.Conf.ChainConf.HdrsOnly {
+		return nil, status.Errorf(codes.NotFound, "block %v could not be found", in.BlockHash)
+	}
+	res := &proto.BlockTxn{BlockHash: in.BlockHash}
+	for _, i := range in.Indexes {
+		if int(i) >= len(b.Transactions) {
+			return nil, status.Errorf(codes.InvalidArgument, "block has no transaction %v", i)
+		}
+		res.Transactions = append(res.Transactions, b.Transactions[i].Serialize())
+	}
+	return res, nil
+}
+
+// cmpctErr (compactError) checks the header of a compact
+// block before any work is done to rebuild it. It must
+// satisfy its difficulty target, and if its parent is
+// known, have the target the chain expects.
+// Inputs:
+// b *block.Block the block, with only its header
+// Returns:
+// error why the header is invalid (a *valerr.Err), or nil
+func (n *Node) cmpctErr(b *block.Block) error {
+	if n.Chain.Has(b.Hdr.PrvBlkHsh) && !n.Chain.ChkDifTrg(b) {
+		return valerr.New(valerr.BadDifTrg, "difficulty target %v is not the expected target", b.Hdr.DiffTarg)
+	}
+	if !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+		return valerr.New(valerr.BadPOW, "block does not satisfy its difficulty target")
+	}
+	return nil
+}
+
+// hndlCmpct (handleCompact) rebuilds a block from a
+// compact block, using the transactions in the node's
+// transaction pool and relay. The transactions that are
+// still missing are asked for from the node that sent
+// it. If the block still can't be rebuilt, or its parent
+// is unknown so it can't be checked yet, the whole block
+// is asked for instead. The block is then handled as if
+// it was sent in full (see HndlFwdBlk).
+// Inputs:
+// a *address.Address the address of the node that sent it
+// cb *proto.CompactBlock the compact block
+// h string the hash of the block
+func (n *Node) hndlCmpct(a *address.Address, cb *proto.CompactBlock, h string) {
+	defer func() {
+		n.inFltMutex.Lock()
+		delete(n.inFlt, h)
+		n.inFltMutex.Unlock()
+	}()
+	b := &block.Block{Hdr: block.DeserializeHdr(cb.Header)}
+	// Light nodes only need the header
+	if n.Conf.ChainConf.HdrsOnly {
+		n.HndlFwdBlk(b, a.Addr)
+		return
+	}
+	if !n.Chain.Has(b.Hdr.PrvBlkHsh) {
+		if b = n.getFll(a, h); b != nil {
+			n.HndlFwdBlk(b, a.Addr)
+		}
+		return
+	}
+	txs, miss, err := block.Rcnstr(cb, n.mmpl())
+	if err != nil {
+		err := valerr.New(valerr.Malformed, "compact block: %v", err)
+		n.Rjcts.Inc(err)
+		utils.Debug.Printf("%v rejected %v from %v: %v", utils.FmtAddr(n.Addr), b.NameTag(), utils.FmtAddr(a.Addr), err)
+		n.Misbhv(a.Addr, peer.BadCmpct, err)
+		return
+	}
+	utils.Debug.Printf("%v rebuilding %v from %v is missing %v of %v transactions",
+		utils.FmtAddr(n.Addr), b.NameTag(), utils.FmtAddr(a.Addr), len(miss), len(txs))
+	if len(miss) > 0 {
+		res, err := a.GetBlockTxnRPC(&proto.GetBlockTxnRequest{BlockHash: h, Indexes: miss}, address.From(n.Addr))
+		if err == nil && len(res.Transactions) == len(miss) {
+			for j, i := range miss {
+				txs[i] = tx.Deserialize(res.Transactions[j])
+			}
+			miss = nil
+		}
+	}
+	b.Transactions = txs
+	if len(miss) > 0 || block.CalcMrklRt(txs) != b.Hdr.MrklRt {
+		utils.Debug.Printf("%v could not rebuild %v, getting the whole block", utils.FmtAddr(n.Addr), b.NameTag())
+		if b = n.getFll(a, h); b == nil {
+			return
+		}
+	}
+	n.HndlFwdBlk(b, a.Addr)
+}
+
+// getFll (getFull) asks a node for the whole of a block.
+// Inputs:
+// a *address.Address the address of the node
+// h string the hash of the block
+// Returns:
+// *block.Block the block, or nil if the node didn't send
+// it
+func (n *Node) getFll(a *address.Address, h string) *block.Block {
+	res, err := a.GetDataRPC(&proto.GetDataRequest{BlockHash: h}, address.From(n.Addr))
+	if err != nil || res.Block == nil || res.Block.Header == nil {
+		return nil
+	}
+	if b := block.Deserialize(res.Block); b.Hash() == h {
+		return b
+	}
+	return nil
+}
+
+// mmpl (mempool) returns the transactions the node has
+// that aren't on the chain yet: those in the miner's
+// transaction pool, and those it relayed.
+func (n *Node) mmpl() []*tx.Transaction {
+	txs := n.TxRly.Txs()
+	if n.Conf.MnrConf.HasMnr {
+		txs = append(txs, n.Mnr.TxP.Txs()...)
+	}
+	return txs
+}
@@ -2,41 +2,86 @@ package pkg
 
 import (
 	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/channel"
 	"BrunoCoin/pkg/id"
 	"BrunoCoin/pkg/miner"
 	"BrunoCoin/pkg/wallet"
 	"time"
 )
 
+// MainNet is the chain id of the main network.
+const MainNet uint32 = 0x42524e43
+
+// UsrAgnt (UserAgent) is the user agent of this
+// software.
+const UsrAgnt = "/BrunoCoin:1.0/"
+
 // Config is the configuration for the node.
 // IdConf is the configuration for the id,
 // MnrConf is the configuration for the miner,
 // WtConf is the configuration for the wallet,
 // ChainConf is the configuration for the blockchain,
+// ChnlConf is the configuration for payment channels,
 // Version is the version that the node is (used for
-// software updates),
+// software updates), which is the protocol version it
+// sends in its version message,
+// MinVer (MinimumVersion) is the oldest protocol version
+// the node peers with,
+// UsrAgnt (UserAgent) is the software the node tells its
+// peers it runs,
+// ChainID is the network the node is on (see MainNet),
 // PeerLimit is the maximum amount of peers the node
 // is allowed to have,
 // AddrLimit is the maximum amount of addresses the
 // node is allowed to keep track of.
 // Port is the port that the node should run on,
 // MxBlkSz is the maximum allowed block size,
+// OrphLim is the maximum amount of orphan blocks
+// (blocks whose previous block is unknown) the node
+// holds on to while it fetches their ancestors.
+// MxTmDrft (MaxTimeDrift) is how far into the future
+// a block's timestamp is allowed to be.
+// PrfLim (ProofLimit) is the most merkle proofs the node
+// sends in reply to one GetMerkleProofs request. The
+// blocks after the one that reached it are left for the
+// next request.
+// CmpctBlks (CompactBlocks) True if new blocks are sent
+// to peers as compact blocks, otherwise they are
+// announced (see Node.BroadcastBlk).
+// BanScr (BanScore) is the misbehavior score at which a
+// peer is disconnected and banned (see Node.Misbhv).
+// BanTm (BanTime) is how long a ban lasts.
+// AdmnPort (AdminPort) is the port the node serves the
+// Admin service on, on the loopback interface only, so
+// that only the computer it runs on can manage its bans.
+// 0 if the node doesn't serve it.
 type Config struct {
 	IdConf    *id.Config
 	MnrConf   *miner.Config
 	WtConf    *wallet.Config
 	ChainConf *blockchain.Config
+	ChnlConf  *channel.Config
 
 	CstmID    bool
 	CstmIDObj id.ID
 
-	Version    int
-	PeerLimit  int
-	AddrLimit  int
-	Port       int
-	VerTimeout time.Duration
+	Version   int
+	MinVer    int
+	UsrAgnt   string
+	ChainID   uint32
+	PeerLimit int
+	AddrLimit int
+	Port      int
+
+	MxBlkSz   uint32
+	OrphLim   int
+	MxTmDrft  time.Duration
+	PrfLim    int
+	CmpctBlks bool
 
-	MxBlkSz uint32
+	BanScr   uint32
+	BanTm    time.Duration
+	AdmnPort int
 }
 
 // DefaultConfig creates a Config object that
@@ -50,32 +95,50 @@ type Config struct {
 // on
 func DefaultConfig(port int) *Config {
 	c := &Config{
-		IdConf:     id.DefaultConfig(),
-		MnrConf:    miner.DefaultConfig(-1),
-		WtConf:     wallet.DefaultConfig(),
-		ChainConf:  blockchain.DefaultConfig(),
-		Version:    0,
-		PeerLimit:  20,
-		AddrLimit:  1000,
-		Port:       port,
-		VerTimeout: time.Second * 2,
-		MxBlkSz:    10000000,
+		IdConf:    id.DefaultConfig(),
+		MnrConf:   miner.DefaultConfig(-1),
+		WtConf:    wallet.DefaultConfig(),
+		ChainConf: blockchain.DefaultConfig(),
+		ChnlConf:  channel.DefaultConfig(),
+		Version:   0,
+		MinVer:    0,
+		UsrAgnt:   UsrAgnt,
+		ChainID:   MainNet,
+		PeerLimit: 20,
+		AddrLimit: 1000,
+		Port:      port,
+		MxBlkSz:   10000000,
+		OrphLim:   100,
+		MxTmDrft:  time.Hour * 2,
+		PrfLim:    500,
+		CmpctBlks: true,
+		BanScr:    100,
+		BanTm:     time.Hour * 24,
 	}
 	return c
 }
 
 func TestingConfig(port int) *Config {
 	c := &Config{
-		IdConf:     id.DefaultConfig(),
-		MnrConf:    miner.DefaultConfig(-1),
-		WtConf:     wallet.DefaultConfig(),
-		ChainConf:  blockchain.DefaultConfig(),
-		Version:    0,
-		PeerLimit:  20,
-		AddrLimit:  1000,
-		Port:       port,
-		VerTimeout: time.Second * 2,
-		MxBlkSz:    10000000,
+		IdConf:    id.DefaultConfig(),
+		MnrConf:   miner.DefaultConfig(-1),
+		WtConf:    wallet.DefaultConfig(),
+		ChainConf: blockchain.DefaultConfig(),
+		ChnlConf:  channel.DefaultConfig(),
+		Version:   0,
+		MinVer:    0,
+		UsrAgnt:   UsrAgnt,
+		ChainID:   MainNet,
+		PeerLimit: 20,
+		AddrLimit: 1000,
+		Port:      port,
+		MxBlkSz:   10000000,
+		OrphLim:   100,
+		MxTmDrft:  time.Hour * 2,
+		PrfLim:    500,
+		CmpctBlks: true,
+		BanScr:    100,
+		BanTm:     time.Hour * 24,
 	}
 	return c
 }
@@ -87,16 +150,25 @@ func TestingConfig(port int) *Config {
 // on
 func NilConfig(port int) *Config {
 	return &Config{
-		IdConf:     id.DefaultConfig(),
-		MnrConf:    miner.NilConfig(-1),
-		WtConf:     wallet.NilConfig(),
-		ChainConf:  blockchain.NilConfig(),
-		Version:    0,
-		PeerLimit:  20,
-		AddrLimit:  1000,
-		Port:       port,
-		VerTimeout: time.Second * 2,
-		MxBlkSz:    10000000,
+		IdConf:    id.DefaultConfig(),
+		MnrConf:   miner.NilConfig(-1),
+		WtConf:    wallet.NilConfig(),
+		ChainConf: blockchain.NilConfig(),
+		ChnlConf:  channel.NilConfig(),
+		Version:   0,
+		MinVer:    0,
+		UsrAgnt:   UsrAgnt,
+		ChainID:   MainNet,
+		PeerLimit: 20,
+		AddrLimit: 1000,
+		Port:      port,
+		MxBlkSz:   10000000,
+		OrphLim:   100,
+		MxTmDrft:  time.Hour * 2,
+		PrfLim:    500,
+		CmpctBlks: true,
+		BanScr:    100,
+		BanTm:     time.Hour * 24,
 	}
 }
 
@@ -107,16 +179,25 @@ func NilConfig(port int) *Config {
 // on
 func NoMnrConfig(port int) *Config {
 	return &Config{
-		IdConf:     id.DefaultConfig(),
-		MnrConf:    miner.NilConfig(-1),
-		WtConf:     wallet.DefaultConfig(),
-		ChainConf:  blockchain.DefaultConfig(),
-		Version:    1,
-		PeerLimit:  20,
-		AddrLimit:  1000,
-		Port:       port,
-		VerTimeout: time.Second * 2,
-		MxBlkSz:    10000000,
+		IdConf:    id.DefaultConfig(),
+		MnrConf:   miner.NilConfig(-1),
+		WtConf:    wallet.DefaultConfig(),
+		ChainConf: blockchain.DefaultConfig(),
+		ChnlConf:  channel.DefaultConfig(),
+		Version:   1,
+		MinVer:    0,
+		UsrAgnt:   UsrAgnt,
+		ChainID:   MainNet,
+		PeerLimit: 20,
+		AddrLimit: 1000,
+		Port:      port,
+		MxBlkSz:   10000000,
+		OrphLim:   100,
+		MxTmDrft:  time.Hour * 2,
+		PrfLim:    500,
+		CmpctBlks: true,
+		BanScr:    100,
+		BanTm:     time.Hour * 24,
 	}
 }
 
@@ -127,16 +208,40 @@ func NoMnrConfig(port int) *Config {
 // on
 func SmallTxPConfig(port int) *Config {
 	c := &Config{
-		IdConf:     id.DefaultConfig(),
-		MnrConf:    miner.SmallTxPCapConfig(-1),
-		WtConf:     wallet.DefaultConfig(),
-		ChainConf:  blockchain.DefaultConfig(),
-		Version:    0,
-		PeerLimit:  20,
-		AddrLimit:  1000,
-		Port:       port,
-		VerTimeout: time.Second * 2,
-		MxBlkSz:    10000000,
+		IdConf:    id.DefaultConfig(),
+		MnrConf:   miner.SmallTxPCapConfig(-1),
+		WtConf:    wallet.DefaultConfig(),
+		ChainConf: blockchain.DefaultConfig(),
+		ChnlConf:  channel.DefaultConfig(),
+		Version:   0,
+		MinVer:    0,
+		UsrAgnt:   UsrAgnt,
+		ChainID:   MainNet,
+		PeerLimit: 20,
+		AddrLimit: 1000,
+		Port:      port,
+		MxBlkSz:   10000000,
+		OrphLim:   100,
+		MxTmDrft:  time.Hour * 2,
+		PrfLim:    500,
+		CmpctBlks: true,
+		BanScr:    100,
+		BanTm:     time.Hour * 24,
 	}
 	return c
 }
+
+// LightConfig is a configuration for a light node,
+// which only keeps block headers and has no miner
+// or payment channels. Its wallet tracks its own
+// utxo with merkle proofs from full nodes.
+// Inputs:
+// port int the port that the node should start
+// on
+func LightConfig(port int) *Config {
+	c := DefaultConfig(port)
+	c.MnrConf = miner.NilConfig(-1)
+	c.ChainConf = blockchain.LightConfig()
+	c.ChnlConf = channel.NilConfig()
+	return c
+}
@@ -0,0 +1,318 @@
+package pkg
+
+import (
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	grpcpeer "google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// The reasons a version message is rejected, sent as
+// the code of a proto.Rejection.
+const (
+	// RjctMlfrmd (RejectMalformed) means the version
+	// message is missing the node's address.
+	RjctMlfrmd = "malformed"
+	// RjctAddr (RejectAddress) means the node's address
+	// is not on the host the version message came from.
+	RjctAddr = "wrong-address"
+	// RjctVer (RejectVersion) means the node's protocol
+	// version is older than Config.MinVer.
+	RjctVer = "obsolete-version"
+	// RjctChn (RejectChain) means the node is on another
+	// network (see Config.ChainID).
+	RjctChn = "wrong-chain"
+	// RjctSlf (RejectSelf) means the node connected to
+	// itself.
+	RjctSlf = "self-connection"
+	// RjctBan (RejectBanned) means the node is banned.
+	RjctBan = "banned"
+	// RjctFull (RejectFull) means the node has no room
+	// for more peers or addresses.
+	RjctFull = "full"
+)
+
+// rjctVer (rejectVersion) returns the error a version
+// message is rejected with: a gRPC status error carrying
+// a proto.Rejection, so the node that sent it can find
+// out why (see valerr.FromStatus).
+// Inputs:
+// code string the reason code, like RjctVer
+// format string, a ...interface{} the reason, as for
+// fmt.Sprintf
+func rjctVer(code string, format string, a ...interface{}) error {
+	msg := fmt.Sprintf(format, a...)
+	st := status.New(codes.FailedPrecondition, msg)
+	dst, err := st.WithDetails(&proto.Rejection{Code: code, Reason: msg})
+	if err != nil {
+		return st.Err()
+	}
+	return dst.Err()
+}
+
+// mkNonce (makeNonce) returns a random nonce for a node's
+// version messages. It isn't taken from math/rand, which
+// gives every process the same numbers unless seeded.
+func mkNonce() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return uint64(time.Now().UnixNano())
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Svcs (Services) returns the services the node offers
+// its peers, from its config.
+func (n *Node) Svcs() peer.Svcs {
+	var s peer.Svcs
+	if n.Conf.ChainConf.HdrsOnly {
+		s |= peer.SvcLght
+	} else if n.Conf.ChainConf.HasChn {
+		s |= peer.SvcChn
+	}
+	if n.Conf.MnrConf.HasMnr {
+		s |= peer.SvcMnr
+	}
+	if n.Conf.WtConf.HasWt {
+		s |= peer.SvcWt
+	}
+	return s
+}
+
+// verMsg (versionMessage) returns the node's version
+// message to the node at addr.
+// Inputs:
+// addr string the address of the node
+// tkn uint64 the token that node's requests to this node
+// will carry (see Peer.InTkn)
+func (n *Node) verMsg(addr string, tkn uint64) *proto.VersionRequest {
+	return &proto.VersionRequest{
+		Version:    uint32(n.Conf.Version),
+		AddrYou:    addr,
+		AddrMe:     n.Addr,
+		BestHeight: uint32(n.Chain.Length()),
+		Services:   uint64(n.Svcs()),
+		UserAgent:  n.Conf.UsrAgnt,
+		ChainId:    n.Conf.ChainID,
+		Nonce:      n.nonce,
+		Token:      tkn,
+	}
+}
+
+// trnsprt (transport) returns the IP that a request came
+// from, or "" if it is unknown.
+func trnsprt(ctx context.Context) string {
+	if p, ok := grpcpeer.FromContext(ctx); ok {
+		if a, ok := p.Addr.(*net.TCPAddr); ok {
+			return a.IP.String()
+		}
+	}
+	return ""
+}
+
+// onHst (onHost) returns whether the host of addr
+// resolves to ip.
+func onHst(addr string, ip string) bool {
+	h, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	ips, err := net.LookupIP(h)
+	if err != nil {
+		return false
+	}
+	for _, i := range ips {
+		if i.String() == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// prSndr (peerSender) returns the address of the peer
+// that a request came from, if the request carries the
+// token this node gave that peer during the handshake.
+// Otherwise the sender is unknown, since anyone can
+// claim any address (see address.From), and "" is
+// returned. Only known senders are scored for
+// misbehaving (see Misbhv).
+func (n *Node) prSndr(ctx context.Context) string {
+	a := address.Sender(ctx)
+	if p := n.PeerDb.Get(a); p != nil && p.InTkn != 0 && p.InTkn == address.SenderTkn(ctx) {
+		return a
+	}
+	return ""
+}
+
+// chkVer (checkVersion) checks that the node can peer
+// with the node that sent a version message.
+// Inputs:
+// v *proto.VersionRequest the version message
+// Returns:
+// error why it can't (see rjctVer), or nil
+func (n *Node) chkVer(v *proto.VersionRequest) error {
+	if v == nil || v.AddrMe == "" {
+		return rjctVer(RjctMlfrmd, "version message has no address")
+	}
+	if v.Nonce == n.nonce {
+		return rjctVer(RjctSlf, "%v is this node", v.AddrMe)
+	}
+	if v.ChainId != n.Conf.ChainID {
+		return rjctVer(RjctChn, "chain id %#x is not %#x", v.ChainId, n.Conf.ChainID)
+	}
+	if int(v.Version) < n.Conf.MinVer {
+		return rjctVer(RjctVer, "protocol version %v is older than %v", v.Version, n.Conf.MinVer)
+	}
+	if n.Bans.Has(v.AddrMe) {
+		return rjctVer(RjctBan, "%v is banned", v.AddrMe)
+	}
+	return nil
+}
+
+// PndTmOut (PendingTimeout) is how long a node whose
+// version message was acknowledged has to accept the
+// verack before it is forgotten (see SendVerack).
+const PndTmOut = 10 * time.Second
+
+// mkPeer (makePeer) makes a peer of the node that sent a
+// version message, after checking it (see chkVer).
+// Inputs:
+// addr string the address of the node
+// v *proto.VersionRequest the version message
+// tkn uint64 the token this node gave it
+// Returns:
+// *peer.Peer the peer, which isn't added yet
+// error why it can't be a peer (see rjctVer), or nil
+func (n *Node) mkPeer(addr string, v *proto.VersionRequest, tkn uint64) (*peer.Peer, error) {
+	if err := n.chkVer(v); err != nil {
+		return nil, err
+	}
+	p := peer.New(address.New(addr, uint32(time.Now().UnixNano())), v.Version, v.BestHeight)
+	p.Svcs = peer.Svcs(v.Services)
+	p.UsrAgnt = v.UserAgent
+	p.InTkn = tkn
+	p.Conn = peer.NewTknConn(addr, v.Token)
+	return p, nil
+}
+
+// addPeer adds a peer made by mkPeer. A peer that was
+// already added is replaced, since the tokens of the
+// new handshake are the ones both sides now use.
+// Inputs:
+// p *peer.Peer the peer
+// Returns:
+// error why it wasn't added (see rjctVer), or nil
+func (n *Node) addPeer(p *peer.Peer) error {
+	a := p.Addr
+	if n.AddrDb.Get(a.Addr) != nil {
+		if err := n.AddrDb.UpdateLastSeen(a.Addr, a.LastSeen); err != nil {
+			return rjctVer(RjctFull, "could not update %v: %v", a.Addr, err)
+		}
+	} else if err := n.AddrDb.Add(a); err != nil {
+		return rjctVer(RjctFull, "could not keep %v: %v", a.Addr, err)
+	}
+	p.Addr = n.AddrDb.Get(a.Addr)
+	n.PeerDb.Rmv(a.Addr)
+	if !n.PeerDb.Add(p) {
+		return rjctVer(RjctFull, "no room for more peers")
+	}
+	utils.Debug.Printf("%v peered with %v %v", utils.FmtAddr(n.Addr), utils.FmtAddr(a.Addr), p.UsrAgnt)
+	return nil
+}
+
+// addPndng (addPending) holds on to a peer made by mkPeer
+// until it accepts this node's verack (see SendVerack),
+// or PndTmOut passes.
+// Inputs:
+// p *peer.Peer the peer
+// Returns:
+// error if too many nodes are already pending (see
+// rjctVer), or nil
+func (n *Node) addPndng(p *peer.Peer) error {
+	n.pndMutex.Lock()
+	defer n.pndMutex.Unlock()
+	if _, ok := n.pndng[p.Addr.Addr]; !ok && len(n.pndng) >= n.Conf.PeerLimit {
+		return rjctVer(RjctFull, "no room for more pending peers")
+	}
+	n.pndng[p.Addr.Addr] = p
+	time.AfterFunc(PndTmOut, func() {
+		n.pndMutex.Lock()
+		if n.pndng[p.Addr.Addr] == p {
+			delete(n.pndng, p.Addr.Addr)
+		}
+		n.pndMutex.Unlock()
+	})
+	return nil
+}
+
+// takePndng (takePending) removes and returns the pending
+// peer at addr, if the request came from it: it has to
+// carry the token this node gave it in its verack.
+// Inputs:
+// addr string the address of the node
+// tkn uint64 the token the request carried
+// Returns:
+// *peer.Peer the peer, or nil
+func (n *Node) takePndng(addr string, tkn uint64) *peer.Peer {
+	n.pndMutex.Lock()
+	defer n.pndMutex.Unlock()
+	p := n.pndng[addr]
+	if p == nil || p.InTkn != tkn {
+		return nil
+	}
+	delete(n.pndng, addr)
+	return p
+}
+
+// ConnectToPeer peers with the node at addr. The node
+// sends its version message, and the other node
+// acknowledges it with its own if they can peer (see
+// chkVer). If this node can peer with it too, it adds
+// it as a peer and accepts the verack (see SendVerack),
+// so the other node adds this one. Each version message
+// carries a new token, which the other node's requests
+// then carry (see prSndr).
+// Inputs:
+// addr string the address of the node
+// Returns:
+// error why they didn't peer, or nil
+func (n *Node) ConnectToPeer(addr string) error {
+	if n.Bans.Has(addr) {
+		return rjctVer(RjctBan, "%v is banned", addr)
+	}
+	tkn := mkNonce()
+	ack, err := address.New(addr, 0).VersionRPC(n.verMsg(addr, tkn))
+	if r := valerr.FromStatus(err); r != nil {
+		utils.Debug.Printf("%v was refused by %v: %v", utils.FmtAddr(n.Addr), utils.FmtAddr(addr), r.Reason)
+		return err
+	} else if err != nil {
+		utils.Debug.Printf("%v recieved no response from VersionRPC to %v",
+			utils.FmtAddr(n.Addr), utils.FmtAddr(addr))
+		return err
+	}
+	p, err := n.mkPeer(addr, ack.Version, tkn)
+	if err == nil {
+		err = n.addPeer(p)
+	}
+	if err != nil {
+		utils.Debug.Printf("%v refused the verack of %v: %v", utils.FmtAddr(n.Addr), utils.FmtAddr(addr), err)
+		return err
+	}
+	if _, err := p.Addr.SendVerackRPC(&proto.Empty{}, address.From(n.Addr)); err != nil {
+		utils.Debug.Printf("%v could not accept the verack of %v: %v", utils.FmtAddr(n.Addr), utils.FmtAddr(addr), err)
+		n.PeerDb.Rmv(addr)
+		return err
+	}
+	return nil
+}
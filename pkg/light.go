@@ -0,0 +1,202 @@
+package pkg
+
+import (
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"errors"
+	"fmt"
+)
+
+// SyncHdrs (SyncHeaders) brings the chain of a light
+// node (see blockchain.Config.HdrsOnly) up to date.
+// Every peer is asked for the headers past where its
+// main chain forks from this node's, and the valid
+// reply with the most work is added (see bstHdrs),
+// until the peers have no more.
+// Returns:
+// error if there are no peers, or none sent valid
+// headers
+func (n *Node) SyncHdrs() error {
+	skp := make(map[string]bool)
+	for {
+		top := n.Chain.GetLastBlock().Hash()
+		r, err := n.bstHdrs(skp)
+		if err != nil {
+			return err
+		}
+		hdrs := r.hdrs
+		if len(hdrs) == 0 {
+			return nil
+		}
+		for _, h := range hdrs {
+			n.Chain.AddHdr(h)
+		}
+		utils.Debug.Printf("%v synced %v headers, top block %v", utils.FmtAddr(n.Addr), len(hdrs), n.Chain.GetLastBlock().NameTag())
+		if len(hdrs) < MxHdrs || n.Chain.GetLastBlock().Hash() == top {
+			return nil
+		}
+	}
+}
+
+// HndlNwHdr (HandleNetworkHeader) handles a block from
+// the network on a light node, which only keeps its
+// header. Headers too far in the future are rejected
+// (see chkDrft). If the previous block is unknown, the
+// node catches up with SyncHdrs instead. When the main chain
+// gets a new last block, the wallet is synced.
+// Inputs:
+// b *block.Block the block from the network
+// from string the address of the node that sent it
+// Returns:
+// error why the header is invalid (a *valerr.Err), or nil
+func (n *Node) HndlNwHdr(b *block.Block, from string) error {
+	var tip bool
+	err := n.chkDrft(b.Hdr)
+	if err == nil {
+		if !n.Chain.Has(b.Hdr.PrvBlkHsh) {
+			go n.syncLght()
+			return nil
+		}
+		tip, err = n.Chain.AddHdr(b.Hdr)
+	}
+	if err != nil {
+		n.Rjcts.Inc(err)
+		utils.Debug.Printf("%v rejected header of %v from %v: %v", utils.FmtAddr(n.Addr), b.NameTag(), utils.FmtAddr(from), err)
+		n.Misbhv(from, peer.BadBlk, err)
+		return err
+	}
+	if tip && n.Wallet != nil {
+		go n.SyncWt()
+	}
+	return nil
+}
+
+// syncLght (syncLight) syncs the headers of a light node,
+// and then its wallet.
+func (n *Node) syncLght() error {
+	if err := n.SyncHdrs(); err != nil {
+		utils.Debug.Printf("%v could not sync headers: %v", utils.FmtAddr(n.Addr), err)
+		return err
+	}
+	if n.Wallet == nil {
+		return nil
+	}
+	return n.SyncWt()
+}
+
+// SyncWt (SyncWallet) finds the transactions on the main
+// chain that pay or spend the wallet of a light node,
+// past the last block it looked at. A peer with the full
+// chain is asked for them with their merkle proofs (see
+// GetMerkleProofs), and the wallet only gets the
+// transactions whose proofs lead to the merkle root of a
+// block on the main chain.
+// Blocks the wallet looked at that a reorganization
+// took off of the main chain are rolled back first, and
+// looked at again from the fork (see LghtUTXO.Rllbck).
+// If a peer sends a bad proof, the next peer is asked
+// (see syncFrm).
+// Returns:
+// error if the node has no light wallet, or no peer
+// sent valid proofs
+func (n *Node) SyncWt() error {
+	if n.Wallet == nil || n.Wallet.Lght == nil {
+		return errors.New("node has no light wallet")
+	}
+	n.syncMutex.Lock()
+	defer n.syncMutex.Unlock()
+	l := n.Wallet.Lght
+	if f := l.Frk(n.hshAt); f < l.Hght {
+		utils.Debug.Printf("%v rolling its wallet back from height %v to %v", utils.FmtAddr(n.Addr), l.Hght, f)
+		n.Wallet.HndlUnconf(l.Rllbck(f))
+	}
+	for _, p := range n.fllPrs() {
+		if err := n.syncFrm(p); err != nil {
+			utils.Debug.Printf("%v could not sync its wallet from %v: %v", utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr), err)
+			continue
+		}
+		return nil
+	}
+	return errors.New("no peer sent valid proofs")
+}
+
+// syncFrm (syncFrom) asks a peer for the merkle proofs of
+// the wallet's transactions a page at a time (see
+// Config.PrfLim), until the wallet has looked at every
+// block of the main chain the peer has. The wallet's
+// filter is made again for each page, so that it matches
+// the outputs found on the pages before.
+// Inputs:
+// p *peer.Peer the peer, which has the full chain
+// Returns:
+// error if the peer didn't answer or sent a bad proof
+func (n *Node) syncFrm(p *peer.Peer) error {
+	l := n.Wallet.Lght
+	for {
+		tip := uint32(n.Chain.Length() - 1)
+		if l.Hght >= tip {
+			return nil
+		}
+		lcks, outs := n.Wallet.Fltr()
+		req := &proto.GetMerkleProofsRequest{LockingScripts: lcks, Outpoints: outs, FromHeight: l.Hght + 1}
+		res, err := p.Addr.GetMerkleProofsRPC(req, address.From(n.Addr))
+		if err != nil {
+			return err
+		}
+		if res.Height < tip {
+			tip = res.Height
+		}
+		for _, pr := range res.Proofs {
+			if pr.Height > tip {
+				break
+			}
+			if err := n.chkPrf(pr); err != nil {
+				return fmt.Errorf("bad proof: %v", err)
+			}
+			n.Wallet.HndlCnfTx(tx.Deserialize(pr.Transaction), pr.Height)
+		}
+		// The peer doesn't have the blocks past tip
+		if tip <= l.Hght {
+			return nil
+		}
+		var hshs []string
+		for _, b := range n.Chain.Slice(int(l.Hght)+1, int(tip)+1) {
+			hshs = append(hshs, b.Hash())
+		}
+		l.Adv(l.Hght+1, hshs)
+	}
+}
+
+// hshAt (hashAt) returns the hash of the block on the
+// main chain at a height, or "" if there is none.
+func (n *Node) hshAt(h uint32) string {
+	if blks := n.Chain.Slice(int(h), int(h)+1); len(blks) == 1 {
+		return blks[0].Hash()
+	}
+	return ""
+}
+
+// chkPrf (checkProof) checks that a merkle proof is for
+// a transaction on a block of the main chain.
+// Inputs:
+// p *proto.MerkleProof the proof
+// Returns:
+// error why the proof is invalid, or nil
+func (n *Node) chkPrf(p *proto.MerkleProof) error {
+	if p.Header == nil || p.Transaction == nil {
+		return errors.New("proof has no header or transaction")
+	}
+	hdr := block.DeserializeHdr(p.Header)
+	h := (&block.Block{Hdr: hdr}).Hash()
+	if blks := n.Chain.Slice(int(p.Height), int(p.Height)+1); len(blks) != 1 || blks[0].Hash() != h {
+		return fmt.Errorf("block %v is not on the main chain at height %v", h, p.Height)
+	}
+	if t := tx.Deserialize(p.Transaction); !hdr.ChkMrklPrf(t.Hash(), int(p.Index), p.Branch) {
+		return fmt.Errorf("branch of %v doesn't lead to the merkle root", t.NameTag())
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package miner
 
 import (
+	"BrunoCoin/pkg/block/tx"
 	"BrunoCoin/pkg/utils"
 	"math"
 )
@@ -10,32 +11,34 @@ import (
 // HasMnr (HasMiner) defines whether or not
 // the node will have a miner.
 // Ver (Ver) defines the software version
-// of the node.
+// of the node, which is the version of the
+// coinbase transactions it makes.
 // DefLckTm (DefineLockTime) defines the lock
 // time that should be on the coinbase transaction.
 // TxPCap defines the maximum number of
 // transactions allowed in the transaction pool.
 // PriLim defines the priority threshold that
 // must be met for the miner to start mining a
-// group of transactions
-// BlkSz defines the maximum size a block can be.
+// group of transactions. Priorities are fees per
+// 100 bytes (see CalcPri), and a payment is about
+// 300 bytes, so the default of 2 is met by a
+// single payment with a fee of 10.
+// BlkSz defines the maximum size a block can be,
+// in bytes of its canonical encoding. The default
+// of 4000 fits about a dozen payments.
 // NncLim defines the maximum nonce that miners
 // are willing to mine to.
-// InitSubsdy defines the initial subsidy given
-// to miners for the minting reward before any
-// havlings.
-// SubsdyHlvRt defines the rate (in terms of
-// blocks added) at which the initial subsidy
-// is havled.
-// MxHlvgs defines the maximum number of
-// halvings that are allowed before the subsidy
-// becomes 0.
 // InitPOWD represents the inital proof of
 // work difficulty. This is helpful in the
 // config because now some nodes can be toggled
 // to have a higher proof of work than others,
 // which is essentially adjusting the speeds of miners
-// on the network.
+// on the network. It is only used until the node sets
+// the target the blockchain expects (see SetDifTrg).
+// RBF (ReplaceByFee) defines whether a transaction that
+// spends the same output as transactions already in the
+// transaction pool replaces them when it pays a higher
+// fee. Otherwise, the first transaction seen is kept.
 type Config struct {
 	HasMnr bool
 
@@ -48,27 +51,24 @@ type Config struct {
 	BlkSz  uint32
 	NncLim uint32
 
-	InitSubsdy  uint32
-	SubsdyHlvRt uint32
-	MxHlvgs     uint32
-	InitPOWD    string
+	InitPOWD string
+
+	RBF bool
 }
 
 // DefaultConfig returns the default settings
 // for the Miner.
 func DefaultConfig(powdNumZeros int) *Config {
 	return &Config{
-		HasMnr:      true,
-		Ver:         0,
-		DefLckTm:    0,
-		TxPCap:      50,
-		PriLim:      10,
-		BlkSz:       1000,
-		NncLim:      uint32(math.Pow(2, 20)),
-		InitSubsdy:  10,
-		SubsdyHlvRt: 10,
-		MxHlvgs:     10,
-		InitPOWD:    utils.CalcPOWD(powdNumZeros),
+		HasMnr:   true,
+		Ver:      tx.EncVer,
+		DefLckTm: 0,
+		TxPCap:   50,
+		PriLim:   2,
+		BlkSz:    4000,
+		NncLim:   uint32(math.Pow(2, 20)),
+		InitPOWD: utils.CalcPOWD(powdNumZeros),
+		RBF:      false,
 	}
 }
 
@@ -78,17 +78,15 @@ func DefaultConfig(powdNumZeros int) *Config {
 // mining functionality.
 func NilConfig(powdNumZeros int) *Config {
 	return &Config{
-		HasMnr:      false,
-		Ver:         0,
-		DefLckTm:    0,
-		TxPCap:      50,
-		PriLim:      10,
-		BlkSz:       1000,
-		NncLim:      uint32(math.Pow(2, 20)),
-		InitSubsdy:  10,
-		SubsdyHlvRt: 10,
-		MxHlvgs:     10,
-		InitPOWD:    utils.CalcPOWD(powdNumZeros),
+		HasMnr:   false,
+		Ver:      tx.EncVer,
+		DefLckTm: 0,
+		TxPCap:   50,
+		PriLim:   2,
+		BlkSz:    4000,
+		NncLim:   uint32(math.Pow(2, 20)),
+		InitPOWD: utils.CalcPOWD(powdNumZeros),
+		RBF:      false,
 	}
 }
 
@@ -97,16 +95,14 @@ func NilConfig(powdNumZeros int) *Config {
 // except for a very small txpool cap
 func SmallTxPCapConfig(powdNumZeros int) *Config {
 	return &Config{
-		HasMnr:      true,
-		Ver:         0,
-		DefLckTm:    0,
-		TxPCap:      1,
-		PriLim:      10,
-		BlkSz:       1000,
-		NncLim:      uint32(math.Pow(2, 20)),
-		InitSubsdy:  10,
-		SubsdyHlvRt: 10,
-		MxHlvgs:     10,
-		InitPOWD:    utils.CalcPOWD(powdNumZeros),
+		HasMnr:   true,
+		Ver:      tx.EncVer,
+		DefLckTm: 0,
+		TxPCap:   1,
+		PriLim:   2,
+		BlkSz:    4000,
+		NncLim:   uint32(math.Pow(2, 20)),
+		InitPOWD: utils.CalcPOWD(powdNumZeros),
+		RBF:      false,
 	}
 }
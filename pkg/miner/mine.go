@@ -3,11 +3,11 @@ package miner
 import (
 	"BrunoCoin/pkg/block"
 	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/blockchain"
 	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
 	"BrunoCoin/pkg/utils"
 	"context"
-	"encoding/hex"
-	"math"
 )
 
 // Mine waits to be told to mine a block
@@ -29,20 +29,23 @@ func (m *Miner) Mine() {
 			if !m.TxP.PriMet() {
 				return
 			}
-			m.Mining.Store(true)
 			m.MiningPool = m.NewMiningPool()
+			if len(m.MiningPool) == 0 {
+				return
+			}
+			m.Mining.Store(true)
 			txs := append([]*tx.Transaction{m.GenCBTx(m.MiningPool)}, m.MiningPool...)
 			b := block.New(m.PrvHsh, txs, m.DifTrg())
 			result := m.CalcNonce(ctx, b)
 			m.Mining.Store(false)
 			if result {
 				utils.Debug.Printf("%v mined %v %v", utils.FmtAddr(m.Addr), b.NameTag(), b.Summarize())
+				// The node adds the block to the chain and
+				// tells the miner about the new last block.
 				m.SendBlk <- b
-				m.HndlBlk(b)
 			}
 		}(ctx)
 	}
-	cancel()
 }
 
 // Returns boolean to indicate success
@@ -53,7 +56,7 @@ func (m *Miner) CalcNonce(ctx context.Context, b *block.Block) bool {
 			return false
 		default:
 			b.Hdr.Nonce = i
-			if b.SatisfiesPOW(m.DifTrg()) {
+			if b.SatisfiesPOW(b.Hdr.DiffTarg) {
 				return true
 			}
 		}
@@ -61,13 +64,49 @@ func (m *Miner) CalcNonce(ctx context.Context, b *block.Block) bool {
 	return false
 }
 
-// DifTrg (DifficultyTarget) calculates the
-// current difficulty target.
+// DifTrg (DifficultyTarget) returns the
+// difficulty target for the next block, which
+// is set by the node from the blockchain. If it
+// wasn't set, InitPOWD is used.
 // Returns:
 // string the difficulty target as a hex
 // string
 func (m *Miner) DifTrg() string {
-	return m.Conf.InitPOWD
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.difTrg == "" {
+		return m.Conf.InitPOWD
+	}
+	return m.difTrg
+}
+
+// SetDifTrg (SetDifficultyTarget) sets the
+// difficulty target for the next block.
+// Inputs:
+// dt string the difficulty target as a hex
+// string, or "" to use InitPOWD
+func (m *Miner) SetDifTrg(dt string) {
+	m.mutex.Lock()
+	m.difTrg = dt
+	m.mutex.Unlock()
+}
+
+// MedTm (MedianTime) returns the median time past
+// of the last block on the main chain.
+func (m *Miner) MedTm() uint32 {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.medTm
+}
+
+// SetMedTm (SetMedianTime) sets the median time
+// past of the last block on the main chain.
+// Inputs:
+// tm uint32 the median time past
+func (m *Miner) SetMedTm(tm uint32) {
+	m.mutex.Lock()
+	m.medTm = tm
+	m.mutex.Unlock()
 }
 
 // GenCBTx (GenerateCoinbaseTransaction) generates a coinbase
@@ -98,16 +137,10 @@ func (m *Miner) GenCBTx(txs []*tx.Transaction) *tx.Transaction {
 
 	fee := inputs - outputs
 
-	divisor := m.ChnLen.Load() / m.Conf.SubsdyHlvRt
-
-	if divisor > m.Conf.MxHlvgs {
-		mintingReward = 0
-	} else {
-		mintingReward = m.Conf.InitSubsdy / uint32((math.Pow(2, float64(divisor))))
-	}
+	mintingReward = blockchain.CalcSubsdy(m.ChnConf, m.ChnLen.Load())
 
 	reward := mintingReward + fee
-	PCBTxO := []*proto.TransactionOutput{proto.NewTxOutpt(reward, hex.EncodeToString(m.Id.GetPublicKeyBytes()))}
+	PCBTxO := []*proto.TransactionOutput{proto.NewTxOutpt(reward, script.P2PKH(m.Id.GetPublicKeyBytes()))}
 	PCBTx := proto.NewTx(m.Conf.Ver, []*proto.TransactionInput{}, PCBTxO, m.Conf.DefLckTm)
 	CBTx := tx.Deserialize(PCBTx)
 
@@ -14,6 +14,7 @@ import (
 
 // Miner supports the functionality of mining new transactions broadcast from the network to a new block.
 // Conf represents the configuration (settings) for the miner.
+// ChnConf (ChainConfig) is the configuration of the blockchain, which has the subsidy schedule.
 // Id represents the identity of the miner, so that the miner can properly make the coinbase transaction.
 // TxP contains all transactions that the miner is either waiting to mine, or is mining.
 // MiningPool contains all transactions that the miner is currently mining.
@@ -23,9 +24,13 @@ import (
 // Mining tells whether the miner is currently mining.
 // SendBlk is used to send newly mined blocks to the node in order to be broadcast on the network.
 // PoolUpdated is used to send alerts of pool updates to the miner
+// difTrg is the difficulty target that the blockchain requires for the next block.
+// medTm (medianTime) is the median time past of the last block on the main chain, which
+// decides whether transactions locked until a timestamp can be mined.
 type Miner struct {
-	Conf *Config
-	Id   id.ID
+	Conf    *Config
+	ChnConf *blockchain.Config
+	Id      id.ID
 
 	TxP        *TxPool
 	MiningPool MiningPool
@@ -40,16 +45,19 @@ type Miner struct {
 	SendBlk     chan *block.Block
 	PoolUpdated chan bool
 
-	mutex sync.Mutex
+	difTrg string
+	medTm  uint32
+	mutex  sync.Mutex
 }
 
-// New constructs a new Miner according to a config and the id of a node.
-func New(c *Config, id id.ID) *Miner {
+// New constructs a new Miner according to a config, the config of the blockchain, and the id of a node.
+func New(c *Config, cc *blockchain.Config, id id.ID) *Miner {
 	if !c.HasMnr {
 		return nil
 	}
 	return &Miner{
 		Conf:        c,
+		ChnConf:     cc,
 		Id:          id,
 		TxP:         NewTxPool(c),
 		MiningPool:  []*tx.Transaction{},
@@ -93,6 +101,33 @@ func (m *Miner) HndlBlk(b *block.Block) {
 	return
 }
 
+// HndlChnUpd (HandleChainUpdate) handles a change of
+// the main chain, which may have been a simple extension
+// or a reorganization onto a heavier fork. The miner
+// starts mining on top of the new last block, drops the
+// transactions that are now on the main chain from the
+// transaction pool, and takes back the transactions
+// that were only on blocks that got disconnected.
+// Inputs:
+// h string the hash of the new last block
+// l uint32 the new length of the main chain
+// conn []*block.Block the blocks that joined the main chain
+// readd []*tx.Transaction the transactions that left the
+// main chain and are still valid
+func (m *Miner) HndlChnUpd(h string, l uint32, conn []*block.Block, readd []*tx.Transaction) {
+	m.SetHash(h)
+	m.SetChnLen(l)
+	for _, b := range conn {
+		m.TxP.ChkTxs(b.Transactions)
+	}
+	for _, t := range readd {
+		m.TxP.Add(t)
+	}
+	if m.Active.Load() {
+		m.PoolUpdated <- true
+	}
+}
+
 // HndlChkBlk (HandleCheckBlock) handles updating
 // the transaction pool and the orphan pool based
 // on the new transactions in the block.
@@ -122,7 +157,10 @@ func (m *Miner) HndlTx(t *tx.Transaction) {
 		return
 	}
 
-	m.TxP.Add(t)
+	if err := m.TxP.Add(t); err != nil {
+		utils.Debug.Printf("%v did not add %v to the pool: %v", utils.FmtAddr(m.Addr), t.NameTag(), err)
+		return
+	}
 
 	if m.Active.Load() {
 		m.PoolUpdated <- true
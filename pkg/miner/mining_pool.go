@@ -7,12 +7,18 @@ import "BrunoCoin/pkg/block/tx"
 type MiningPool []*tx.Transaction
 
 // NewMiningPool selects the highest priority
-// transactions from the transaction pool.
+// transactions from the transaction pool. Transactions
+// that aren't final at the height of the next block stay
+// in the pool until they are.
 func (m *Miner) NewMiningPool() MiningPool {
 	var txs []*tx.Transaction
 	var blkSz uint32 = 100 // assume coinbase
 	var rankings = *m.TxP.TxQ
+	h, tm := m.ChnLen.Load(), m.MedTm()
 	for i := 0; i < len(rankings); i++ {
+		if !rankings[i].T.IsFinal(h, tm) {
+			continue
+		}
 		blkSz += rankings[i].T.Sz()
 		if blkSz < m.Conf.BlkSz {
 			txs = append(txs, rankings[i].T)
@@ -2,6 +2,9 @@ package miner
 
 import (
 	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/utils"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -20,6 +23,15 @@ import (
 // in the pool.
 // Cap is the maximum amount of allowed
 // transactions to store in the pool.
+// RBF (ReplaceByFee) is whether a conflicting
+// transaction with a higher fee replaces the
+// transactions it conflicts with.
+// Conflicts reports every transaction that was
+// rejected or evicted because it spends the same
+// output as another transaction. It is buffered, and
+// conflicts are dropped if nobody reads it.
+// spnt (spent) maps the locator of every output spent
+// by a transaction in the pool to that transaction.
 type TxPool struct {
 	CurPri *atomic.Uint32
 	PriLim uint32
@@ -27,7 +39,26 @@ type TxPool struct {
 	TxQ   *tx.Heap
 	Ct    *atomic.Uint32
 	Cap   uint32
+	RBF   bool
 	mutex sync.Mutex
+
+	Conflicts chan *Conflict
+	spnt      map[string]*tx.Transaction
+}
+
+// Conflict describes a transaction that lost out to
+// another transaction spending the same output.
+// Loc (Locator) is the output both transactions spend
+// Tx is the transaction that was rejected or evicted
+// from the pool
+// By is the transaction that was kept, either in the
+// pool or because it was mined
+// Mined is whether By was mined on a block
+type Conflict struct {
+	Loc   string
+	Tx    *tx.Transaction
+	By    *tx.Transaction
+	Mined bool
 }
 
 // Length returns the count of transactions
@@ -38,6 +69,18 @@ func (tp *TxPool) Length() uint32 {
 	return tp.Ct.Load()
 }
 
+// Txs (Transactions) returns the transactions
+// in the pool.
+func (tp *TxPool) Txs() []*tx.Transaction {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+	txs := make([]*tx.Transaction, 0, len(*tp.TxQ))
+	for _, it := range *tp.TxQ {
+		txs = append(txs, it.T)
+	}
+	return txs
+}
+
 // NewTxPool constructs a transaction pool.
 func NewTxPool(c *Config) *TxPool {
 	return &TxPool{
@@ -46,6 +89,10 @@ func NewTxPool(c *Config) *TxPool {
 		TxQ:    tx.NewTxHeap(),
 		Ct:     atomic.NewUint32(0),
 		Cap:    c.TxPCap,
+		RBF:    c.RBF,
+
+		Conflicts: make(chan *Conflict, 100),
+		spnt:      make(map[string]*tx.Transaction),
 	}
 }
 
@@ -79,50 +126,149 @@ func CalcPri(t *tx.Transaction) uint32 {
 
 // Add adds a transaction to the transaction pool.
 // If the transaction pool is full, the transaction
-// will not be added. Otherwise, the cumulative
+// will not be added. If it spends an output that a
+// transaction in the pool already spends, it is
+// rejected, unless RBF is on and it pays a higher fee
+// than all of those transactions together, in which
+// case they are evicted. Otherwise, the cumulative
 // priority level is updated, the counter is
 // incremented, and the transaction is added to the
 // heap.
-func (tp *TxPool) Add(t *tx.Transaction) {
+// Returns:
+// error why the transaction was not added
+func (tp *TxPool) Add(t *tx.Transaction) error {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
 
 	if t == nil {
 		fmt.Println("ERROR {TxPool.Add}: received a nil transaction")
-		return
+		return errors.New("nil transaction")
 	}
 
-	if tp.Length() >= tp.Cap {
-		return
+	if tp.TxQ.Has(t) {
+		return nil
+	}
+
+	cnfl := make(map[string]*tx.Transaction)
+	var cnflFee uint32
+	for _, l := range spends(t) {
+		if o, found := tp.spnt[l]; found {
+			if _, seen := cnfl[o.Hash()]; !seen {
+				cnflFee += fee(o)
+			}
+			cnfl[o.Hash()] = o
+		}
+	}
+	if len(cnfl) > 0 && (!tp.RBF || fee(t) <= cnflFee) {
+		for _, l := range spends(t) {
+			if o, found := tp.spnt[l]; found {
+				tp.report(&Conflict{Loc: l, Tx: t, By: o})
+				return fmt.Errorf("%v double spends %v", t.NameTag(), l)
+			}
+		}
+	}
+
+	if tp.Length()-uint32(len(cnfl)) >= tp.Cap {
+		return errors.New("transaction pool is full")
+	}
+
+	for _, l := range spends(t) {
+		if o, found := tp.spnt[l]; found {
+			tp.report(&Conflict{Loc: l, Tx: o, By: t})
+		}
+	}
+	var evict []*tx.Transaction
+	for _, o := range cnfl {
+		evict = append(evict, o)
 	}
+	tp.rmv(evict)
 
 	tp.TxQ.Add(CalcPri(t), t)
 	tp.CurPri.Add(CalcPri(t))
 	tp.Ct.Add(1)
+	for _, l := range spends(t) {
+		tp.spnt[l] = t
+	}
+	return nil
 }
 
 // ChkTxs (CheckTransactions) checks for any duplicate
-// transactions in the heap and removes them.
+// transactions in the heap and removes them. Transactions
+// in the heap that spend the same outputs as the
+// inputted (mined) transactions can never be mined, so
+// they are removed as well.
 func (tp *TxPool) ChkTxs(remover []*tx.Transaction) {
 	tp.mutex.Lock()
-	var count uint32
-	var priority uint32
+	defer tp.mutex.Unlock()
 
 	if remover == nil {
 		fmt.Printf("ERROR {TxPool.ChkTxs}: received a nil transaction")
 		return
 	}
 
-	removed := tp.TxQ.Rmv(remover)
+	tp.rmv(remover)
+
+	var cnfl []*tx.Transaction
+	for _, t := range remover {
+		if t == nil {
+			continue
+		}
+		for _, l := range spends(t) {
+			if o, found := tp.spnt[l]; found {
+				tp.report(&Conflict{Loc: l, Tx: o, By: t, Mined: true})
+				cnfl = append(cnfl, o)
+			}
+		}
+	}
+	tp.rmv(cnfl)
+}
+
+// rmv (remove) removes transactions from the heap and
+// the spent outputs. The caller must hold the mutex.
+func (tp *TxPool) rmv(ts []*tx.Transaction) {
+	var count uint32
+	var priority uint32
+
+	removed := tp.TxQ.Rmv(ts)
 
 	for i := range removed {
 		count++
 		priority += CalcPri(removed[i])
+		for _, l := range spends(removed[i]) {
+			if o, found := tp.spnt[l]; found && o.Hash() == removed[i].Hash() {
+				delete(tp.spnt, l)
+			}
+		}
 	}
 
 	tp.CurPri.Sub(priority)
 	tp.Ct.Sub(count)
+}
+
+// report sends a conflict on the Conflicts channel
+// without blocking.
+func (tp *TxPool) report(c *Conflict) {
+	utils.Debug.Printf("%v lost to %v on %v", c.Tx.NameTag(), c.By.NameTag(), c.Loc)
+	select {
+	case tp.Conflicts <- c:
+	default:
+	}
+}
 
-	tp.mutex.Unlock()
-	return
+// spends returns the locators of the outputs a
+// transaction spends.
+func spends(t *tx.Transaction) []string {
+	ls := make([]string, len(t.Inputs))
+	for i, inp := range t.Inputs {
+		ls[i] = txo.MkTXOLoc(inp.TransactionHash, inp.OutputIndex)
+	}
+	return ls
+}
+
+// fee returns the fee a transaction pays.
+func fee(t *tx.Transaction) uint32 {
+	if t.SumInputs() < t.SumOutputs() {
+		return 0
+	}
+	return t.SumInputs() - t.SumOutputs()
 }
@@ -6,13 +6,15 @@ import (
 	"BrunoCoin/pkg/block"
 	"BrunoCoin/pkg/block/tx"
 	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/channel"
 	"BrunoCoin/pkg/id"
 	"BrunoCoin/pkg/miner"
 	"BrunoCoin/pkg/peer"
 	"BrunoCoin/pkg/proto"
 	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
 	"BrunoCoin/pkg/wallet"
-	"errors"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"os"
@@ -34,7 +36,11 @@ import (
 // and directives to stop or resume the node is done
 // on the node object.
 // *proto.UnimplementedBrunoCoinServer
+// *proto.UnimplementedAdminServer
 // Server *grpc.Server
+// AdmnSrv (AdminServer) *grpc.Server serves the Admin
+// service on the loopback interface, nil if it isn't
+// served (see Config.AdmnPort)
 // Conf *Config the settings for the node
 // Addr string the address that the node is listening
 // to traffic on
@@ -42,6 +48,8 @@ import (
 // Chain  *blockchain.Blockchain the blockchain
 // Wallet *wallet.Wallet the wallet
 // Mnr    *miner.Miner the miner
+// Chnls (Channels) *channel.Svc the payment channels, nil
+// if the node has none
 // fGetAddr bool
 // AddrDb   addressdb.AddressDb a database of addresses
 // of nodes that it knows about in the network
@@ -50,13 +58,32 @@ import (
 // TxMap    map[string]bool a map used to keep track
 // of whether a transaction has been seen on the network
 // before or not
+// TxRly (TransactionRelay) *TxRly the transactions the
+// node announced, for peers to get
 // BlockMap map[string]bool a map used to keep track
 // of whether a block has been seen on the network
 // before or not
+// Orphans *blockchain.OrphanPool blocks from the network
+// whose previous block has not arrived yet
+// Rjcts (Rejections) *valerr.Counts counts the transactions
+// and blocks from the network that were rejected, by reason
+// Bans *peer.BanLst the addresses the node refuses to peer
+// with (see Ban)
+// syncMutex keeps a light node from syncing its wallet
+// twice at once (see SyncWt)
+// inFlt (inFlight) map[string]bool the announced
+// transactions and blocks being asked for (see reqInv)
+// pndng (pending) map[string]*peer.Peer the nodes whose
+// version message this node acknowledged, which aren't
+// peers until they accept its verack (see SendVerack)
+// nonce uint64 is random, and sent in the node's version
+// messages so it can tell when it connected to itself
 // Paused bool
 type Node struct {
 	*proto.UnimplementedBrunoCoinServer
-	Server *grpc.Server
+	*proto.UnimplementedAdminServer
+	Server  *grpc.Server
+	AdmnSrv *grpc.Server
 
 	Conf *Config
 	Addr string
@@ -65,14 +92,26 @@ type Node struct {
 	Chain  *blockchain.Blockchain
 	Wallet *wallet.Wallet
 	Mnr    *miner.Miner
+	Chnls  *channel.Svc
 
 	fGetAddr bool // starts false, set to true when we request addresses from a node, cleared when we receive less than 1000 addresses from a node
 
 	AddrDb        addressdb.AddressDb
 	PeerDb        peer.PeerDb
 	TxMap         map[string]bool
+	TxMapMutex    sync.Mutex
+	TxRly         *TxRly
 	BlockMap      map[string]bool
 	BlockMapMutex sync.Mutex
+	Orphans       *blockchain.OrphanPool
+	Rjcts         *valerr.Counts
+	Bans          *peer.BanLst
+	syncMutex     sync.Mutex
+	inFlt         map[string]bool
+	inFltMutex    sync.Mutex
+	pndng         map[string]*peer.Peer
+	pndMutex      sync.Mutex
+	nonce         uint64
 
 	Paused bool
 }
@@ -105,6 +144,23 @@ func (n *Node) SendTx(amt uint32, fee uint32, pubK []byte) {
 	go n.Wallet.HndlTxReq(txR)
 }
 
+// SendLckdTx (SendLockedTransaction) is SendTx for a
+// payment that can't be mined until its lock time has
+// passed.
+// Inputs:
+// amt uint32 the amount to pay
+// fee uint32 the fee
+// pubK []byte the public key of the person being paid
+// lckTm uint32 a block height, or a Unix timestamp if it
+// is at least script.LckTmThrsh
+func (n *Node) SendLckdTx(amt uint32, fee uint32, pubK []byte, lckTm uint32) {
+	if amt <= 0 || pubK == nil {
+		utils.Debug.Printf("Node {%v} received a bad locked payment", n)
+		return
+	}
+	go n.Wallet.HndlTxReq(&wallet.TxReq{PubK: pubK, Amt: amt, Fee: fee, LckTm: lckTm})
+}
+
 // New returns a new Node object based on
 // a configuration
 // Inputs:
@@ -121,12 +177,28 @@ func New(conf *Config) *Node {
 	}
 	n.Chain = blockchain.New(n.Conf.ChainConf)
 	n.Wallet = wallet.New(n.Conf.WtConf, n.Id, n.Chain)
-	n.Mnr = miner.New(n.Conf.MnrConf, n.Id)
+	n.Mnr = miner.New(n.Conf.MnrConf, n.Conf.ChainConf, n.Id)
+	if conf.ChnlConf != nil {
+		n.Chnls = channel.New(conf.ChnlConf, n.Id, n.Chain, n.Wallet)
+	}
+	if n.Conf.MnrConf.HasMnr {
+		n.Mnr.SetHash(n.Chain.GetLastBlock().Hash())
+		n.Mnr.SetChnLen(uint32(n.Chain.Length()))
+		n.Mnr.SetDifTrg(n.Chain.NextDifTrg())
+		n.Mnr.SetMedTm(n.medTm())
+	}
 
 	n.AddrDb = addressdb.New(true, 1000)
 	n.PeerDb = peer.NewDb(true, 200, "")
 	n.TxMap = make(map[string]bool)
+	n.TxRly = NewTxRly(MxRly)
 	n.BlockMap = make(map[string]bool)
+	n.inFlt = make(map[string]bool)
+	n.pndng = make(map[string]*peer.Peer)
+	n.Orphans = blockchain.NewOrphanPool(conf.OrphLim)
+	n.Rjcts = valerr.NewCounts()
+	n.Bans = peer.NewBanLst()
+	n.nonce = mkNonce()
 
 	return n
 }
@@ -155,7 +227,13 @@ func (n *Node) Start() {
 	if n.Conf.WtConf.HasWt {
 		n.Wallet.SetAddr(addr)
 	}
+	if n.Chnls != nil {
+		n.Chnls.SetAddr(addr)
+	}
 	n.StartServer(addr)
+	if n.Conf.AdmnPort != 0 {
+		n.StartAdmn(fmt.Sprintf("127.0.0.1:%v", n.Conf.AdmnPort))
+	}
 	go func() {
 		if n.Conf.MnrConf.HasMnr {
 			for {
@@ -164,6 +242,8 @@ func (n *Node) Start() {
 					n.HndlWtTx(t)
 				case b := <-n.Mnr.SendBlk:
 					n.HndlMnrBlk(b)
+				case c := <-n.Mnr.TxP.Conflicts:
+					n.HndlCnfl(c)
 				}
 			}
 		} else {
@@ -186,25 +266,290 @@ func (n *Node) Start() {
 // Inputs:
 // b *block.Block the block mined by the miner
 func (n *Node) HndlMnrBlk(b *block.Block) {
+	// The target may have changed while the block
+	// was being mined.
+	if !n.Chain.ChkDifTrg(b) {
+		utils.Debug.Printf("%v dropped mined %v with a stale difficulty target", utils.FmtAddr(n.Addr), b.NameTag())
+		return
+	}
 	n.BlockMapMutex.Lock()
 	n.BlockMap[b.Hash()] = true
 	n.BlockMapMutex.Unlock()
-	n.Chain.Add(b)
-	if n.Conf.WtConf.HasWt {
-		blks := n.Chain.Slice(n.Chain.Length()-n.Conf.WtConf.SafeBlkAmt, n.Chain.Length())
-		if len(blks) == n.Conf.WtConf.SafeBlkAmt {
-			go n.Wallet.HndlBlk(blks[0])
+	n.HndlChnUpd(n.Chain.Add(b))
+	n.BroadcastBlk(b)
+	n.ConnOrphs(b.Hash())
+}
+
+// BroadcastBlk (BroadcastBlock) sends a block to every
+// peer that isn't known to have it, as a compact block
+// if Conf.CmpctBlks is set (see sndCmpct). Otherwise
+// the block is announced (see Announce).
+// Inputs:
+// b *block.Block the block to be sent
+func (n *Node) BroadcastBlk(b *block.Block) {
+	if n.Conf.CmpctBlks {
+		n.sndCmpct(b)
+		return
+	}
+	utils.Debug.Printf("%v announcing %v", utils.FmtAddr(n.Addr), b.NameTag())
+	n.Announce([]*proto.InvItem{{Type: proto.InvType_BLOCK, Hash: b.Hash()}})
+}
+
+// HndlNwTx (HandleNetworkTransaction) handles a transaction
+// that was received from another node. If it wasn't seen
+// before and is valid, it is sent to the miner and
+// announced to the node's peers.
+// Inputs:
+// t *tx.Transaction the transaction
+// from string the address of the node that sent it, may
+// be "" if unknown
+// Returns:
+// error why the transaction is invalid (a *valerr.Err),
+// or nil
+func (n *Node) HndlNwTx(t *tx.Transaction, from string) error {
+	n.mrkKnwn(from, t.Hash())
+	n.TxMapMutex.Lock()
+	seen := n.TxMap[t.Hash()]
+	n.TxMapMutex.Unlock()
+	// Light nodes have no utxo to check transactions with
+	if seen || n.Conf.ChainConf.HdrsOnly {
+		return nil
+	}
+	if err := n.TxErr(t); err != nil {
+		n.Rjcts.Inc(err)
+		utils.Debug.Printf("%v recieved invalid %v from %v: %v", utils.FmtAddr(n.Addr), t.NameTag(),
+			utils.FmtAddr(from), err)
+		n.Misbhv(from, peer.BadTx, err)
+		return err
+	}
+	utils.Debug.Printf("%v recieved valid %v", utils.FmtAddr(n.Addr), t.NameTag())
+	if n.Conf.MnrConf.HasMnr {
+		go n.Mnr.HndlTx(t)
+	}
+	n.TxMapMutex.Lock()
+	n.TxMap[t.Hash()] = true
+	n.TxMapMutex.Unlock()
+	n.RlyTx(t)
+	return nil
+}
+
+// HndlFwdBlk (HandleForwardedBlock) handles a block that
+// was received from another node, unless it was seen
+// before. A light node only keeps its header (see
+// HndlNwHdr), other nodes validate and add it (see
+// HndlNwBlk).
+// Inputs:
+// b *block.Block the block
+// from string the address of the node that sent it, may
+// be "" if unknown
+// Returns:
+// error why the block is invalid (a *valerr.Err), or nil
+func (n *Node) HndlFwdBlk(b *block.Block, from string) error {
+	n.mrkKnwn(from, b.Hash())
+	// Ignore if already seen block
+	n.BlockMapMutex.Lock()
+	if n.BlockMap[b.Hash()] {
+		n.BlockMapMutex.Unlock()
+		return nil
+	}
+	n.BlockMap[b.Hash()] = true
+	n.BlockMapMutex.Unlock()
+	if n.Conf.ChainConf.HdrsOnly {
+		return n.HndlNwHdr(b, from)
+	}
+	return n.HndlNwBlk(b, from)
+}
+
+// HndlNwBlk (HandleNetworkBlock) handles a block that
+// was received from another node. If the previous block
+// is unknown, the block is put in the orphan pool and its
+// ancestors are requested from the node that sent it.
+// Otherwise the block is validated, added to the chain
+// and broadcast, and any orphans that were waiting on it
+// are connected as well.
+// Inputs:
+// b *block.Block the block from the network
+// from string the address of the node that sent the
+// block, may be "" if unknown
+// Returns:
+// error why the block is invalid (a *valerr.Err), or nil
+func (n *Node) HndlNwBlk(b *block.Block, from string) error {
+	if !n.Chain.Has(b.Hdr.PrvBlkHsh) {
+		if !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+			err := valerr.New(valerr.BadPOW, "orphan block does not satisfy its difficulty target")
+			n.Rjcts.Inc(err)
+			n.Misbhv(from, peer.BadBlk, err)
+			return err
 		}
+		if n.Orphans.Add(b) {
+			utils.Debug.Printf("%v recieved orphan %v", utils.FmtAddr(n.Addr), b.NameTag())
+			go n.FetchOrphAnc(b, from)
+		}
+		return nil
 	}
-	for _, p := range n.PeerDb.List() {
-		utils.Debug.Printf("%v sending %v to %v", utils.FmtAddr(n.Addr), b.NameTag(), utils.FmtAddr(p.Addr.Addr))
-		go func(addr *address.Address) {
-			_, err := addr.ForwardBlockRPC(b.Serialize())
-			if err != nil {
-				utils.Debug.Printf("%v recieved no response from ForwardBlockRPC to %v",
-					utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr))
+	if err := n.BlkErr(b); err != nil {
+		n.Rjcts.Inc(err)
+		utils.Debug.Printf("%v rejected %v from %v: %v", utils.FmtAddr(n.Addr), b.NameTag(), utils.FmtAddr(from), err)
+		n.Misbhv(from, peer.BadBlk, err)
+		return err
+	}
+	n.HndlChnUpd(n.Chain.Add(b))
+	n.BroadcastBlk(b)
+	n.ConnOrphs(b.Hash())
+	return nil
+}
+
+// ConnOrphs (ConnectOrphans) adds the orphans that were
+// waiting on a block that was just added to the chain,
+// and then the orphans waiting on those, and so on, so
+// that a whole chain of orphans connects in order.
+// Inputs:
+// h string the hash of the block that was just added
+func (n *Node) ConnOrphs(h string) {
+	q := []string{h}
+	for len(q) > 0 {
+		for _, o := range n.Orphans.Rmv(q[0]) {
+			if err := n.BlkErr(o); err != nil {
+				n.Rjcts.Inc(err)
+				utils.Debug.Printf("%v dropped invalid orphan %v: %v", utils.FmtAddr(n.Addr), o.NameTag(), err)
+				continue
 			}
-		}(p.Addr)
+			utils.Debug.Printf("%v connected orphan %v", utils.FmtAddr(n.Addr), o.NameTag())
+			n.HndlChnUpd(n.Chain.Add(o))
+			n.BroadcastBlk(o)
+			q = append(q, o.Hash())
+		}
+		q = q[1:]
+	}
+}
+
+// FetchOrphAnc (FetchOrphanAncestors) requests the blocks
+// that are missing between the chain and an orphan from
+// the node that sent the orphan. First, it asks for the
+// blocks past the last block of the main chain, which
+// covers the node having simply fallen behind. If the
+// orphan still can't connect (it is on a fork), it walks
+// back from the orphan one block at a time until it
+// reaches a block that is on the chain.
+// Inputs:
+// b *block.Block the orphan
+// from string the address of the node that sent the
+// orphan. If it is not a peer, any peer is asked.
+func (n *Node) FetchOrphAnc(b *block.Block, from string) {
+	var addr *address.Address
+	if p := n.PeerDb.Get(from); p != nil {
+		addr = p.Addr
+	} else if ps := n.PeerDb.GetRandom(1, []string{n.Addr}); len(ps) > 0 {
+		addr = ps[0].Addr
+	} else {
+		return
+	}
+	res, err := addr.GetBlocksRPC(&proto.GetBlocksRequest{
+		TopBlockHash: n.Chain.GetLastBlock().Hash(),
+		AddrMe:       n.Addr,
+	}, address.From(n.Addr))
+	if err == nil {
+		for _, h := range res.BlockHashes {
+			if n.Chain.Has(h) || n.Orphans.Has(h) {
+				continue
+			}
+			if !n.fetchBlk(addr, h) {
+				break
+			}
+		}
+	}
+	for i := 0; i < n.Conf.OrphLim && n.Orphans.Has(b.Hash()); i++ {
+		h := n.Orphans.Missing(b)
+		if n.Chain.Has(h) || !n.fetchBlk(addr, h) {
+			return
+		}
+	}
+}
+
+// fetchBlk (fetchBlock) requests a single block from a
+// node and handles it. If the block is an orphan itself,
+// it only goes in the orphan pool, since whoever called
+// fetchBlk is already fetching its ancestors.
+// Returns:
+// bool True if the block was received and is valid
+func (n *Node) fetchBlk(addr *address.Address, h string) bool {
+	res, err := addr.GetDataRPC(&proto.GetDataRequest{BlockHash: h}, address.From(n.Addr))
+	if err != nil || res.Block == nil || res.Block.Header == nil {
+		utils.Debug.Printf("%v could not fetch block %v from %v", utils.FmtAddr(n.Addr), h, utils.FmtAddr(addr.Addr))
+		return false
+	}
+	blk := block.Deserialize(res.Block)
+	if blk.Hash() != h {
+		return false
+	}
+	n.BlockMapMutex.Lock()
+	n.BlockMap[h] = true
+	n.BlockMapMutex.Unlock()
+	if !n.Chain.Has(blk.Hdr.PrvBlkHsh) {
+		if !blk.SatisfiesPOW(blk.Hdr.DiffTarg) {
+			return false
+		}
+		n.Orphans.Add(blk)
+		return true
+	}
+	return n.HndlNwBlk(blk, addr.Addr) == nil
+}
+
+// medTm (medianTime) returns the median time past of
+// the last block on the main chain.
+func (n *Node) medTm() uint32 {
+	tm, _ := n.Chain.MedTmPst(n.Chain.GetLastBlock().Hash())
+	return tm
+}
+
+// HndlChnUpd (HandleChainUpdate) handles a change of
+// the main chain after a block was added to the chain.
+// The miner is told about the new last block, gets rid
+// of transactions that were mined and takes back the
+// still valid transactions from disconnected blocks.
+// The wallet is told about its transactions that are no
+// longer on the main chain, and is handed the block that
+// is now "safe block amount" deep for every height the
+// main chain gained. Payment channels watch the connected
+// blocks for transactions closing them.
+// Inputs:
+// u *blockchain.ChnUpd the change of the main chain, may
+// be nil if the main chain did not change
+func (n *Node) HndlChnUpd(u *blockchain.ChnUpd) {
+	if u == nil {
+		return
+	}
+	unconf := u.Unconfirmed()
+	if n.Conf.MnrConf.HasMnr {
+		var readd []*tx.Transaction
+		for _, t := range unconf {
+			if n.ChkTx(t) {
+				readd = append(readd, t)
+			}
+		}
+		n.Mnr.SetDifTrg(n.Chain.NextDifTrg())
+		n.Mnr.SetMedTm(n.medTm())
+		n.Mnr.HndlChnUpd(n.Chain.GetLastBlock().Hash(), uint32(n.Chain.Length()), u.Connected, readd)
+	}
+	if n.Conf.WtConf.HasWt {
+		if len(unconf) > 0 {
+			n.Wallet.HndlUnconf(unconf)
+		}
+		ln := n.Chain.Length()
+		for i := range u.Connected {
+			// index of the block that is safe block amount
+			// down from the i-th connected block
+			s := ln - len(u.Connected) + i + 1 - n.Conf.WtConf.SafeBlkAmt
+			if s < 0 {
+				continue
+			}
+			if blks := n.Chain.Slice(s, s+1); len(blks) == 1 {
+				go n.Wallet.HndlBlk(blks[0])
+			}
+		}
+	}
+	if n.Chnls != nil {
+		go n.Chnls.HndlBlks(u)
 	}
 }
 
@@ -217,14 +562,21 @@ func (n *Node) HndlMnrBlk(b *block.Block) {
 // uint32 the amount of money (the balance) that
 // the person with that public key has
 func (n *Node) GetBalance(pk string) uint32 {
+	// A light node only knows its own wallet's balance
+	if n.Conf.ChainConf.HdrsOnly {
+		if n.Wallet == nil || pk != hex.EncodeToString(n.Id.GetPublicKeyBytes()) {
+			return 0
+		}
+		return n.Wallet.Lght.Bal()
+	}
 	return n.Chain.GetBalance(pk)
 }
 
 // HndlWtTx (HandleWalletTransaction) handles a new
 // transaction being created by the wallet. It does
-// this by sending that transaction to the network as
-// well as to the miner. Lastly, it is also added
-// to the map of seen transactions.
+// this by sending that transaction to the miner and
+// announcing it to the network (see RlyTx). It is
+// also added to the map of seen transactions.
 // Inputs:
 // t *tx.Transaction the transaction that was just
 // made by the wallet.
@@ -232,17 +584,24 @@ func (n *Node) HndlWtTx(t *tx.Transaction) {
 	if n.Conf.MnrConf.HasMnr {
 		go n.Mnr.HndlTx(t)
 	}
+	n.TxMapMutex.Lock()
 	n.TxMap[t.Hash()] = true
-	for _, p := range n.PeerDb.List() {
-		d := t.Serialize()
-		utils.Debug.Printf("%v sending %v to %v", utils.FmtAddr(n.Addr), t.NameTag(), utils.FmtAddr(p.Addr.Addr))
-		go func(addr *address.Address) {
-			_, err := addr.ForwardTransactionRPC(d)
-			if err != nil {
-				utils.Debug.Printf("%v recieved no response from ForwardTransactionRPC to %v",
-					utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr))
-			}
-		}(p.Addr)
+	n.TxMapMutex.Unlock()
+	n.RlyTx(t)
+}
+
+// HndlCnfl (HandleConflict) handles a transaction that
+// was rejected or evicted from the transaction pool because
+// another transaction spends the same output. If the
+// transaction was the wallet's own, the wallet stops
+// waiting on it (see wallet.Wallet.HndlCnfl).
+// Inputs:
+// c *miner.Conflict the conflict
+func (n *Node) HndlCnfl(c *miner.Conflict) {
+	utils.Debug.Printf("%v conflict on %v: %v lost to %v (mined: %v)",
+		utils.FmtAddr(n.Addr), c.Loc, c.Tx.NameTag(), c.By.NameTag(), c.Mined)
+	if n.Conf.WtConf.HasWt && n.Wallet.LmnlTxs.Has(c.Tx) {
+		n.Wallet.HndlCnfl(c.Tx, c.By)
 	}
 }
 
@@ -253,26 +612,6 @@ func (n *Node) StartMiner() {
 	n.Mnr.StartMiner()
 }
 
-// This connects to a certain peer in the network. This just
-// serves as an interface for the real functionality contained
-// within the Router.
-// Inputs:
-// addr string the address of the node that you want
-// to connect to.
-func (n *Node) ConnectToPeer(addr string) {
-	a := address.New(addr, 0)
-	_, err := a.VersionRPC(&proto.VersionRequest{
-		Version:    uint32(n.Conf.Version),
-		AddrYou:    addr,
-		AddrMe:     n.Addr,
-		BestHeight: uint32(n.Chain.Length()),
-	})
-	if err != nil {
-		utils.Debug.Printf("%v recieved no response from VersionRPC to %v",
-			utils.FmtAddr(n.Addr), utils.FmtAddr(addr))
-	}
-}
-
 // BroadcastAddr
 func (n *Node) BroadcastAddr() {
 	myAddr := proto.Address{Addr: n.Addr, LastSeen: uint32(time.Now().UnixNano())}
@@ -281,7 +620,7 @@ func (n *Node) BroadcastAddr() {
 			_, err := addr.SendAddressesRPC(&proto.Addresses{Addrs: []*proto.Address{&myAddr}})
 			if err != nil {
 				utils.Debug.Printf("%v recieved no response from SendAddressesRPC to %v",
-					utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr))
+					utils.FmtAddr(n.Addr), utils.FmtAddr(addr.Addr))
 			}
 		}(p.Addr)
 	}
@@ -291,47 +630,65 @@ func (n *Node) BroadcastAddr() {
 // pre-existing one that other nodes have. This may happen
 // when a node first joins the network, or if the node left
 // the network for a while (paused), then rejoined.
+// The headers past where the peers' main chain forks from
+// this node's are synced and validated first, then their
+// blocks are downloaded in parallel from the peers that
+// sent them, and added in order. The headers that lead
+// to the most work are synced. If they or their blocks
+// are invalid, the peers that sent them are dropped and
+// the headers of the other peers are synced instead
+// (see bstHdrs). This repeats until the peers have no
+// more headers. A light node syncs its headers and
+// wallet instead.
 func (n *Node) Bootstrap() error {
-	utils.Debug.Printf("%v bootstrapping from %v peers with top block %v", utils.FmtAddr(n.Addr), len(n.PeerDb.List()), n.Chain.LastBlock.NameTag())
-	topBlockHash := n.Chain.GetLastBlock().Hash()
-	var wg sync.WaitGroup
-	var longestRes *proto.GetBlocksResponse
-	var addr *address.Address
-	if len(n.PeerDb.List()) == 0 {
-		return errors.New("no peers to bootstrap from")
+	if n.Conf.ChainConf.HdrsOnly {
+		return n.syncLght()
 	}
-	for _, p := range n.PeerDb.List() {
-		wg.Add(1)
-		go func(p *peer.Peer) {
-			res, err := p.Addr.GetBlocksRPC(&proto.GetBlocksRequest{TopBlockHash: topBlockHash})
-			if err != nil {
-				wg.Done()
-				return
+	utils.Debug.Printf("%v bootstrapping from %v peers with top block %v", utils.FmtAddr(n.Addr), len(n.PeerDb.List()), n.Chain.GetLastBlock().NameTag())
+	skp := make(map[string]bool)
+	for {
+		top := n.Chain.GetLastBlock().Hash()
+		r, err := n.bstHdrs(skp)
+		if err != nil {
+			return err
+		}
+		hdrs := r.hdrs
+		if len(hdrs) == 0 {
+			return nil
+		}
+		blks, dlErr := n.dlBlks(hdrs, r.ps)
+		var blkErr error
+		for _, b := range blks {
+			if n.Chain.Has(b.Hash()) {
+				continue
 			}
-			if longestRes == nil || len(res.BlockHashes) > len(longestRes.BlockHashes) {
-				longestRes = res
-				addr = p.Addr
+			if blkErr = n.BlkErr(b); blkErr != nil {
+				n.Rjcts.Inc(blkErr)
+				n.drpPrs(r.ps, skp, blkErr)
+				break
 			}
-			wg.Done()
-		}(p)
-	}
-	wg.Wait()
-	if longestRes == nil {
-		return errors.New("no peers gave responses")
-	}
-	chkOrf := len(longestRes.BlockHashes) <= 2
-	for _, h := range longestRes.BlockHashes {
-		pb, _ := addr.GetDataRPC(&proto.GetDataRequest{BlockHash: h})
-		b := block.Deserialize(pb.Block)
-		n.BlockMapMutex.Lock()
-		n.BlockMap[b.Hash()] = true
-		n.BlockMapMutex.Unlock()
-		n.Chain.Add(b)
-		if chkOrf {
-			n.Mnr.HndlChkBlk(b)
+			n.BlockMapMutex.Lock()
+			n.BlockMap[b.Hash()] = true
+			n.BlockMapMutex.Unlock()
+			n.HndlChnUpd(n.Chain.Add(b))
+			n.ConnOrphs(b.Hash())
+		}
+		if blkErr != nil {
+			continue
+		}
+		if dlErr != nil {
+			// The peers may be gone, so they aren't scored
+			utils.Debug.Printf("%v could not download blocks: %v", utils.FmtAddr(n.Addr), dlErr)
+			for _, p := range r.ps {
+				skp[p.Addr.Addr] = true
+			}
+			continue
+		}
+		utils.Debug.Printf("%v synced %v blocks, top block %v", utils.FmtAddr(n.Addr), len(blks), n.Chain.GetLastBlock().NameTag())
+		if len(hdrs) < MxHdrs || n.Chain.GetLastBlock().Hash() == top {
+			return nil
 		}
 	}
-	return nil
 }
 
 func (n *Node) StartServer(addr string) {
@@ -342,6 +699,9 @@ func (n *Node) StartServer(addr string) {
 	// Open node to connections
 	n.Server = grpc.NewServer()
 	proto.RegisterBrunoCoinServer(n.Server, n)
+	if n.Chnls != nil {
+		proto.RegisterPaymentChannelServer(n.Server, n.Chnls)
+	}
 	go func() {
 		err := n.Server.Serve(lis)
 		if err != nil {
@@ -351,6 +711,26 @@ func (n *Node) StartServer(addr string) {
 	}()
 }
 
+// StartAdmn (StartAdmin) serves the Admin service on its
+// own server, apart from the one its peers use, since
+// the Admin service has no authentication.
+// Inputs:
+// addr string the address to listen on, which should be
+// on the loopback interface
+func (n *Node) StartAdmn(addr string) {
+	lis, err := net.Listen("tcp4", addr)
+	if err != nil {
+		panic(err)
+	}
+	n.AdmnSrv = grpc.NewServer()
+	proto.RegisterAdminServer(n.AdmnSrv, n)
+	go func() {
+		if err := n.AdmnSrv.Serve(lis); err != nil {
+			utils.Err.Printf("%v could not serve the Admin service: %v", utils.FmtAddr(n.Addr), err)
+		}
+	}()
+}
+
 func (n *Node) PauseNetwork() {
 	n.Server.Stop()
 	utils.Debug.Printf("%v paused", utils.FmtAddr(n.Addr))
@@ -367,7 +747,18 @@ func (n *Node) ResumeNetwork() {
 }
 
 // This kills any threads currently managed by the Node or that
-// it previously started. It also does any necessary clean up.
+// it previously started. It also does any necessary clean up,
+// such as closing the block database and the connections to
+// its peers.
 func (n *Node) Kill() {
 	n.Server.GracefulStop()
+	if n.AdmnSrv != nil {
+		n.AdmnSrv.Stop()
+	}
+	for _, p := range n.PeerDb.List() {
+		n.PeerDb.Rmv(p.Addr.Addr)
+	}
+	if err := n.Chain.Close(); err != nil {
+		utils.Err.Printf("%v could not close block database: %v", utils.FmtAddr(n.Addr), err)
+	}
 }
@@ -0,0 +1,116 @@
+package peer
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// Ban is why and until when an address is banned.
+// Until is the time the ban ends
+// Scr (Score) is the misbehavior score of the peer when
+// it was banned, 0 if it was banned by hand
+type Ban struct {
+	Until time.Time
+	Scr   uint32
+}
+
+// BanLst (BanList) holds the hosts a node refuses to
+// peer with, each until its ban ends. Addresses are
+// banned by the IPs their host resolves to, so a banned
+// node can't come back on another port or under another
+// name for the same host. Ended bans are dropped when
+// they are looked at.
+type BanLst struct {
+	bans  map[string]Ban
+	mutex sync.Mutex
+}
+
+// NewBanLst (NewBanList) creates an empty ban list.
+func NewBanLst() *BanLst {
+	return &BanLst{bans: make(map[string]Ban)}
+}
+
+// hsts (hosts) returns what an address is banned by:
+// the IPs its host resolves to, or the host itself if it
+// doesn't resolve.
+// Inputs:
+// addr string a host:port address, or just a host
+func hsts(addr string) []string {
+	h, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		h = addr
+	}
+	ips, err := net.LookupIP(h)
+	if err != nil || len(ips) == 0 {
+		return []string{h}
+	}
+	ks := make([]string, len(ips))
+	for i, ip := range ips {
+		ks[i] = ip.String()
+	}
+	return ks
+}
+
+// Add bans the host of an address, replacing any ban it
+// had.
+// Inputs:
+// addr string the address
+// d time.Duration how long the ban lasts
+// scr uint32 the misbehavior score it was banned for
+func (l *BanLst) Add(addr string, d time.Duration, scr uint32) {
+	ks := hsts(addr)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, k := range ks {
+		l.bans[k] = Ban{Until: time.Now().Add(d), Scr: scr}
+	}
+}
+
+// Rmv (Remove) lifts the ban on the host of an address.
+// Returns true if the host was banned.
+func (l *BanLst) Rmv(addr string) bool {
+	ks := hsts(addr)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	bnd := false
+	for _, k := range ks {
+		b, ok := l.bans[k]
+		delete(l.bans, k)
+		bnd = bnd || (ok && time.Now().Before(b.Until))
+	}
+	return bnd
+}
+
+// Has returns whether the host of an address is banned.
+func (l *BanLst) Has(addr string) bool {
+	ks := hsts(addr)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	bnd := false
+	for _, k := range ks {
+		b, ok := l.bans[k]
+		if ok && !time.Now().Before(b.Until) {
+			delete(l.bans, k)
+			continue
+		}
+		bnd = bnd || ok
+	}
+	return bnd
+}
+
+// List returns the bans that haven't ended, by IP (or
+// host, if it didn't resolve).
+func (l *BanLst) List() map[string]Ban {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	cp := make(map[string]Ban, len(l.bans))
+	for a, b := range l.bans {
+		if !time.Now().Before(b.Until) {
+			delete(l.bans, a)
+			continue
+		}
+		cp[a] = b
+	}
+	return cp
+}
@@ -0,0 +1,82 @@
+package peer
+
+import (
+	"BrunoCoin/pkg/address"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// ErrClosed is returned by Conn.Get after the
+// connection was closed.
+var ErrClosed = errors.New("connection to peer is closed")
+
+// Conn (Connection) keeps a single connection to a peer,
+// which every request to the peer shares. The connection
+// is made the first time it is needed. When it drops,
+// it is made again in the background, backing off
+// between attempts (see address.Dial). It is closed when
+// the peer is evicted from the PeerDb.
+// addr is the address of the peer
+// tkn (token) is attached to every request, so the peer
+// knows they are from this node (see address.WithTkn)
+// cc is the connection, or nil if it wasn't made yet
+// closed is true once Close was called
+type Conn struct {
+	addr   string
+	tkn    uint64
+	cc     *grpc.ClientConn
+	closed bool
+	mutex  sync.Mutex
+}
+
+// NewConn (NewConnection) creates the connection
+// to the peer at addr, without connecting yet.
+func NewConn(addr string) *Conn {
+	return &Conn{addr: addr}
+}
+
+// NewTknConn (NewTokenConnection) is NewConn for a
+// connection whose requests carry the token the peer
+// gave this node during the handshake.
+func NewTknConn(addr string, tkn uint64) *Conn {
+	return &Conn{addr: addr, tkn: tkn}
+}
+
+// Get returns the connection to the peer, making it
+// if it wasn't made yet.
+// Returns:
+// *grpc.ClientConn the connection
+// error ErrClosed if the connection was closed, or why
+// it could not be made
+func (c *Conn) Get() (*grpc.ClientConn, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed {
+		return nil, ErrClosed
+	}
+	if c.cc == nil || c.cc.GetState() == connectivity.Shutdown {
+		cc, err := address.Dial(c.addr, address.WithTkn(c.tkn))
+		if err != nil {
+			return nil, err
+		}
+		c.cc = cc
+	}
+	return c.cc, nil
+}
+
+// Close closes the connection. Requests to the peer
+// made afterwards make their own connections.
+func (c *Conn) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	if c.cc == nil {
+		return nil
+	}
+	err := c.cc.Close()
+	c.cc = nil
+	return err
+}
@@ -3,15 +3,22 @@ package peer
 import (
 	"errors"
 	"math/rand"
+	"sync"
 )
 
+// EphemeralPeerDb is a PeerDb kept in memory. Its
+// methods are called from the node's gRPC handlers, so
+// peers is guarded by mutex.
 type EphemeralPeerDb struct {
 	peers map[string]*Peer
 	limit int
 	Addr string
+	mutex sync.RWMutex
 }
 
 func (pdb *EphemeralPeerDb) In(k string) bool {
+	pdb.mutex.RLock()
+	defer pdb.mutex.RUnlock()
 	_, in := pdb.peers[k]
 	return in
 }
@@ -20,22 +27,54 @@ func (pdb *EphemeralPeerDb) SetAddr(addr string) {
 	pdb.Addr = addr
 }
 
-// Returns true if peer existed already or was added
+// Returns true if peer existed already or was added.
+// Requests to an added peer share its connection, and
+// the connection of the peer it replaces is closed.
 func (pdb *EphemeralPeerDb) Add(p *Peer) bool {
+	pdb.mutex.Lock()
+	defer pdb.mutex.Unlock()
 	oldP := pdb.peers[p.Addr.Addr]
 	if (oldP != nil && p.Addr.LastSeen != oldP.Addr.LastSeen) || (oldP == nil && len(pdb.peers) < pdb.limit) {
+		if oldP != nil && oldP.Conn != p.Conn {
+			oldP.disconnect()
+		}
+		if p.Conn == nil {
+			p.Conn = NewConn(p.Addr.Addr)
+		}
+		if p.Known == nil {
+			p.Known = NewInvFltr(MxKnwn)
+		}
 		pdb.peers[p.Addr.Addr] = p
+		p.Addr.SetConns(p.Conn)
 		//utils.Debug.Printf("%v added peer %v", utils.FmtAddr(pdb.Addr), utils.FmtAddr(p.Addr.Addr))
 		return true
 	}
 	return false
 }
 
+// Rmv (Remove) evicts a peer and closes its connection.
+// Returns true if the peer was in the database.
+func (pdb *EphemeralPeerDb) Rmv(addr string) bool {
+	pdb.mutex.Lock()
+	p := pdb.peers[addr]
+	delete(pdb.peers, addr)
+	pdb.mutex.Unlock()
+	if p == nil {
+		return false
+	}
+	p.disconnect()
+	return true
+}
+
 func (pdb *EphemeralPeerDb) Get(addr string) *Peer {
+	pdb.mutex.RLock()
+	defer pdb.mutex.RUnlock()
 	return pdb.peers[addr]
 }
 
 func (pdb *EphemeralPeerDb) UpdateLastSeen(addr string, lastSeen uint32) error {
+	pdb.mutex.Lock()
+	defer pdb.mutex.Unlock()
 	p := pdb.peers[addr]
 	if p == nil {
 		return errors.New("peer not found")
@@ -46,6 +85,8 @@ func (pdb *EphemeralPeerDb) UpdateLastSeen(addr string, lastSeen uint32) error {
 
 // Get up to n random peers
 func (pdb *EphemeralPeerDb) GetRandom(n int, exclude []string) []*Peer {
+	pdb.mutex.RLock()
+	defer pdb.mutex.RUnlock()
 	peers := make([]*Peer, 0)
 	if n >= len(pdb.peers) {
 		for _, peer := range pdb.peers {
@@ -72,6 +113,8 @@ func (pdb *EphemeralPeerDb) GetRandom(n int, exclude []string) []*Peer {
 }
 
 func (pdb *EphemeralPeerDb) List() []*Peer {
+	pdb.mutex.RLock()
+	defer pdb.mutex.RUnlock()
 	peers := make([]*Peer, 0)
 	for _, peer := range pdb.peers {
 		peers = append(peers, peer)
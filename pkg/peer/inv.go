@@ -0,0 +1,53 @@
+package peer
+
+import "sync"
+
+// MxKnwn (MaxKnown) is the most hashes a peer's
+// InvFltr remembers.
+const MxKnwn = 10000
+
+// InvFltr (InventoryFilter) holds the hashes of the
+// transactions and blocks that a peer is known to have,
+// because it announced or sent them, or they were
+// announced or sent to it. Past its limit, the oldest
+// hashes are forgotten.
+// lim (limit) is the most hashes it holds
+// hshs (hashes) is the set of hashes
+// ordr (order) is the hashes, oldest first
+type InvFltr struct {
+	lim   int
+	hshs  map[string]bool
+	ordr  []string
+	mutex sync.Mutex
+}
+
+// NewInvFltr (NewInventoryFilter) creates an empty
+// filter that holds at most lim hashes.
+func NewInvFltr(lim int) *InvFltr {
+	return &InvFltr{lim: lim, hshs: make(map[string]bool)}
+}
+
+// Add adds a hash to the filter.
+// Returns:
+// bool True if the hash wasn't in the filter
+func (f *InvFltr) Add(h string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.hshs[h] {
+		return false
+	}
+	if len(f.ordr) >= f.lim {
+		delete(f.hshs, f.ordr[0])
+		f.ordr = f.ordr[1:]
+	}
+	f.hshs[h] = true
+	f.ordr = append(f.ordr, h)
+	return true
+}
+
+// Has returns whether a hash is in the filter.
+func (f *InvFltr) Has(h string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.hshs[h]
+}
@@ -2,14 +2,50 @@ package peer
 
 import (
 	"BrunoCoin/pkg/address"
+	"sync"
 )
 
+// Peer is a node that this node is connected to.
+// Conn (Connection) is the connection that requests
+// to the peer share, once it is added to a PeerDb.
+// Known is the transactions and blocks the peer is
+// known to have, which are not announced to it.
+// Svcs (Services) is the services the peer offers, and
+// UsrAgnt (UserAgent) the software it runs, from its
+// version message.
+// InTkn (InToken) is the token this node gave the peer
+// during the handshake, which the peer's requests carry
+// (see address.WithTkn).
+// scrs (scores) is how much each offense added to the
+// peer's misbehavior score (see Misbhv)
 type Peer struct {
-	Addr          *address.Address
-	Version       uint32
-	bestHeight    uint32
+	Addr       *address.Address
+	Version    uint32
+	bestHeight uint32
+	Conn       *Conn
+	Known      *InvFltr
+	Svcs       Svcs
+	UsrAgnt    string
+	InTkn      uint64
+	scrs       map[Ofns]uint32
+	scrMutex   sync.Mutex
 }
 
 func New(addr *address.Address, version uint32, bestHeight uint32) *Peer {
-	return &Peer{Addr: addr, Version: version, bestHeight: bestHeight}
+	return &Peer{
+		Addr:       addr,
+		Version:    version,
+		bestHeight: bestHeight,
+		Conn:       NewConn(addr.Addr),
+		Known:      NewInvFltr(MxKnwn),
+	}
+}
+
+// disconnect closes the peer's connection, and makes
+// requests to its address stop sharing it.
+func (p *Peer) disconnect() {
+	if p.Addr.GetConns() == p.Conn {
+		p.Addr.SetConns(nil)
+	}
+	p.Conn.Close()
 }
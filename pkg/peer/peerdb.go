@@ -2,6 +2,7 @@ package peer
 
 type PeerDb interface {
 	Add(*Peer) bool
+	Rmv(string) bool
 	Get(string) *Peer
 	UpdateLastSeen(string, uint32) error
 	List() []*Peer
@@ -0,0 +1,58 @@
+package peer
+
+// Ofns (Offense) is a kind of misbehavior by a peer.
+type Ofns string
+
+const (
+	// BadBlk (BadBlock) means the peer sent a block or
+	// header that failed validation.
+	BadBlk Ofns = "bad-block"
+	// BadTx (BadTransaction) means the peer sent a
+	// transaction that failed validation.
+	BadTx Ofns = "bad-transaction"
+	// BadCmpct (BadCompact) means the peer sent a compact
+	// block that can't be rebuilt.
+	BadCmpct Ofns = "bad-compact-block"
+)
+
+// Pnlts (Penalties) is how much each offense adds to a
+// peer's misbehavior score. A block costs more work to
+// make than a transaction, so an invalid one is less
+// likely to be a mistake.
+var Pnlts = map[Ofns]uint32{
+	BadBlk:   50,
+	BadTx:    10,
+	BadCmpct: 20,
+}
+
+// Misbhv (Misbehave) adds an offense's penalty (see
+// Pnlts) to the peer's misbehavior score.
+// Inputs:
+// o Ofns the offense
+// Returns:
+// uint32 the peer's score, for all offenses
+func (p *Peer) Misbhv(o Ofns) uint32 {
+	p.scrMutex.Lock()
+	defer p.scrMutex.Unlock()
+	if p.scrs == nil {
+		p.scrs = make(map[Ofns]uint32)
+	}
+	p.scrs[o] += Pnlts[o]
+	s := uint32(0)
+	for _, v := range p.scrs {
+		s += v
+	}
+	return s
+}
+
+// Scrs (Scores) returns how much each offense added to
+// the peer's misbehavior score.
+func (p *Peer) Scrs() map[Ofns]uint32 {
+	p.scrMutex.Lock()
+	defer p.scrMutex.Unlock()
+	cp := make(map[Ofns]uint32, len(p.scrs))
+	for k, v := range p.scrs {
+		cp[k] = v
+	}
+	return cp
+}
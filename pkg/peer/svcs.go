@@ -0,0 +1,25 @@
+package peer
+
+// Svcs (Services) is the services a node offers its
+// peers, as bit flags. A node sends them in its version
+// message.
+type Svcs uint64
+
+const (
+	// SvcChn (ServiceChain) means the node has the full
+	// chain, with every block's transactions.
+	SvcChn Svcs = 1 << iota
+	// SvcMnr (ServiceMiner) means the node mines.
+	SvcMnr
+	// SvcWt (ServiceWallet) means the node has a wallet.
+	SvcWt
+	// SvcLght (ServiceLight) means the node only keeps
+	// block headers (see blockchain.Config.HdrsOnly).
+	SvcLght
+)
+
+// Has returns whether all of the services in o are
+// offered.
+func (s Svcs) Has(o Svcs) bool {
+	return s&o == o
+}
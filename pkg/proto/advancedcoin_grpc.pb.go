@@ -20,16 +20,30 @@ const _ = grpc.SupportPackageIsVersion7
 type BrunoCoinClient interface {
 	ForwardTransaction(ctx context.Context, in *Transaction, opts ...grpc.CallOption) (*Empty, error)
 	ForwardBlock(ctx context.Context, in *Block, opts ...grpc.CallOption) (*Empty, error)
-	// Establishes a one way connection to a node (may be reciprocated)
-	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Peers with a node, which acknowledges with its own version, or rejects with a Rejection detail
+	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*Verack, error)
+	// Accepts the verack of a node, which only then adds this node as a peer
+	SendVerack(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
 	// Gets maximum 500 blocks past block with top hash
 	GetBlocks(ctx context.Context, in *GetBlocksRequest, opts ...grpc.CallOption) (*GetBlocksResponse, error)
-	// Get a single block
+	// Gets maximum 2000 headers past where the main chain forks from the locator
+	GetHeaders(ctx context.Context, in *GetHeadersRequest, opts ...grpc.CallOption) (*Headers, error)
+	// Get a single block, or the announced transactions and blocks that are missing
 	GetData(ctx context.Context, in *GetDataRequest, opts ...grpc.CallOption) (*GetDataResponse, error)
+	// Announces transactions and blocks, so that peers can get the ones they are missing
+	SendInv(ctx context.Context, in *Inv, opts ...grpc.CallOption) (*Empty, error)
+	// Sends a new block in compact form
+	SendCmpctBlk(ctx context.Context, in *CompactBlock, opts ...grpc.CallOption) (*Empty, error)
+	// Gets the transactions of a block that a compact block was missing
+	GetBlockTxn(ctx context.Context, in *GetBlockTxnRequest, opts ...grpc.CallOption) (*BlockTxn, error)
 	// Sends know addresses to neighbors, forwarded from node to node
 	SendAddresses(ctx context.Context, in *Addresses, opts ...grpc.CallOption) (*Empty, error)
 	// Gets neighbor addresses from node (can be multicast with static addr_me)
 	GetAddresses(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Addresses, error)
+	// Gets the merkle proof of a transaction on the main chain
+	GetMerkleProof(ctx context.Context, in *GetMerkleProofRequest, opts ...grpc.CallOption) (*MerkleProof, error)
+	// Gets the merkle proofs of the transactions on the main chain matching a light node's filter, a page at a time
+	GetMerkleProofs(ctx context.Context, in *GetMerkleProofsRequest, opts ...grpc.CallOption) (*MerkleProofs, error)
 }
 
 type brunoCoinClient struct {
@@ -58,8 +72,8 @@ func (c *brunoCoinClient) ForwardBlock(ctx context.Context, in *Block, opts ...g
 	return out, nil
 }
 
-func (c *brunoCoinClient) Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *brunoCoinClient) Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*Verack, error) {
+	out := new(Verack)
 	err := c.cc.Invoke(ctx, "/BrunoCoin/Version", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -67,6 +81,15 @@ func (c *brunoCoinClient) Version(ctx context.Context, in *VersionRequest, opts
 	return out, nil
 }
 
+func (c *brunoCoinClient) SendVerack(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/BrunoCoin/SendVerack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *brunoCoinClient) GetBlocks(ctx context.Context, in *GetBlocksRequest, opts ...grpc.CallOption) (*GetBlocksResponse, error) {
 	out := new(GetBlocksResponse)
 	err := c.cc.Invoke(ctx, "/BrunoCoin/GetBlocks", in, out, opts...)
@@ -76,6 +99,15 @@ func (c *brunoCoinClient) GetBlocks(ctx context.Context, in *GetBlocksRequest, o
 	return out, nil
 }
 
+func (c *brunoCoinClient) GetHeaders(ctx context.Context, in *GetHeadersRequest, opts ...grpc.CallOption) (*Headers, error) {
+	out := new(Headers)
+	err := c.cc.Invoke(ctx, "/BrunoCoin/GetHeaders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *brunoCoinClient) GetData(ctx context.Context, in *GetDataRequest, opts ...grpc.CallOption) (*GetDataResponse, error) {
 	out := new(GetDataResponse)
 	err := c.cc.Invoke(ctx, "/BrunoCoin/GetData", in, out, opts...)
@@ -85,6 +117,33 @@ func (c *brunoCoinClient) GetData(ctx context.Context, in *GetDataRequest, opts
 	return out, nil
 }
 
+func (c *brunoCoinClient) SendInv(ctx context.Context, in *Inv, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/BrunoCoin/SendInv", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brunoCoinClient) SendCmpctBlk(ctx context.Context, in *CompactBlock, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/BrunoCoin/SendCmpctBlk", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brunoCoinClient) GetBlockTxn(ctx context.Context, in *GetBlockTxnRequest, opts ...grpc.CallOption) (*BlockTxn, error) {
+	out := new(BlockTxn)
+	err := c.cc.Invoke(ctx, "/BrunoCoin/GetBlockTxn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *brunoCoinClient) SendAddresses(ctx context.Context, in *Addresses, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
 	err := c.cc.Invoke(ctx, "/BrunoCoin/SendAddresses", in, out, opts...)
@@ -103,22 +162,54 @@ func (c *brunoCoinClient) GetAddresses(ctx context.Context, in *Empty, opts ...g
 	return out, nil
 }
 
+func (c *brunoCoinClient) GetMerkleProof(ctx context.Context, in *GetMerkleProofRequest, opts ...grpc.CallOption) (*MerkleProof, error) {
+	out := new(MerkleProof)
+	err := c.cc.Invoke(ctx, "/BrunoCoin/GetMerkleProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *brunoCoinClient) GetMerkleProofs(ctx context.Context, in *GetMerkleProofsRequest, opts ...grpc.CallOption) (*MerkleProofs, error) {
+	out := new(MerkleProofs)
+	err := c.cc.Invoke(ctx, "/BrunoCoin/GetMerkleProofs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BrunoCoinServer is the server API for BrunoCoin service.
 // All implementations must embed UnimplementedBrunoCoinServer
 // for forward compatibility
 type BrunoCoinServer interface {
 	ForwardTransaction(context.Context, *Transaction) (*Empty, error)
 	ForwardBlock(context.Context, *Block) (*Empty, error)
-	// Establishes a one way connection to a node (may be reciprocated)
-	Version(context.Context, *VersionRequest) (*Empty, error)
+	// Peers with a node, which acknowledges with its own version, or rejects with a Rejection detail
+	Version(context.Context, *VersionRequest) (*Verack, error)
+	// Accepts the verack of a node, which only then adds this node as a peer
+	SendVerack(context.Context, *Empty) (*Empty, error)
 	// Gets maximum 500 blocks past block with top hash
 	GetBlocks(context.Context, *GetBlocksRequest) (*GetBlocksResponse, error)
-	// Get a single block
+	// Gets maximum 2000 headers past where the main chain forks from the locator
+	GetHeaders(context.Context, *GetHeadersRequest) (*Headers, error)
+	// Get a single block, or the announced transactions and blocks that are missing
 	GetData(context.Context, *GetDataRequest) (*GetDataResponse, error)
+	// Announces transactions and blocks, so that peers can get the ones they are missing
+	SendInv(context.Context, *Inv) (*Empty, error)
+	// Sends a new block in compact form
+	SendCmpctBlk(context.Context, *CompactBlock) (*Empty, error)
+	// Gets the transactions of a block that a compact block was missing
+	GetBlockTxn(context.Context, *GetBlockTxnRequest) (*BlockTxn, error)
 	// Sends know addresses to neighbors, forwarded from node to node
 	SendAddresses(context.Context, *Addresses) (*Empty, error)
 	// Gets neighbor addresses from node (can be multicast with static addr_me)
 	GetAddresses(context.Context, *Empty) (*Addresses, error)
+	// Gets the merkle proof of a transaction on the main chain
+	GetMerkleProof(context.Context, *GetMerkleProofRequest) (*MerkleProof, error)
+	// Gets the merkle proofs of the transactions on the main chain matching a light node's filter, a page at a time
+	GetMerkleProofs(context.Context, *GetMerkleProofsRequest) (*MerkleProofs, error)
 	mustEmbedUnimplementedBrunoCoinServer()
 }
 
@@ -132,21 +223,42 @@ func (UnimplementedBrunoCoinServer) ForwardTransaction(context.Context, *Transac
 func (UnimplementedBrunoCoinServer) ForwardBlock(context.Context, *Block) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ForwardBlock not implemented")
 }
-func (UnimplementedBrunoCoinServer) Version(context.Context, *VersionRequest) (*Empty, error) {
+func (UnimplementedBrunoCoinServer) Version(context.Context, *VersionRequest) (*Verack, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
 }
+func (UnimplementedBrunoCoinServer) SendVerack(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendVerack not implemented")
+}
 func (UnimplementedBrunoCoinServer) GetBlocks(context.Context, *GetBlocksRequest) (*GetBlocksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBlocks not implemented")
 }
+func (UnimplementedBrunoCoinServer) GetHeaders(context.Context, *GetHeadersRequest) (*Headers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHeaders not implemented")
+}
 func (UnimplementedBrunoCoinServer) GetData(context.Context, *GetDataRequest) (*GetDataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetData not implemented")
 }
+func (UnimplementedBrunoCoinServer) SendInv(context.Context, *Inv) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendInv not implemented")
+}
+func (UnimplementedBrunoCoinServer) SendCmpctBlk(context.Context, *CompactBlock) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendCmpctBlk not implemented")
+}
+func (UnimplementedBrunoCoinServer) GetBlockTxn(context.Context, *GetBlockTxnRequest) (*BlockTxn, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockTxn not implemented")
+}
 func (UnimplementedBrunoCoinServer) SendAddresses(context.Context, *Addresses) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendAddresses not implemented")
 }
 func (UnimplementedBrunoCoinServer) GetAddresses(context.Context, *Empty) (*Addresses, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAddresses not implemented")
 }
+func (UnimplementedBrunoCoinServer) GetMerkleProof(context.Context, *GetMerkleProofRequest) (*MerkleProof, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMerkleProof not implemented")
+}
+func (UnimplementedBrunoCoinServer) GetMerkleProofs(context.Context, *GetMerkleProofsRequest) (*MerkleProofs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMerkleProofs not implemented")
+}
 func (UnimplementedBrunoCoinServer) mustEmbedUnimplementedBrunoCoinServer() {}
 
 // UnsafeBrunoCoinServer may be embedded to opt out of forward compatibility for this service.
@@ -214,6 +326,24 @@ func _BrunoCoin_Version_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BrunoCoin_SendVerack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrunoCoinServer).SendVerack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BrunoCoin/SendVerack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrunoCoinServer).SendVerack(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BrunoCoin_GetBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetBlocksRequest)
 	if err := dec(in); err != nil {
@@ -232,6 +362,24 @@ func _BrunoCoin_GetBlocks_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BrunoCoin_GetHeaders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHeadersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrunoCoinServer).GetHeaders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BrunoCoin/GetHeaders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrunoCoinServer).GetHeaders(ctx, req.(*GetHeadersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BrunoCoin_GetData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetDataRequest)
 	if err := dec(in); err != nil {
@@ -250,6 +398,60 @@ func _BrunoCoin_GetData_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BrunoCoin_SendInv_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Inv)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrunoCoinServer).SendInv(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BrunoCoin/SendInv",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrunoCoinServer).SendInv(ctx, req.(*Inv))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrunoCoin_SendCmpctBlk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompactBlock)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrunoCoinServer).SendCmpctBlk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BrunoCoin/SendCmpctBlk",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrunoCoinServer).SendCmpctBlk(ctx, req.(*CompactBlock))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrunoCoin_GetBlockTxn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockTxnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrunoCoinServer).GetBlockTxn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BrunoCoin/GetBlockTxn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrunoCoinServer).GetBlockTxn(ctx, req.(*GetBlockTxnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BrunoCoin_SendAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Addresses)
 	if err := dec(in); err != nil {
@@ -286,6 +488,42 @@ func _BrunoCoin_GetAddresses_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BrunoCoin_GetMerkleProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMerkleProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrunoCoinServer).GetMerkleProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BrunoCoin/GetMerkleProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrunoCoinServer).GetMerkleProof(ctx, req.(*GetMerkleProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BrunoCoin_GetMerkleProofs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMerkleProofsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrunoCoinServer).GetMerkleProofs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/BrunoCoin/GetMerkleProofs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrunoCoinServer).GetMerkleProofs(ctx, req.(*GetMerkleProofsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BrunoCoin_ServiceDesc is the grpc.ServiceDesc for BrunoCoin service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,14 +543,34 @@ var BrunoCoin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Version",
 			Handler:    _BrunoCoin_Version_Handler,
 		},
+		{
+			MethodName: "SendVerack",
+			Handler:    _BrunoCoin_SendVerack_Handler,
+		},
 		{
 			MethodName: "GetBlocks",
 			Handler:    _BrunoCoin_GetBlocks_Handler,
 		},
+		{
+			MethodName: "GetHeaders",
+			Handler:    _BrunoCoin_GetHeaders_Handler,
+		},
 		{
 			MethodName: "GetData",
 			Handler:    _BrunoCoin_GetData_Handler,
 		},
+		{
+			MethodName: "SendInv",
+			Handler:    _BrunoCoin_SendInv_Handler,
+		},
+		{
+			MethodName: "SendCmpctBlk",
+			Handler:    _BrunoCoin_SendCmpctBlk_Handler,
+		},
+		{
+			MethodName: "GetBlockTxn",
+			Handler:    _BrunoCoin_GetBlockTxn_Handler,
+		},
 		{
 			MethodName: "SendAddresses",
 			Handler:    _BrunoCoin_SendAddresses_Handler,
@@ -321,6 +579,376 @@ var BrunoCoin_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAddresses",
 			Handler:    _BrunoCoin_GetAddresses_Handler,
 		},
+		{
+			MethodName: "GetMerkleProof",
+			Handler:    _BrunoCoin_GetMerkleProof_Handler,
+		},
+		{
+			MethodName: "GetMerkleProofs",
+			Handler:    _BrunoCoin_GetMerkleProofs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "advancedcoin.proto",
+}
+
+// PaymentChannelClient is the client API for PaymentChannel service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PaymentChannelClient interface {
+	OpenChannel(ctx context.Context, in *ChannelOpen, opts ...grpc.CallOption) (*Empty, error)
+	UpdateChannel(ctx context.Context, in *ChannelUpdate, opts ...grpc.CallOption) (*Empty, error)
+	// Asks the payee to close the channel with the last update, returns the closing transaction
+	CloseChannel(ctx context.Context, in *ChannelClose, opts ...grpc.CallOption) (*Transaction, error)
+}
+
+type paymentChannelClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPaymentChannelClient(cc grpc.ClientConnInterface) PaymentChannelClient {
+	return &paymentChannelClient{cc}
+}
+
+func (c *paymentChannelClient) OpenChannel(ctx context.Context, in *ChannelOpen, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/PaymentChannel/OpenChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentChannelClient) UpdateChannel(ctx context.Context, in *ChannelUpdate, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/PaymentChannel/UpdateChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentChannelClient) CloseChannel(ctx context.Context, in *ChannelClose, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, "/PaymentChannel/CloseChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentChannelServer is the server API for PaymentChannel service.
+// All implementations must embed UnimplementedPaymentChannelServer
+// for forward compatibility
+type PaymentChannelServer interface {
+	OpenChannel(context.Context, *ChannelOpen) (*Empty, error)
+	UpdateChannel(context.Context, *ChannelUpdate) (*Empty, error)
+	// Asks the payee to close the channel with the last update, returns the closing transaction
+	CloseChannel(context.Context, *ChannelClose) (*Transaction, error)
+	mustEmbedUnimplementedPaymentChannelServer()
+}
+
+// UnimplementedPaymentChannelServer must be embedded to have forward compatible implementations.
+type UnimplementedPaymentChannelServer struct {
+}
+
+func (UnimplementedPaymentChannelServer) OpenChannel(context.Context, *ChannelOpen) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenChannel not implemented")
+}
+func (UnimplementedPaymentChannelServer) UpdateChannel(context.Context, *ChannelUpdate) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateChannel not implemented")
+}
+func (UnimplementedPaymentChannelServer) CloseChannel(context.Context, *ChannelClose) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
+}
+func (UnimplementedPaymentChannelServer) mustEmbedUnimplementedPaymentChannelServer() {}
+
+// UnsafePaymentChannelServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PaymentChannelServer will
+// result in compilation errors.
+type UnsafePaymentChannelServer interface {
+	mustEmbedUnimplementedPaymentChannelServer()
+}
+
+func RegisterPaymentChannelServer(s grpc.ServiceRegistrar, srv PaymentChannelServer) {
+	s.RegisterService(&PaymentChannel_ServiceDesc, srv)
+}
+
+func _PaymentChannel_OpenChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelOpen)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentChannelServer).OpenChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/PaymentChannel/OpenChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentChannelServer).OpenChannel(ctx, req.(*ChannelOpen))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentChannel_UpdateChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelUpdate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentChannelServer).UpdateChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/PaymentChannel/UpdateChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentChannelServer).UpdateChannel(ctx, req.(*ChannelUpdate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentChannel_CloseChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelClose)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentChannelServer).CloseChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/PaymentChannel/CloseChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentChannelServer).CloseChannel(ctx, req.(*ChannelClose))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentChannel_ServiceDesc is the grpc.ServiceDesc for PaymentChannel service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PaymentChannel_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "PaymentChannel",
+	HandlerType: (*PaymentChannelServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "OpenChannel",
+			Handler:    _PaymentChannel_OpenChannel_Handler,
+		},
+		{
+			MethodName: "UpdateChannel",
+			Handler:    _PaymentChannel_UpdateChannel_Handler,
+		},
+		{
+			MethodName: "CloseChannel",
+			Handler:    _PaymentChannel_CloseChannel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "advancedcoin.proto",
+}
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	// Lists the addresses the node refuses to peer with
+	ListBans(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Bans, error)
+	// Disconnects from an address and refuses to peer with it for a while
+	AddBan(ctx context.Context, in *BanRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Lets a banned address peer again
+	RmvBan(ctx context.Context, in *BanRequest, opts ...grpc.CallOption) (*Empty, error)
+	// Lists how many transactions and blocks the node rejected, by reason
+	GetRejections(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RejectionCounts, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ListBans(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Bans, error) {
+	out := new(Bans)
+	err := c.cc.Invoke(ctx, "/Admin/ListBans", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AddBan(ctx context.Context, in *BanRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/Admin/AddBan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RmvBan(ctx context.Context, in *BanRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/Admin/RmvBan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetRejections(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RejectionCounts, error) {
+	out := new(RejectionCounts)
+	err := c.cc.Invoke(ctx, "/Admin/GetRejections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	// Lists the addresses the node refuses to peer with
+	ListBans(context.Context, *Empty) (*Bans, error)
+	// Disconnects from an address and refuses to peer with it for a while
+	AddBan(context.Context, *BanRequest) (*Empty, error)
+	// Lets a banned address peer again
+	RmvBan(context.Context, *BanRequest) (*Empty, error)
+	// Lists how many transactions and blocks the node rejected, by reason
+	GetRejections(context.Context, *Empty) (*RejectionCounts, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) ListBans(context.Context, *Empty) (*Bans, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBans not implemented")
+}
+func (UnimplementedAdminServer) AddBan(context.Context, *BanRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBan not implemented")
+}
+func (UnimplementedAdminServer) RmvBan(context.Context, *BanRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RmvBan not implemented")
+}
+func (UnimplementedAdminServer) GetRejections(context.Context, *Empty) (*RejectionCounts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRejections not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_ListBans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListBans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Admin/ListBans",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListBans(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AddBan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AddBan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Admin/AddBan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AddBan(ctx, req.(*BanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RmvBan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RmvBan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Admin/RmvBan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RmvBan(ctx, req.(*BanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetRejections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetRejections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Admin/GetRejections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetRejections(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBans",
+			Handler:    _Admin_ListBans_Handler,
+		},
+		{
+			MethodName: "AddBan",
+			Handler:    _Admin_AddBan_Handler,
+		},
+		{
+			MethodName: "RmvBan",
+			Handler:    _Admin_RmvBan_Handler,
+		},
+		{
+			MethodName: "GetRejections",
+			Handler:    _Admin_GetRejections_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "advancedcoin.proto",
@@ -0,0 +1,192 @@
+package pkg
+
+import (
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"sync"
+)
+
+// MxInv (MaxInventory) is the most transactions and
+// blocks a node announces or asks for at once.
+const MxInv = 1000
+
+// MxRly (MaxRelay) is the most transactions a node
+// keeps for peers to get after it announced them.
+const MxRly = 5000
+
+// TxRly (TransactionRelay) holds the transactions a node
+// announced to its peers, so that they can get them with
+// GetData. Past its limit, the oldest are dropped.
+// lim (limit) is the most transactions it holds
+// txs maps the hashes of the transactions to them
+// ordr (order) is the hashes, oldest first
+type TxRly struct {
+	lim   int
+	txs   map[string]*tx.Transaction
+	ordr  []string
+	mutex sync.Mutex
+}
+
+// NewTxRly (NewTransactionRelay) creates an empty relay
+// that holds at most lim transactions.
+func NewTxRly(lim int) *TxRly {
+	return &TxRly{lim: lim, txs: make(map[string]*tx.Transaction)}
+}
+
+// Add adds a transaction to the relay.
+func (r *TxRly) Add(t *tx.Transaction) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	h := t.Hash()
+	if r.txs[h] != nil {
+		return
+	}
+	if len(r.ordr) >= r.lim {
+		delete(r.txs, r.ordr[0])
+		r.ordr = r.ordr[1:]
+	}
+	r.txs[h] = t
+	r.ordr = append(r.ordr, h)
+}
+
+// Get returns the transaction with hash h, or nil if
+// the relay doesn't have it.
+func (r *TxRly) Get(h string) *tx.Transaction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.txs[h]
+}
+
+// Txs (Transactions) returns the transactions in
+// the relay.
+func (r *TxRly) Txs() []*tx.Transaction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	txs := make([]*tx.Transaction, 0, len(r.txs))
+	for _, t := range r.txs {
+		txs = append(txs, t)
+	}
+	return txs
+}
+
+// Announce sends inventory to every peer that isn't
+// known to have it already. The peers then get what
+// they are missing with GetData. A peer is only known
+// to have the inventory once it was sent, so a peer
+// that couldn't be reached gets it the next time.
+// Inputs:
+// items []*proto.InvItem the transactions and blocks
+func (n *Node) Announce(items []*proto.InvItem) {
+	for _, p := range n.PeerDb.List() {
+		var inv []*proto.InvItem
+		for _, it := range items {
+			if !p.Known.Has(it.Hash) {
+				inv = append(inv, it)
+			}
+		}
+		if len(inv) == 0 {
+			continue
+		}
+		go func(p *peer.Peer) {
+			_, err := p.Addr.SendInvRPC(&proto.Inv{Items: inv}, address.From(n.Addr))
+			if err != nil {
+				utils.Debug.Printf("%v recieved no response from SendInvRPC to %v",
+					utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr))
+				return
+			}
+			for _, it := range inv {
+				p.Known.Add(it.Hash)
+			}
+		}(p)
+	}
+}
+
+// RlyTx (RelayTransaction) keeps a transaction for peers
+// to get, and announces it to them.
+// Inputs:
+// t *tx.Transaction the transaction
+func (n *Node) RlyTx(t *tx.Transaction) {
+	n.TxRly.Add(t)
+	utils.Debug.Printf("%v announcing %v", utils.FmtAddr(n.Addr), t.NameTag())
+	n.Announce([]*proto.InvItem{{Type: proto.InvType_TX, Hash: t.Hash()}})
+}
+
+// mrkKnwn (markKnown) records that the peer at addr has
+// a transaction or block, if it is a peer.
+func (n *Node) mrkKnwn(addr string, h string) {
+	if p := n.PeerDb.Get(addr); p != nil {
+		p.Known.Add(h)
+	}
+}
+
+// hasInv (hasInventory) returns whether the node has an
+// announced transaction or block, or doesn't want it.
+// Light nodes don't want transactions.
+func (n *Node) hasInv(it *proto.InvItem) bool {
+	if it.Type == proto.InvType_BLOCK {
+		n.BlockMapMutex.Lock()
+		defer n.BlockMapMutex.Unlock()
+		return n.BlockMap[it.Hash] || n.Chain.Has(it.Hash)
+	}
+	n.TxMapMutex.Lock()
+	defer n.TxMapMutex.Unlock()
+	return n.TxMap[it.Hash] || n.Conf.ChainConf.HdrsOnly
+}
+
+// reqInv (requestInventory) marks a transaction or block
+// as being asked for, so only one peer is asked for it
+// at a time.
+// Returns:
+// bool False if it is already being asked for
+func (n *Node) reqInv(h string) bool {
+	n.inFltMutex.Lock()
+	defer n.inFltMutex.Unlock()
+	if n.inFlt[h] {
+		return false
+	}
+	n.inFlt[h] = true
+	return true
+}
+
+// getInv (getInventory) gets announced transactions and
+// blocks from the node that announced them, and handles
+// them as if that node had sent them.
+// Inputs:
+// a *address.Address the address of the node
+// want []*proto.InvItem the transactions and blocks
+func (n *Node) getInv(a *address.Address, want []*proto.InvItem) {
+	asked := make(map[string]bool)
+	for _, it := range want {
+		asked[it.Hash] = true
+	}
+	defer func() {
+		n.inFltMutex.Lock()
+		for h := range asked {
+			delete(n.inFlt, h)
+		}
+		n.inFltMutex.Unlock()
+	}()
+	res, err := a.GetDataRPC(&proto.GetDataRequest{Items: want}, address.From(n.Addr))
+	if err != nil {
+		utils.Debug.Printf("%v recieved no response from GetDataRPC to %v",
+			utils.FmtAddr(n.Addr), utils.FmtAddr(a.Addr))
+		return
+	}
+	for _, pt := range res.Transactions {
+		if t := tx.Deserialize(pt); asked[t.Hash()] {
+			n.HndlNwTx(t, a.Addr)
+		}
+	}
+	for _, pb := range res.Blocks {
+		if pb.Header == nil {
+			continue
+		}
+		if b := block.Deserialize(pb); asked[b.Hash()] {
+			n.HndlFwdBlk(b, a.Addr)
+		}
+	}
+}
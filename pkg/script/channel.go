@@ -0,0 +1,73 @@
+package script
+
+import "bytes"
+
+// Chnl (Channel) returns the redeem script of a
+// unidirectional payment channel, which is unlocked by
+// signatures from both the payer and the payee, or by
+// the payer's signature once the lock time has passed.
+// Outputs are locked to its pay to script hash.
+// Close unlocking script: <payer sig> <payee sig> Op1 <rdm>
+// Refund unlocking script: <payer sig> OpFalse <rdm>
+// Inputs:
+// payer []byte the public key of the payer
+// payee []byte the public key of the payee
+// lck uint32 a block height, or a Unix timestamp if it
+// is at least LckTmThrsh
+// Returns:
+// []byte the script
+func Chnl(payer, payee []byte, lck uint32) []byte {
+	return New().
+		Op(OpIf, Op1+1).Push(payer).Push(payee).Op(Op1+1, OpChkMltSig).
+		Op(OpElse).
+		Num(int64(lck)).Op(OpChkLckTmVfy, OpDrop).
+		Push(payer).Op(OpChkSig).
+		Op(OpEndIf).Bytes()
+}
+
+// PrsChnl (ParseChannel) gets the public keys and the
+// lock time out of a script made by Chnl.
+// Returns:
+// []byte the public key of the payer
+// []byte the public key of the payee
+// uint32 the lock time
+// bool False if the script wasn't made by Chnl
+func PrsChnl(scr []byte) ([]byte, []byte, uint32, bool) {
+	ins, err := Prs(scr)
+	if err != nil || len(ins) != 13 {
+		return nil, nil, 0, false
+	}
+	lck, ok := lckNum(ins[7])
+	payer, payee := ins[2].Data, ins[3].Data
+	if !ok || !bytes.Equal(Chnl(payer, payee, lck), scr) {
+		return nil, nil, 0, false
+	}
+	return payer, payee, lck, true
+}
+
+// ChnlCls (ChannelClose) returns the unlocking script
+// that closes a channel with both signatures.
+// Inputs:
+// payer []byte the payer's signature (with its sighash
+// flag)
+// payee []byte the payee's signature
+// rdm []byte the channel's script (see Chnl)
+// Returns:
+// string the unlocking script as a hex string
+func ChnlCls(payer, payee, rdm []byte) string {
+	return New().Push(payer).Push(payee).Op(Op1).Push(rdm).Hex()
+}
+
+// ChnlRfnd (ChannelRefund) returns the unlocking script
+// the payer uses to take back a channel on its own. The
+// spending transaction's lock time has to be at or after
+// the channel's.
+// Inputs:
+// sig []byte the payer's signature (with its sighash
+// flag)
+// rdm []byte the channel's script (see Chnl)
+// Returns:
+// string the unlocking script as a hex string
+func ChnlRfnd(sig, rdm []byte) string {
+	return New().Push(sig).Op(OpFalse).Push(rdm).Hex()
+}
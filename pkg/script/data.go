@@ -0,0 +1,60 @@
+package script
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+)
+
+// MxDataSz (MaxDataSize) is the most bytes a data
+// output can carry.
+const MxDataSz = 80
+
+// Data returns a locking script that carries a payload
+// and can never be unlocked, since it starts with OpRet.
+// Outputs locked by it hold no money and are left out of
+// the utxo set.
+// Locking script: OpRet <d>
+// Inputs:
+// d []byte the payload
+// Returns:
+// string the locking script as a hex string
+// error if the payload is more than MxDataSz bytes
+func Data(d []byte) (string, error) {
+	if len(d) > MxDataSz {
+		return "", fmt.Errorf("payload of %v bytes is over the limit of %v", len(d), MxDataSz)
+	}
+	return New().Op(OpRet).Push(d).Hex(), nil
+}
+
+// IsData returns whether a locking script can never be
+// unlocked because it starts with OpRet.
+// Inputs:
+// lck string the locking script as a hex string
+func IsData(lck string) bool {
+	return len(lck) >= 2 && lck[:2] == hex.EncodeToString([]byte{OpRet})
+}
+
+// DataOf gets the payload out of a locking script made
+// by Data.
+// Inputs:
+// lck string the locking script as a hex string
+// Returns:
+// []byte the payload
+// bool False if the script wasn't made by Data, or its
+// payload is more than MxDataSz bytes
+func DataOf(lck string) ([]byte, bool) {
+	scr, err := hex.DecodeString(lck)
+	if err != nil || len(scr) == 0 || scr[0] != OpRet {
+		return nil, false
+	}
+	ins, err := Prs(scr[1:])
+	if err != nil || len(ins) != 1 || ins[0].Op > OpPshDt2 {
+		return nil, false
+	}
+	d := ins[0].Data
+	if len(d) > MxDataSz || !bytes.Equal(New().Op(OpRet).Push(d).Bytes(), scr) {
+		return nil, false
+	}
+	return d, true
+}
@@ -0,0 +1,119 @@
+package script
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HTLCTrms (HashTimeLockedContractTerms) are the terms of
+// a hash time locked contract.
+// Hsh (Hash) is the sha256 of the secret (the preimage)
+// that the receiver needs to claim the output
+// Rcv (Receiver) is the public key of the receiver
+// Snd (Sender) is the public key of the sender, who can
+// take the output back once Lck has passed
+// Lck (Lock) is a block height, or a Unix timestamp if it
+// is at least LckTmThrsh
+type HTLCTrms struct {
+	Hsh []byte
+	Rcv []byte
+	Snd []byte
+	Lck uint32
+}
+
+// HTLC (HashTimeLockedContract) returns the redeem script
+// of a hash time locked contract, which is unlocked by
+// the receiver's signature and the preimage of the hash,
+// or by the sender's signature once the lock time has
+// passed. Outputs are locked to its pay to script hash.
+// Claim unlocking script: <sig> <preimage> Op1 <rdm>
+// Refund unlocking script: <sig> OpFalse <rdm>
+// Inputs:
+// t HTLCTrms the terms of the contract
+// Returns:
+// []byte the script
+func HTLC(t HTLCTrms) []byte {
+	return New().
+		Op(OpIf, OpSha256).Push(t.Hsh).Op(OpEqlVfy).
+		Push(t.Rcv).
+		Op(OpElse).
+		Num(int64(t.Lck)).Op(OpChkLckTmVfy, OpDrop).
+		Push(t.Snd).
+		Op(OpEndIf, OpChkSig).Bytes()
+}
+
+// PrsHTLC (ParseHashTimeLockedContract) gets the terms
+// out of a script made by HTLC.
+// Returns:
+// HTLCTrms the terms of the contract
+// bool False if the script wasn't made by HTLC
+func PrsHTLC(scr []byte) (HTLCTrms, bool) {
+	ins, err := Prs(scr)
+	if err != nil || len(ins) != 12 {
+		return HTLCTrms{}, false
+	}
+	lck, ok := lckNum(ins[6])
+	t := HTLCTrms{Hsh: ins[2].Data, Rcv: ins[4].Data, Snd: ins[9].Data, Lck: lck}
+	if !ok || !bytes.Equal(HTLC(t), scr) {
+		return HTLCTrms{}, false
+	}
+	return t, true
+}
+
+// HTLCClm (HashTimeLockedContractClaim) returns the
+// unlocking script the receiver uses to claim an output
+// locked to the pay to script hash of rdm.
+// Inputs:
+// sig []byte the receiver's signature (with its sighash
+// flag)
+// pre []byte the preimage of the contract's hash
+// rdm []byte the contract (see HTLC)
+// Returns:
+// string the unlocking script as a hex string
+func HTLCClm(sig, pre, rdm []byte) string {
+	return New().Push(sig).Push(pre).Op(Op1).Push(rdm).Hex()
+}
+
+// HTLCRfnd (HashTimeLockedContractRefund) returns the
+// unlocking script the sender uses to take back an
+// output locked to the pay to script hash of rdm. The
+// spending transaction's lock time has to be at or
+// after the contract's.
+// Inputs:
+// sig []byte the sender's signature (with its sighash
+// flag)
+// rdm []byte the contract (see HTLC)
+// Returns:
+// string the unlocking script as a hex string
+func HTLCRfnd(sig, rdm []byte) string {
+	return New().Push(sig).Op(OpFalse).Push(rdm).Hex()
+}
+
+// HTLCPre (HashTimeLockedContractPreimage) looks for the
+// preimage of a hash in an unlocking script. Once the
+// receiver claims a contract, the preimage is public,
+// which is what lets the other side of a swap claim its
+// own contract.
+// Inputs:
+// unlck string the unlocking script as a hex string
+// hsh []byte the sha256 of the preimage
+// Returns:
+// []byte the preimage
+// bool False if the unlocking script doesn't have it
+func HTLCPre(unlck string, hsh []byte) ([]byte, bool) {
+	b, err := hex.DecodeString(unlck)
+	if err != nil {
+		return nil, false
+	}
+	ins, err := Prs(b)
+	if err != nil {
+		return nil, false
+	}
+	for _, in := range ins {
+		if h := sha256.Sum256(in.Data); in.Op <= OpPshDt2 && bytes.Equal(h[:], hsh) {
+			return in.Data, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,419 @@
+package script
+
+import (
+	"BrunoCoin/pkg/utils"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// SigHshr (SignatureHasher) returns the hash that a
+// signature with a particular sighash flag has to sign.
+// It is made by the transaction spending the output
+// (see tx.Transaction.SigHsh), which binds the signature
+// to that transaction.
+type SigHshr func(ht byte) (string, error)
+
+// Spnd (Spend) is what scripts know about the input
+// spending the output they lock.
+// SigHsh (SignatureHash) makes the hash signatures have
+// to sign
+// LckTm (LockTime) is the lock time of the spending
+// transaction, which OpChkLckTmVfy checks against
+type Spnd struct {
+	SigHsh SigHshr
+	LckTm  uint32
+}
+
+// stk (stack) is the stack scripts run on.
+type stk [][]byte
+
+func (s *stk) push(v []byte) error {
+	if len(v) > MxElSz {
+		return fmt.Errorf("value of %v bytes is over the limit of %v", len(v), MxElSz)
+	}
+	if len(*s) >= MxStk {
+		return errors.New("stack is full")
+	}
+	*s = append(*s, v)
+	return nil
+}
+
+func (s *stk) pop() ([]byte, error) {
+	if len(*s) == 0 {
+		return nil, errors.New("stack is empty")
+	}
+	v := (*s)[len(*s)-1]
+	*s = (*s)[:len(*s)-1]
+	return v, nil
+}
+
+func (s *stk) popBool() (bool, error) {
+	v, err := s.pop()
+	return isTrue(v), err
+}
+
+func (s *stk) pushBool(b bool) error {
+	if b {
+		return s.push([]byte{1})
+	}
+	return s.push(nil)
+}
+
+// isTrue returns whether a value is true, which is
+// whenever it has a byte that is not 0 (a final 0x80,
+// negative zero, also counts as 0).
+func isTrue(v []byte) bool {
+	for i, b := range v {
+		if b != 0 && !(i == len(v)-1 && b == 0x80) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs a locking script with the unlocking script of
+// the input spending it. The unlocking script can only
+// push values, which the locking script then runs on.
+// The locking script unlocks if it finishes with true on
+// top of the stack. If the locking script is pay to
+// script hash, the last value pushed by the unlocking
+// script is the redeem script, which also has to finish
+// with true, running on the other values.
+// A locking script that is only a public key (how outputs
+// were locked before scripts) is pay to public key, and
+// its unlocking script is only the signature.
+// Inputs:
+// unlck string the unlocking script as a hex string
+// lck string the locking script as a hex string
+// sp *Spnd the input spending the locking script
+// Returns:
+// error why the locking script didn't unlock, or nil if
+// it did
+func Run(unlck, lck string, sp *Spnd) error {
+	lckB, err := hex.DecodeString(lck)
+	if err != nil {
+		return errors.New("locking script is not hex")
+	}
+	unlckB, err := hex.DecodeString(unlck)
+	if err != nil {
+		return errors.New("unlocking script is not hex")
+	}
+	if IsLgcy(lckB) {
+		if !chkSig(lckB, unlckB, sp) {
+			return errors.New("signature does not unlock the public key")
+		}
+		return nil
+	}
+
+	ins, err := Prs(unlckB)
+	if err != nil {
+		return err
+	}
+	for _, in := range ins {
+		if !IsPsh(in.Op) {
+			return fmt.Errorf("unlocking script has opcode %#x, but can only push", in.Op)
+		}
+	}
+	var s stk
+	if err := run(unlckB, &s, sp); err != nil {
+		return err
+	}
+	p2sh := append(stk(nil), s...)
+	if err := run(lckB, &s, sp); err != nil {
+		return err
+	}
+	if ok, err := s.popBool(); err != nil || !ok {
+		return errors.New("locking script finished false")
+	}
+	if !IsP2SH(lckB) {
+		return nil
+	}
+
+	rdm, _ := p2sh.pop()
+	if err := run(rdm, &p2sh, sp); err != nil {
+		return fmt.Errorf("redeem script: %v", err)
+	}
+	if ok, err := p2sh.popBool(); err != nil || !ok {
+		return errors.New("redeem script finished false")
+	}
+	return nil
+}
+
+// run runs a script on a stack.
+func run(scr []byte, s *stk, sp *Spnd) error {
+	if len(scr) > MxScrSz {
+		return fmt.Errorf("script of %v bytes is over the limit of %v", len(scr), MxScrSz)
+	}
+	ins, err := Prs(scr)
+	if err != nil {
+		return err
+	}
+	// cnd (conditions) holds whether each OpIf we are in
+	// is taking its branch. Opcodes only run if all are.
+	var cnd []bool
+	ops := 0
+	for _, in := range ins {
+		exc := true
+		for _, c := range cnd {
+			exc = exc && c
+		}
+		if !IsPsh(in.Op) {
+			if ops++; ops > MxOps {
+				return fmt.Errorf("script runs more than %v opcodes", MxOps)
+			}
+		}
+
+		switch in.Op {
+		case OpIf, OpNotIf:
+			b := false
+			if exc {
+				if b, err = s.popBool(); err != nil {
+					return err
+				}
+				if in.Op == OpNotIf {
+					b = !b
+				}
+			}
+			cnd = append(cnd, b)
+			continue
+		case OpElse:
+			if len(cnd) == 0 {
+				return errors.New("OpElse without OpIf")
+			}
+			cnd[len(cnd)-1] = !cnd[len(cnd)-1]
+			continue
+		case OpEndIf:
+			if len(cnd) == 0 {
+				return errors.New("OpEndIf without OpIf")
+			}
+			cnd = cnd[:len(cnd)-1]
+			continue
+		}
+		if !exc {
+			continue
+		}
+
+		if err := step(in, s, sp); err != nil {
+			return err
+		}
+	}
+	if len(cnd) != 0 {
+		return errors.New("OpIf without OpEndIf")
+	}
+	return nil
+}
+
+// step runs a single instruction other than the
+// conditionals.
+func step(in Instr, s *stk, sp *Spnd) error {
+	switch {
+	case in.Op <= OpPshDt2:
+		return s.push(in.Data)
+	case in.Op >= Op1 && in.Op <= Op16:
+		return s.push([]byte{in.Op - Op1 + 1})
+	}
+
+	switch in.Op {
+	case OpNop:
+	case OpVfy:
+		ok, err := s.popBool()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("OpVfy failed")
+		}
+	case OpRet:
+		return errors.New("OpRet")
+	case OpDrop:
+		_, err := s.pop()
+		return err
+	case OpDup:
+		v, err := s.pop()
+		if err != nil {
+			return err
+		}
+		s.push(v)
+		return s.push(v)
+	case OpSwap:
+		a, err := s.pop()
+		if err != nil {
+			return err
+		}
+		b, err := s.pop()
+		if err != nil {
+			return err
+		}
+		s.push(a)
+		return s.push(b)
+	case OpEql, OpEqlVfy:
+		a, err := s.pop()
+		if err != nil {
+			return err
+		}
+		b, err := s.pop()
+		if err != nil {
+			return err
+		}
+		if in.Op == OpEqlVfy {
+			if !bytes.Equal(a, b) {
+				return errors.New("OpEqlVfy failed")
+			}
+			return nil
+		}
+		return s.pushBool(bytes.Equal(a, b))
+	case OpSha256, OpHsh256:
+		v, err := s.pop()
+		if err != nil {
+			return err
+		}
+		h := sha256.Sum256(v)
+		if in.Op == OpHsh256 {
+			h = sha256.Sum256(h[:])
+		}
+		return s.push(h[:])
+	case OpChkSig, OpChkSigVfy:
+		pk, err := s.pop()
+		if err != nil {
+			return err
+		}
+		sig, err := s.pop()
+		if err != nil {
+			return err
+		}
+		ok := chkSig(pk, sig, sp)
+		if in.Op == OpChkSigVfy {
+			if !ok {
+				return errors.New("OpChkSigVfy failed")
+			}
+			return nil
+		}
+		return s.pushBool(ok)
+	case OpChkMltSig, OpChkMltSigVfy:
+		ok, err := chkMltSig(s, sp)
+		if err != nil {
+			return err
+		}
+		if in.Op == OpChkMltSigVfy {
+			if !ok {
+				return errors.New("OpChkMltSigVfy failed")
+			}
+			return nil
+		}
+		return s.pushBool(ok)
+	case OpChkLckTmVfy:
+		return chkLckTm(s, sp)
+	default:
+		return fmt.Errorf("unknown opcode %#x", in.Op)
+	}
+	return nil
+}
+
+// popNum (popNumber) pops a number off of the stack
+// and checks that it is between 0 and mx.
+func (s *stk) popNum(mx int64) (int64, error) {
+	v, err := s.pop()
+	if err != nil {
+		return 0, err
+	}
+	n, err := DecNum(v, MxNumSz)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || n > mx {
+		return 0, fmt.Errorf("%v is not between 0 and %v", n, mx)
+	}
+	return n, nil
+}
+
+// chkLckTm (checkLockTime) runs OpChkLckTmVfy. The lock
+// time on top of the stack has to be the same kind as
+// the lock time of the spending transaction (a height or
+// a timestamp, see LckTmThrsh), and can't be after it.
+// Since the transaction can't be on a block until its
+// lock time has passed (see tx.Transaction.IsFinal),
+// neither can the spend.
+func chkLckTm(s *stk, sp *Spnd) error {
+	if len(*s) == 0 {
+		return errors.New("stack is empty")
+	}
+	n, err := DecNum((*s)[len(*s)-1], MxLckSz)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return fmt.Errorf("lock time %v is negative", n)
+	}
+	if (n < int64(LckTmThrsh)) != (sp.LckTm < LckTmThrsh) {
+		return errors.New("lock time is not the same kind as the transaction's")
+	}
+	if n > int64(sp.LckTm) {
+		return fmt.Errorf("lock time %v is after the transaction's %v", n, sp.LckTm)
+	}
+	return nil
+}
+
+// chkMltSig (checkMultiSignature) runs OpChkMltSig:
+// <sig 1> ... <sig m> <m> <pk 1> ... <pk n> <n>
+func chkMltSig(s *stk, sp *Spnd) (bool, error) {
+	n, err := s.popNum(MxMsKeys)
+	if err != nil {
+		return false, err
+	}
+	pks := make([][]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		if pks[i], err = s.pop(); err != nil {
+			return false, err
+		}
+	}
+	m, err := s.popNum(n)
+	if err != nil {
+		return false, err
+	}
+	sigs := make([][]byte, m)
+	for i := m - 1; i >= 0; i-- {
+		if sigs[i], err = s.pop(); err != nil {
+			return false, err
+		}
+	}
+	// Every signature is matched with the first
+	// public key after the last match that it is
+	// valid for.
+	k := 0
+	for _, sig := range sigs {
+		for k < len(pks) && !chkSig(pks[k], sig, sp) {
+			k++
+		}
+		if k == len(pks) {
+			return false, nil
+		}
+		k++
+	}
+	return true, nil
+}
+
+// chkSig (checkSignature) checks a signature against a
+// public key. The last byte of the signature is its
+// sighash flag, which decides the hash that it signs.
+func chkSig(pkB, sig []byte, sp *Spnd) bool {
+	if len(sig) < 2 {
+		return false
+	}
+	pk, err := utils.Byt2PK(pkB)
+	if err != nil {
+		return false
+	}
+	hsh, err := sp.SigHsh(sig[len(sig)-1])
+	if err != nil {
+		return false
+	}
+	h, err := hex.DecodeString(hsh)
+	if err != nil {
+		return false
+	}
+	return ecdsa.VerifyASN1(pk, h, sig[:len(sig)-1])
+}
@@ -0,0 +1,271 @@
+package script
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Opcodes of the script language. A script is a list
+// of opcodes, and is stored on transactions as a hex
+// string. Opcodes 0x01-0x4b push that many of the bytes
+// after them onto the stack.
+const (
+	// OpFalse pushes an empty value (false).
+	OpFalse byte = 0x00
+	// OpPshDt1 (OpPushData1) pushes the amount of bytes
+	// given by the next byte.
+	OpPshDt1 byte = 0x4c
+	// OpPshDt2 (OpPushData2) pushes the amount of bytes
+	// given by the next 2 little endian bytes.
+	OpPshDt2 byte = 0x4d
+	// Op1 through Op16 push the numbers 1 to 16. Op1 is
+	// also true.
+	Op1  byte = 0x51
+	Op16 byte = 0x60
+	// OpNop does nothing.
+	OpNop byte = 0x61
+	// OpIf runs the opcodes up to the matching OpElse or
+	// OpEndIf if the top of the stack is true. OpNotIf
+	// does the opposite.
+	OpIf    byte = 0x63
+	OpNotIf byte = 0x64
+	OpElse  byte = 0x67
+	OpEndIf byte = 0x68
+	// OpVfy (OpVerify) fails the script if the top of
+	// the stack is false.
+	OpVfy byte = 0x69
+	// OpRet (OpReturn) fails the script.
+	OpRet byte = 0x6a
+	// OpDrop, OpDup and OpSwap remove, copy and swap
+	// the top of the stack.
+	OpDrop byte = 0x75
+	OpDup  byte = 0x76
+	OpSwap byte = 0x7c
+	// OpEql (OpEqual) replaces the top two values with
+	// whether they are equal. OpEqlVfy (OpEqualVerify)
+	// fails the script if they are not.
+	OpEql    byte = 0x87
+	OpEqlVfy byte = 0x88
+	// OpSha256 replaces the top of the stack with its
+	// sha256. OpHsh256 (OpHash256) uses sha256 twice.
+	OpSha256 byte = 0xa8
+	OpHsh256 byte = 0xaa
+	// OpChkSig (OpCheckSignature) replaces a signature
+	// and the public key on top of it with whether the
+	// signature signs the spending transaction.
+	// OpChkSigVfy (OpCheckSignatureVerify) fails the
+	// script if it doesn't.
+	OpChkSig    byte = 0xac
+	OpChkSigVfy byte = 0xad
+	// OpChkMltSig (OpCheckMultiSignature) pops a number
+	// of public keys n, the n public keys, a number of
+	// signatures m and the m signatures, and pushes
+	// whether every signature is valid for a different
+	// public key. The signatures have to be in the same
+	// order as their public keys. OpChkMltSigVfy
+	// (OpCheckMultiSignatureVerify) fails the script if
+	// they aren't.
+	OpChkMltSig    byte = 0xae
+	OpChkMltSigVfy byte = 0xaf
+	// OpChkLckTmVfy (OpCheckLockTimeVerify) fails the
+	// script unless the number on top of the stack is a
+	// lock time that the spending transaction's lock time
+	// is at or after. It leaves the number on the stack.
+	OpChkLckTmVfy byte = 0xb1
+)
+
+// Limits that keep running a script cheap.
+// MxScrSz (MaxScriptSize) is the most bytes a script
+// can have.
+// MxElSz (MaxElementSize) is the most bytes a value on
+// the stack can have.
+// MxOps (MaxOperations) is the most opcodes, other than
+// pushes, a script can run.
+// MxStk (MaxStack) is the most values the stack can hold.
+// MxMsKeys (MaxMultisigKeys) is the most public keys
+// OpChkMltSig can check against.
+// MxNumSz (MaxNumberSize) is the most bytes a number
+// can have.
+// MxLckSz (MaxLockSize) is the most bytes a lock time
+// can have, since lock times use all 32 bits.
+const (
+	MxScrSz  = 10000
+	MxElSz   = 520
+	MxOps    = 201
+	MxStk    = 1000
+	MxMsKeys = 16
+	MxNumSz  = 4
+	MxLckSz  = 5
+)
+
+// LckTmThrsh (LockTimeThreshold) splits the meaning of
+// lock times. Lock times below it are block heights,
+// and lock times at or above it are Unix timestamps.
+const LckTmThrsh uint32 = 500000000
+
+// Instr (Instruction) is an opcode and, for pushes,
+// the bytes it pushes.
+type Instr struct {
+	Op   byte
+	Data []byte
+}
+
+// Prs (Parse) splits a script into instructions.
+// Inputs:
+// s []byte the script
+// Returns:
+// []Instr the instructions of the script
+// error if a push runs past the end of the script
+func Prs(s []byte) ([]Instr, error) {
+	var ins []Instr
+	for i := 0; i < len(s); {
+		op := s[i]
+		i++
+		n := 0
+		switch {
+		case op > OpFalse && op < OpPshDt1:
+			n = int(op)
+		case op == OpPshDt1:
+			if i+1 > len(s) {
+				return nil, errors.New("truncated OpPshDt1")
+			}
+			n = int(s[i])
+			i++
+		case op == OpPshDt2:
+			if i+2 > len(s) {
+				return nil, errors.New("truncated OpPshDt2")
+			}
+			n = int(binary.LittleEndian.Uint16(s[i:]))
+			i += 2
+		}
+		if i+n > len(s) {
+			return nil, fmt.Errorf("push of %v bytes runs past the end of the script", n)
+		}
+		in := Instr{Op: op}
+		if op <= OpPshDt2 {
+			in.Data = s[i : i+n]
+		}
+		ins = append(ins, in)
+		i += n
+	}
+	return ins, nil
+}
+
+// IsPsh (IsPush) returns whether an opcode only pushes
+// a value onto the stack.
+func IsPsh(op byte) bool {
+	return op <= OpPshDt2 || (op >= Op1 && op <= Op16)
+}
+
+// Bldr (Builder) builds a script.
+type Bldr struct {
+	b []byte
+}
+
+// New creates an empty script builder.
+func New() *Bldr {
+	return &Bldr{}
+}
+
+// Op adds opcodes to the script.
+func (b *Bldr) Op(ops ...byte) *Bldr {
+	b.b = append(b.b, ops...)
+	return b
+}
+
+// Push adds the opcode that pushes d onto the stack.
+func (b *Bldr) Push(d []byte) *Bldr {
+	switch {
+	case len(d) == 0:
+		b.b = append(b.b, OpFalse)
+	case len(d) < int(OpPshDt1):
+		b.b = append(b.b, byte(len(d)))
+	case len(d) <= 0xff:
+		b.b = append(b.b, OpPshDt1, byte(len(d)))
+	default:
+		b.b = append(b.b, OpPshDt2, byte(len(d)), byte(len(d)>>8))
+	}
+	b.b = append(b.b, d...)
+	return b
+}
+
+// Num (Number) adds the opcode that pushes the
+// number n onto the stack. Numbers from 0 to 16 use
+// OpFalse and Op1 to Op16. Other numbers are pushed as
+// little endian bytes, with the top bit of the last byte
+// as the sign, using as few bytes as possible.
+func (b *Bldr) Num(n int64) *Bldr {
+	switch {
+	case n == 0:
+		return b.Op(OpFalse)
+	case n >= 1 && n <= 16:
+		return b.Op(Op1 + byte(n-1))
+	}
+	return b.Push(EncNum(n))
+}
+
+// Bytes returns the script.
+func (b *Bldr) Bytes() []byte {
+	return b.b
+}
+
+// Hex returns the script as a hex string, which is
+// how scripts are stored on transactions.
+func (b *Bldr) Hex() string {
+	return hex.EncodeToString(b.b)
+}
+
+// EncNum (EncodeNumber) encodes a number the way
+// scripts store numbers on the stack: little endian,
+// with the top bit of the last byte as the sign, and
+// as few bytes as possible. 0 is empty.
+func EncNum(n int64) []byte {
+	if n == 0 {
+		return nil
+	}
+	neg := n < 0
+	u := uint64(n)
+	if neg {
+		u = uint64(-n)
+	}
+	var b []byte
+	for u > 0 {
+		b = append(b, byte(u))
+		u >>= 8
+	}
+	if b[len(b)-1]&0x80 != 0 {
+		b = append(b, 0)
+	}
+	if neg {
+		b[len(b)-1] |= 0x80
+	}
+	return b
+}
+
+// DecNum (DecodeNumber) decodes a number from the
+// stack (see EncNum).
+// Inputs:
+// v []byte the value on the stack
+// mx int the most bytes the number can have
+// Returns:
+// int64 the number
+// error if v is longer than mx
+func DecNum(v []byte, mx int) (int64, error) {
+	if len(v) > mx {
+		return 0, fmt.Errorf("number of %v bytes is over the limit of %v", len(v), mx)
+	}
+	if len(v) == 0 {
+		return 0, nil
+	}
+	var n int64
+	for i, b := range v {
+		n |= int64(b) << (8 * uint(i))
+	}
+	if v[len(v)-1]&0x80 != 0 {
+		n &^= int64(0x80) << (8 * uint(len(v)-1))
+		n = -n
+	}
+	return n, nil
+}
@@ -0,0 +1,208 @@
+package script
+
+import (
+	"BrunoCoin/pkg/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Hsh256 (Hash256) is sha256 used twice. Pay to public
+// key hash and pay to script hash outputs lock to it.
+func Hsh256(b []byte) []byte {
+	h := sha256.Sum256(b)
+	h = sha256.Sum256(h[:])
+	return h[:]
+}
+
+// P2PK (PayToPublicKey) returns a locking script that
+// is unlocked by a signature from a public key.
+// Unlocking script: <sig>
+func P2PK(pk []byte) string {
+	return New().Push(pk).Op(OpChkSig).Hex()
+}
+
+// P2PKH (PayToPublicKeyHash) returns a locking script
+// that is unlocked by a public key with the hash of pk
+// and a signature from it.
+// Unlocking script: <sig> <pk>
+func P2PKH(pk []byte) string {
+	return New().Op(OpDup, OpHsh256).Push(Hsh256(pk)).Op(OpEqlVfy, OpChkSig).Hex()
+}
+
+// P2SH (PayToScriptHash) returns a locking script that
+// is unlocked by a redeem script with the hash of rdm
+// and the values that make it finish true.
+// Unlocking script: <values...> <rdm>
+func P2SH(rdm []byte) string {
+	return New().Op(OpHsh256).Push(Hsh256(rdm)).Op(OpEql).Hex()
+}
+
+// IsP2SH (IsPayToScriptHash) returns whether a locking
+// script is pay to script hash.
+func IsP2SH(lck []byte) bool {
+	return len(lck) == 35 && lck[0] == OpHsh256 &&
+		lck[1] == 32 && lck[34] == OpEql
+}
+
+// IsLgcy (IsLegacy) returns whether a locking script is
+// only a public key, which is how outputs were locked
+// before scripts.
+func IsLgcy(lck []byte) bool {
+	_, err := utils.Byt2PK(lck)
+	return err == nil
+}
+
+// Pays returns whether a locking script is one of the
+// standard scripts that pay a public key: a legacy
+// public key, pay to public key or pay to public key
+// hash. To check many locking scripts, use PKScrs.
+// Inputs:
+// lck string the locking script as a hex string
+// pk string the public key as a hex string
+func Pays(lck string, pk string) bool {
+	return PKScrs(pk)[lck]
+}
+
+// PKScrs (PublicKeyScripts) returns the standard scripts
+// that pay a public key (see Pays), so that many locking
+// scripts can be checked without encoding them again.
+// Inputs:
+// pk string the public key as a hex string
+// Returns:
+// map[string]bool the locking scripts as hex strings
+func PKScrs(pk string) map[string]bool {
+	scrs := map[string]bool{pk: true}
+	if pkB, err := hex.DecodeString(pk); err == nil {
+		scrs[P2PK(pkB)] = true
+		scrs[P2PKH(pkB)] = true
+	}
+	return scrs
+}
+
+// Unlck (Unlock) returns the unlocking script for a
+// standard locking script that pays a public key.
+// Inputs:
+// lck string the locking script as a hex string
+// sig string the signature (with its sighash flag) as
+// a hex string
+// pk []byte the public key the script pays
+// Returns:
+// string the unlocking script as a hex string
+// error if the locking script doesn't pay pk
+func Unlck(lck string, sig string, pk []byte) (string, error) {
+	sigB, err := hex.DecodeString(sig)
+	if err != nil {
+		return "", err
+	}
+	switch lck {
+	case hex.EncodeToString(pk):
+		return sig, nil
+	case P2PK(pk):
+		return New().Push(sigB).Hex(), nil
+	case P2PKH(pk):
+		return New().Push(sigB).Push(pk).Hex(), nil
+	}
+	return "", errors.New("locking script does not pay the public key")
+}
+
+// MltSig (MultiSignature) returns a script that is
+// unlocked by signatures from m of the public keys.
+// It can lock an output (as a hex string), or be the
+// redeem script of a pay to script hash output.
+// Unlocking script: <sig 1> ... <sig m>, with the
+// signatures in the same order as their public keys
+// Inputs:
+// m int how many signatures are needed
+// pks [][]byte the public keys
+// Returns:
+// []byte the script
+// error if m or the number of public keys is not allowed
+func MltSig(m int, pks [][]byte) ([]byte, error) {
+	if len(pks) == 0 || len(pks) > MxMsKeys {
+		return nil, fmt.Errorf("multisig needs 1 to %v public keys", MxMsKeys)
+	}
+	if m < 1 || m > len(pks) {
+		return nil, fmt.Errorf("multisig can't need %v of %v signatures", m, len(pks))
+	}
+	b := New().Num(int64(m))
+	for _, pk := range pks {
+		b.Push(pk)
+	}
+	return b.Num(int64(len(pks))).Op(OpChkMltSig).Bytes(), nil
+}
+
+// PrsMltSig (ParseMultiSignature) gets the number of
+// signatures and the public keys out of a script made
+// by MltSig.
+// Returns:
+// int how many signatures are needed
+// [][]byte the public keys
+// bool False if the script wasn't made by MltSig
+func PrsMltSig(scr []byte) (int, [][]byte, bool) {
+	ins, err := Prs(scr)
+	if err != nil || len(ins) < 4 || ins[len(ins)-1].Op != OpChkMltSig {
+		return 0, nil, false
+	}
+	m, n := smlNum(ins[0].Op), smlNum(ins[len(ins)-2].Op)
+	pks := make([][]byte, 0)
+	for _, in := range ins[1 : len(ins)-2] {
+		if in.Op > OpPshDt2 || len(in.Data) == 0 {
+			return 0, nil, false
+		}
+		pks = append(pks, in.Data)
+	}
+	if m < 1 || n != len(pks) || m > n {
+		return 0, nil, false
+	}
+	return m, pks, true
+}
+
+// smlNum (smallNumber) returns the number pushed by
+// Op1 to Op16, or 0.
+func smlNum(op byte) int {
+	if op >= Op1 && op <= Op16 {
+		return int(op-Op1) + 1
+	}
+	return 0
+}
+
+// lckNum (lockNumber) returns the lock time pushed by
+// an instruction (see Bldr.Num).
+// Returns:
+// uint32 the lock time
+// bool False if the instruction doesn't push a lock time
+func lckNum(in Instr) (uint32, bool) {
+	if n := smlNum(in.Op); n > 0 {
+		return uint32(n), true
+	}
+	if in.Op > OpPshDt2 {
+		return 0, false
+	}
+	n, err := DecNum(in.Data, MxLckSz)
+	if err != nil || n < 0 || n > int64(^uint32(0)) {
+		return 0, false
+	}
+	return uint32(n), true
+}
+
+// MltSigUnlck (MultiSignatureUnlock) returns the
+// unlocking script for a multisig output.
+// Inputs:
+// sigs [][]byte the signatures, in the same order as
+// their public keys
+// rdm []byte the multisig script if the output is pay
+// to script hash, otherwise nil
+// Returns:
+// string the unlocking script as a hex string
+func MltSigUnlck(sigs [][]byte, rdm []byte) string {
+	b := New()
+	for _, sig := range sigs {
+		b.Push(sig)
+	}
+	if rdm != nil {
+		b.Push(rdm)
+	}
+	return b.Hex()
+}
@@ -4,12 +4,15 @@ import (
 	"BrunoCoin/pkg/address"
 	"BrunoCoin/pkg/block"
 	"BrunoCoin/pkg/block/tx"
-	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/block/tx/txo"
 	"BrunoCoin/pkg/proto"
 	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
 	"errors"
 	"fmt"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"time"
 )
 
@@ -26,36 +29,38 @@ func (n *Node) peerCheck(addr string) error {
 	return nil
 }
 
-// Handles version request (a request to become a peer)
-func (n *Node) Version(ctx context.Context, in *proto.VersionRequest) (*proto.Empty, error) {
-	// Reject all outdated versions (this is not true to Satoshi Client)
-	if int(in.Version) != n.Conf.Version {
-		return &proto.Empty{}, nil
+// Handles version request (a request to become a peer, acknowledged with this node's version)
+func (n *Node) Version(ctx context.Context, in *proto.VersionRequest) (*proto.Verack, error) {
+	// The node's address must be on the host the request came from,
+	// so it can't peer (and be banned) as another node
+	ip := trnsprt(ctx)
+	if n.Bans.Has(ip) {
+		return nil, rjctVer(RjctBan, "%v is banned", ip)
 	}
-	// If addr map is full or does not contain addr of ver, reject
-	newAddr := address.New(in.AddrMe, uint32(time.Now().UnixNano()))
-	if n.AddrDb.Get(newAddr.Addr) != nil {
-		err := n.AddrDb.UpdateLastSeen(newAddr.Addr, newAddr.LastSeen)
-		if err != nil {
-			return &proto.Empty{}, nil
-		}
-	} else if err := n.AddrDb.Add(newAddr); err != nil {
-		return &proto.Empty{}, nil
+	if !onHst(in.AddrMe, ip) {
+		return nil, rjctVer(RjctAddr, "%v is not on host %v", in.AddrMe, ip)
 	}
-	newPeer := peer.New(n.AddrDb.Get(newAddr.Addr), in.Version, in.BestHeight)
-	// Check if we are waiting for a ver in response to a ver, do not respond if this is a confirmation of peering
-	pendingVer := newPeer.Addr.SentVer != time.Time{} && newPeer.Addr.SentVer.Add(n.Conf.VerTimeout).After(time.Now())
-	if n.PeerDb.Add(newPeer) && !pendingVer {
-		newPeer.Addr.SentVer = time.Now()
-		_, err := newAddr.VersionRPC(&proto.VersionRequest{
-			Version:    uint32(n.Conf.Version),
-			AddrYou:    in.AddrMe,
-			AddrMe:     n.Addr,
-			BestHeight: uint32(n.Chain.Length()),
-		})
-		if err != nil {
-			return &proto.Empty{}, err
-		}
+	tkn := mkNonce()
+	p, err := n.mkPeer(in.AddrMe, in, tkn)
+	if err == nil {
+		err = n.addPndng(p)
+	}
+	if err != nil {
+		utils.Debug.Printf("%v refused to peer with %v: %v", utils.FmtAddr(n.Addr), utils.FmtAddr(in.AddrMe), err)
+		return nil, err
+	}
+	return &proto.Verack{Version: n.verMsg(in.AddrMe, tkn)}, nil
+}
+
+// Handles send verack request (a node accepting the verack this node sent it, so they are both peers)
+func (n *Node) SendVerack(ctx context.Context, in *proto.Empty) (*proto.Empty, error) {
+	p := n.takePndng(address.Sender(ctx), address.SenderTkn(ctx))
+	if p == nil {
+		return nil, status.Error(codes.PermissionDenied, "no verack is waiting to be accepted")
+	}
+	if err := n.addPeer(p); err != nil {
+		utils.Debug.Printf("%v refused to peer with %v: %v", utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr), err)
+		return nil, err
 	}
 	return &proto.Empty{}, nil
 }
@@ -63,6 +68,10 @@ func (n *Node) Version(ctx context.Context, in *proto.VersionRequest) (*proto.Em
 // Handles get blocks request (request for blocks past a certain block)
 func (n *Node) GetBlocks(ctx context.Context, in *proto.GetBlocksRequest) (*proto.GetBlocksResponse, error) {
 	blockHashes := make([]string, 0)
+	// Light nodes don't have the blocks' transactions
+	if n.Conf.ChainConf.HdrsOnly {
+		return &proto.GetBlocksResponse{BlockHashes: blockHashes}, nil
+	}
 	if ind := n.Chain.IndexOf(in.TopBlockHash); ind != -1 && ind < n.Chain.Length() {
 		upperIndex := n.Chain.Length()
 		// Can send a maximum of 50 0 headers
@@ -76,23 +85,134 @@ func (n *Node) GetBlocks(ctx context.Context, in *proto.GetBlocksRequest) (*prot
 	return &proto.GetBlocksResponse{BlockHashes: blockHashes}, nil
 }
 
-// Handles get data request (request for a specific block identified by its hash)
+// Handles get headers request (request for the headers of main chain blocks past where it forks from the requester's)
+func (n *Node) GetHeaders(ctx context.Context, in *proto.GetHeadersRequest) (*proto.Headers, error) {
+	hdrs := make([]*proto.BlockHeader, 0)
+	if ind := n.Chain.FndFork(in.Locator); ind != -1 {
+		for _, b := range n.Chain.Slice(ind+1, ind+1+MxHdrs) {
+			hdrs = append(hdrs, b.Hdr.Serialize())
+		}
+	}
+	return &proto.Headers{Headers: hdrs}, nil
+}
+
+// Handles get data request (request for a specific block identified by its hash, or for announced transactions and blocks)
 func (n *Node) GetData(ctx context.Context, in *proto.GetDataRequest) (*proto.GetDataResponse, error) {
+	res := &proto.GetDataResponse{}
+	for i, it := range in.Items {
+		if i >= MxInv {
+			break
+		}
+		if it.Type == proto.InvType_TX {
+			if t := n.TxRly.Get(it.Hash); t != nil {
+				res.Transactions = append(res.Transactions, t.Serialize())
+			}
+		} else if b := n.Chain.Get(it.Hash); b != nil && !n.Conf.ChainConf.HdrsOnly {
+			res.Blocks = append(res.Blocks, b.Serialize())
+		}
+	}
+	if in.BlockHash == "" {
+		return res, nil
+	}
 	blk := n.Chain.Get(in.BlockHash)
-	if blk == nil {
+	if blk == nil || n.Conf.ChainConf.HdrsOnly {
 		utils.Debug.Printf("Node {%v} received a data req from the network for a block {%v} that could not be found locally.\n",
 			n.Addr, in.BlockHash)
-		return &proto.GetDataResponse{}, nil
+		return res, nil
+	}
+	res.Block = blk.Serialize()
+	return res, nil
+}
+
+// Handles get merkle proof request (request for proof that a transaction is on the main chain)
+func (n *Node) GetMerkleProof(ctx context.Context, in *proto.GetMerkleProofRequest) (*proto.MerkleProof, error) {
+	b, h, i, found := n.Chain.FndTx(in.TxHash)
+	if !found || n.Conf.ChainConf.HdrsOnly {
+		return nil, status.Errorf(codes.NotFound, "transaction %v is not on the main chain", in.TxHash)
+	}
+	prf, _ := block.MrklPrf(b.Transactions, i)
+	return &proto.MerkleProof{
+		Header:      b.Hdr.Serialize(),
+		Height:      h,
+		Index:       uint32(i),
+		Branch:      prf,
+		Transaction: b.Transactions[i].Serialize(),
+	}, nil
+}
+
+// MxPrfBlks (MaxProofBlocks) is the most blocks looked
+// at for one GetMerkleProofs request.
+const MxPrfBlks = 2000
+
+// Handles get merkle proofs request (request for the transactions matching a light node's filter, a page at a time)
+func (n *Node) GetMerkleProofs(ctx context.Context, in *proto.GetMerkleProofsRequest) (*proto.MerkleProofs, error) {
+	if n.Conf.ChainConf.HdrsOnly {
+		return nil, status.Error(codes.FailedPrecondition, "light nodes don't have transactions")
+	}
+	lcks := make(map[string]bool)
+	for _, l := range in.LockingScripts {
+		lcks[l] = true
+	}
+	outs := make(map[string]bool)
+	for _, o := range in.Outpoints {
+		outs[o] = true
+	}
+	l := n.Chain.Length()
+	if int(in.FromHeight)+MxPrfBlks < l {
+		l = int(in.FromHeight) + MxPrfBlks
+	}
+	res := &proto.MerkleProofs{Height: uint32(l - 1)}
+	for i, b := range n.Chain.Slice(int(in.FromHeight), l) {
+		if len(res.Proofs) >= n.Conf.PrfLim {
+			res.Height = in.FromHeight + uint32(i) - 1
+			break
+		}
+		for j, t := range b.Transactions {
+			if !fltrTx(t, lcks, outs) {
+				continue
+			}
+			prf, _ := block.MrklPrf(b.Transactions, j)
+			res.Proofs = append(res.Proofs, &proto.MerkleProof{
+				Header:      b.Hdr.Serialize(),
+				Height:      in.FromHeight + uint32(i),
+				Index:       uint32(j),
+				Branch:      prf,
+				Transaction: t.Serialize(),
+			})
+		}
+	}
+	return res, nil
+}
+
+// fltrTx (filterTransaction) returns whether a
+// transaction spends one of the utxo locators in outs, or
+// has an output locked by one of the scripts in lcks. The
+// outputs it matches are added to outs, so a transaction
+// spending them later on the chain matches too.
+func fltrTx(t *tx.Transaction, lcks, outs map[string]bool) bool {
+	m := false
+	for _, i := range t.Inputs {
+		m = m || outs[txo.MkTXOLoc(i.TransactionHash, i.OutputIndex)]
+	}
+	for j, o := range t.Outputs {
+		if lcks[o.LockingScript] {
+			outs[txo.MkTXOLoc(t.Hash(), uint32(j))] = true
+			m = true
+		}
 	}
-	return &proto.GetDataResponse{Block: blk.Serialize()}, nil
+	return m
 }
 
 // Handles send addresses request (request for nodes to peer with the requesting node)
 func (n *Node) SendAddresses(ctx context.Context, in *proto.Addresses) (*proto.Empty, error) {
+	if n.Bans.Has(trnsprt(ctx)) {
+		return &proto.Empty{}, nil
+	}
 	// Forward nodes to all neighbors if new nodes were found (without redundancy)
 	foundNew := false
 	for _, addr := range in.Addrs {
-		if addr.Addr == n.Addr {
+		// Banned nodes are neither kept nor peered with
+		if addr.Addr == n.Addr || n.Bans.Has(addr.Addr) {
 			continue
 		}
 		newAddr := address.New(addr.Addr, addr.LastSeen)
@@ -119,19 +239,10 @@ func (n *Node) SendAddresses(ctx context.Context, in *proto.Addresses) (*proto.E
 				foundNew = true
 			}
 		}
-		// Try to connect to each new address as true peers (it is okay if this is repeated, this may be a reboot)
-		go func() {
-			_, err := newAddr.VersionRPC(&proto.VersionRequest{
-				Version:    uint32(n.Conf.Version),
-				AddrYou:    newAddr.Addr,
-				AddrMe:     n.Addr,
-				BestHeight: uint32(n.Chain.Length()),
-			})
-			if err != nil {
-				utils.Debug.Printf("%v recieved no response from VersionRPC to %v",
-					utils.FmtAddr(n.Addr), utils.FmtAddr(addr.Addr))
-			}
-		}()
+		// Try to connect to each new address as true peers
+		if !n.PeerDb.In(newAddr.Addr) {
+			go n.ConnectToPeer(newAddr.Addr)
+		}
 	}
 	if foundNew {
 		bcPeers := n.PeerDb.GetRandom(2, []string{n.Addr})
@@ -156,26 +267,8 @@ func (n *Node) GetAddresses(ctx context.Context, in *proto.Empty) (*proto.Addres
 // Handles forward transaction request (tx propagation)
 func (n *Node) ForwardTransaction(ctx context.Context, in *proto.Transaction) (*proto.Empty, error) {
 	t := tx.Deserialize(in)
-	if n.TxMap[t.Hash()] {
-		return &proto.Empty{}, nil
-	}
-	if !n.ChkTx(t) {
-		utils.Debug.Printf("%v recieved invalid %v", utils.FmtAddr(n.Addr), t.NameTag())
-		return &proto.Empty{}, errors.New("transaction is not valid")
-	}
-	utils.Debug.Printf("%v recieved valid %v", utils.FmtAddr(n.Addr), t.NameTag())
-	if n.Conf.MnrConf.HasMnr {
-		go n.Mnr.HndlTx(t)
-	}
-	n.TxMap[t.Hash()] = true
-	for _, p := range n.PeerDb.List() {
-		go func(addr *address.Address) {
-			_, err := addr.ForwardTransactionRPC(t.Serialize())
-			if err != nil {
-				utils.Debug.Printf("%v recieved no response from ForwardTransaction to %v",
-					utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr))
-			}
-		}(p.Addr)
+	if err := n.HndlNwTx(t, n.prSndr(ctx)); err != nil {
+		return &proto.Empty{}, valerr.Status(err, t.Hash())
 	}
 	return &proto.Empty{}, nil
 }
@@ -183,37 +276,32 @@ func (n *Node) ForwardTransaction(ctx context.Context, in *proto.Transaction) (*
 // Handles forward block request (block propagation)
 func (n *Node) ForwardBlock(ctx context.Context, in *proto.Block) (*proto.Empty, error) {
 	b := block.Deserialize(in)
-	// Ignore if already seen block
-	n.BlockMapMutex.Lock()
-	if n.BlockMap[b.Hash()] {
-		n.BlockMapMutex.Unlock()
-		return &proto.Empty{}, nil
+	if err := n.HndlFwdBlk(b, n.prSndr(ctx)); err != nil {
+		return &proto.Empty{}, valerr.Status(err, b.Hash())
 	}
-	n.BlockMap[b.Hash()] = true
-	n.BlockMapMutex.Unlock()
-	if !n.ChkBlk(b) {
-		utils.Debug.Printf("%v recieved invalid %v", utils.FmtAddr(n.Addr), b.NameTag())
-		return &proto.Empty{}, errors.New("block is not valid")
-	}
-	mnChn := n.Chain.IsEndMainChain(b)
-	n.Chain.Add(b)
-	if n.Conf.MnrConf.HasMnr && mnChn {
-		go n.Mnr.HndlBlk(b)
-	}
-	if n.Conf.WtConf.HasWt && mnChn {
-		blks := n.Chain.Slice(n.Chain.Length()-n.Conf.WtConf.SafeBlkAmt, n.Chain.Length())
-		if len(blks) == n.Conf.WtConf.SafeBlkAmt {
-			go n.Wallet.HndlBlk(blks[0])
+	return &proto.Empty{}, nil
+}
+
+// Handles send inventory request (announcement of transactions and blocks)
+func (n *Node) SendInv(ctx context.Context, in *proto.Inv) (*proto.Empty, error) {
+	// Only inventory from peers is fetched, so nodes can't be made to fetch from anywhere
+	p := n.PeerDb.Get(n.prSndr(ctx))
+	if p == nil {
+		return nil, status.Error(codes.PermissionDenied, "inventory is not from a peer")
+	}
+	from, a := p.Addr.Addr, p.Addr
+	var want []*proto.InvItem
+	for i, it := range in.Items {
+		if i >= MxInv {
+			break
+		}
+		n.mrkKnwn(from, it.Hash)
+		if !n.hasInv(it) && n.reqInv(it.Hash) {
+			want = append(want, it)
 		}
 	}
-	for _, p := range n.PeerDb.List() {
-		go func(addr *address.Address) {
-			_, err := addr.ForwardBlockRPC(b.Serialize())
-			if err != nil {
-				utils.Debug.Printf("%v recieved no response from ForwardBlockRPC to %v",
-					utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr))
-			}
-		}(p.Addr)
+	if len(want) > 0 {
+		go n.getInv(a, want)
 	}
 	return &proto.Empty{}, nil
 }
@@ -0,0 +1,266 @@
+package pkg
+
+import (
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MxHdrs (MaxHeaders) is the most headers a node
+// sends in reply to GetHeaders.
+const MxHdrs = 2000
+
+// MxDls (MaxDownloads) is the most blocks a node
+// downloads at once while bootstrapping.
+const MxDls = 16
+
+// hdrsRpl (headersReply) is the headers that some peers
+// sent in reply to GetHeaders.
+// hdrs []block.Header the headers, in chain order
+// ps []*peer.Peer the peers that sent them, which have
+// all of the blocks
+// work *big.Int the cumulative work of the chain the
+// headers lead to (see hdrsWork)
+type hdrsRpl struct {
+	hdrs []block.Header
+	ps   []*peer.Peer
+	work *big.Int
+}
+
+// getHdrs (getHeaders) asks every peer for the headers
+// past where its main chain forks from this node's (see
+// Blockchain.Locator). A full node only asks the peers
+// that have the blocks too (see fllPrs). Peers that
+// sent the same headers share a reply, and the replies
+// are returned with the most work first.
+// Inputs:
+// skp map[string]bool the addresses of the peers not to
+// ask
+// Returns:
+// []*hdrsRpl the replies
+// error if there are no peers, or none replied
+func (n *Node) getHdrs(skp map[string]bool) ([]*hdrsRpl, error) {
+	all := n.PeerDb.List()
+	if !n.Conf.ChainConf.HdrsOnly {
+		all = n.fllPrs()
+	}
+	var ps []*peer.Peer
+	for _, p := range all {
+		if !skp[p.Addr.Addr] {
+			ps = append(ps, p)
+		}
+	}
+	if len(ps) == 0 {
+		return nil, errors.New("no peers to sync from")
+	}
+	req := &proto.GetHeadersRequest{Locator: n.Chain.Locator(), AddrMe: n.Addr}
+	byTop := make(map[string]*hdrsRpl)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, p := range ps {
+		wg.Add(1)
+		go func(p *peer.Peer) {
+			defer wg.Done()
+			res, err := p.Addr.GetHeadersRPC(req, address.From(n.Addr))
+			if err != nil {
+				return
+			}
+			hdrs := make([]block.Header, len(res.Headers))
+			for i, h := range res.Headers {
+				hdrs[i] = block.DeserializeHdr(h)
+			}
+			var top string
+			if l := len(hdrs); l > 0 {
+				top = (&block.Block{Hdr: hdrs[l-1]}).Hash()
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			if r := byTop[top]; r != nil {
+				r.ps = append(r.ps, p)
+			} else {
+				byTop[top] = &hdrsRpl{hdrs: hdrs, ps: []*peer.Peer{p}}
+			}
+		}(p)
+	}
+	wg.Wait()
+	if len(byTop) == 0 {
+		return nil, errors.New("no peers gave responses")
+	}
+	rpls := make([]*hdrsRpl, 0, len(byTop))
+	for _, r := range byTop {
+		r.work = n.hdrsWork(r.hdrs)
+		rpls = append(rpls, r)
+	}
+	sort.Slice(rpls, func(i, j int) bool {
+		if c := rpls[i].work.Cmp(rpls[j].work); c != 0 {
+			return c > 0
+		}
+		return len(rpls[i].hdrs) > len(rpls[j].hdrs)
+	})
+	return rpls, nil
+}
+
+// hdrsWork (headersWork) returns the cumulative work of
+// the chain that headers lead to: the work of the block
+// they are on, plus the work of each header. No headers
+// lead to this node's main chain. The work is 0 if the
+// block they are on is unknown.
+// Inputs:
+// hdrs []block.Header the headers, in chain order
+func (n *Node) hdrsWork(hdrs []block.Header) *big.Int {
+	if len(hdrs) == 0 {
+		return n.Chain.Work(n.Chain.GetLastBlock().Hash())
+	}
+	w := n.Chain.Work(hdrs[0].PrvBlkHsh)
+	if w.Sign() == 0 {
+		return w
+	}
+	for _, h := range hdrs {
+		w.Add(w, utils.CalcWork(h.DiffTarg))
+	}
+	return w
+}
+
+// bstHdrs (bestHeaders) asks the peers for headers (see
+// getHdrs) and returns the valid reply with the most
+// work. The peers that sent invalid headers are dropped
+// (see drpPrs).
+// Inputs:
+// skp map[string]bool the addresses of the peers not to
+// ask, which the dropped peers are added to
+// Returns:
+// *hdrsRpl the reply
+// error if no peer sent valid headers
+func (n *Node) bstHdrs(skp map[string]bool) (*hdrsRpl, error) {
+	rpls, err := n.getHdrs(skp)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rpls {
+		err := n.chkHdrs(r.hdrs)
+		if err == nil {
+			return r, nil
+		}
+		n.Rjcts.Inc(err)
+		n.drpPrs(r.ps, skp, err)
+	}
+	return nil, errors.New("no peer sent valid headers")
+}
+
+// drpPrs (dropPeers) stops syncing from peers that sent
+// an invalid header or block, and scores them for it
+// (see Misbhv).
+// Inputs:
+// ps []*peer.Peer the peers
+// skp map[string]bool the addresses of the peers not to
+// sync from, which they are added to
+// err error why what they sent was rejected
+func (n *Node) drpPrs(ps []*peer.Peer, skp map[string]bool, err error) {
+	for _, p := range ps {
+		utils.Debug.Printf("%v not syncing from %v: %v", utils.FmtAddr(n.Addr), utils.FmtAddr(p.Addr.Addr), err)
+		skp[p.Addr.Addr] = true
+		n.Misbhv(p.Addr.Addr, peer.BadBlk, err)
+	}
+}
+
+// chkHdrs (checkHeaders) validates a chain of headers
+// from a peer (see Blockchain.ChkHdrs), and checks that
+// none of them are too far in the future.
+// Inputs:
+// hdrs []block.Header the headers, in chain order
+// Returns:
+// error why a header is invalid (a *valerr.Err), or nil
+func (n *Node) chkHdrs(hdrs []block.Header) error {
+	for _, h := range hdrs {
+		if err := n.chkDrft(h); err != nil {
+			return err
+		}
+	}
+	return n.Chain.ChkHdrs(hdrs)
+}
+
+// chkDrft (checkDrift) checks that a header is not more
+// than Conf.MxTmDrft in the future.
+// Inputs:
+// h block.Header the header
+// Returns:
+// error if it is too far in the future (a *valerr.Err),
+// or nil
+func (n *Node) chkDrft(h block.Header) error {
+	if mx := time.Now().Add(n.Conf.MxTmDrft).Unix(); int64(h.Timestamp) > mx {
+		return valerr.New(valerr.TimeTooNew, "timestamp %v is too far in the future", h.Timestamp)
+	}
+	return nil
+}
+
+// dlBlks (downloadBlocks) downloads the blocks of a chain
+// of headers, at most MxDls at once, spread over peers.
+// A block that a peer fails to send, or that doesn't
+// match its header, is asked for from the next peer.
+// Blocks already on the chain are not downloaded.
+// Inputs:
+// hdrs []block.Header the headers, in chain order
+// ps []*peer.Peer the peers that have the blocks
+// Returns:
+// []*block.Block the blocks, in chain order, up to the
+// first one that could not be downloaded
+// error if not every block could be downloaded
+func (n *Node) dlBlks(hdrs []block.Header, ps []*peer.Peer) ([]*block.Block, error) {
+	blks := make([]*block.Block, len(hdrs))
+	sem := make(chan struct{}, MxDls)
+	var wg sync.WaitGroup
+	for i, h := range hdrs {
+		hsh := (&block.Block{Hdr: h}).Hash()
+		if b := n.Chain.Get(hsh); b != nil {
+			blks[i] = b
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, hsh string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for j := range ps {
+				p := ps[(i+j)%len(ps)]
+				res, err := p.Addr.GetDataRPC(&proto.GetDataRequest{BlockHash: hsh}, address.From(n.Addr))
+				if err != nil || res.Block == nil || res.Block.Header == nil {
+					continue
+				}
+				if b := block.Deserialize(res.Block); b.Hash() == hsh {
+					blks[i] = b
+					return
+				}
+			}
+		}(i, hsh)
+	}
+	wg.Wait()
+	for i, b := range blks {
+		if b == nil {
+			return blks[:i], fmt.Errorf("could not download block %v from %v peers", i, len(ps))
+		}
+	}
+	return blks, nil
+}
+
+// fllPrs (fullPeers) returns the peers that have the full
+// chain (see peer.SvcChn).
+func (n *Node) fllPrs() []*peer.Peer {
+	var ps []*peer.Peer
+	for _, p := range n.PeerDb.List() {
+		if p.Svcs.Has(peer.SvcChn) {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
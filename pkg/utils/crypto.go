@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"crypto/x509"
 	"encoding/hex"
+	"errors"
 )
 
 // Hash returns the hash of the inputted
@@ -51,7 +52,11 @@ func Byt2PK(pkB []byte) (*ecdsa.PublicKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	return pk.(*ecdsa.PublicKey), nil
+	ePK, ok := pk.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not an ecdsa public key")
+	}
+	return ePK, nil
 }
 
 // Byt2SK deserializes the bytes
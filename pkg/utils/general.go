@@ -1,5 +1,7 @@
 package utils
 
+import "math/big"
+
 // RevStrArr (ReverseStringArray) reverses
 // the order of an array of strings in place.
 // Inputs:
@@ -57,3 +59,22 @@ func InSlice(a []string, val string) bool {
 	}
 	return false
 }
+
+// CalcWork (CalculateWork) calculates the amount of
+// work that a block with a certain difficulty target
+// represents, which is the expected number of hashes
+// needed to find a nonce that meets the target:
+// 2^256 / (target + 1).
+// Inputs:
+// dt string the difficulty target as a hex string
+// Returns:
+// *big.Int the work for the target. It is 0 if the
+// target can't be decoded.
+func CalcWork(dt string) *big.Int {
+	trg, ok := new(big.Int).SetString(dt, 16)
+	if dt == "" || !ok || trg.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	max := new(big.Int).Lsh(big.NewInt(1), 256)
+	return max.Div(max, trg.Add(trg, big.NewInt(1)))
+}
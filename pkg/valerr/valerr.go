@@ -0,0 +1,208 @@
+package valerr
+
+import (
+	"BrunoCoin/pkg/proto"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Code is the reason a transaction or block was
+// rejected during validation.
+type Code string
+
+const (
+	// Malformed means the transaction or block is
+	// missing required parts (no inputs, no outputs,
+	// no transactions, ...).
+	Malformed Code = "malformed"
+	// MissingInput means an input references utxo that
+	// doesn't exist or was already spent.
+	MissingInput Code = "missing-input"
+	// BadSig means an input's unlocking script does not
+	// unlock the utxo it references.
+	BadSig Code = "bad-signature"
+	// DblSpnd (DoubleSpend) means two inputs in the same
+	// transaction or block spend the same utxo.
+	DblSpnd Code = "double-spend"
+	// BadAmt (BadAmount) means an input's amount is not
+	// the amount of the utxo it references.
+	BadAmt Code = "bad-input-amount"
+	// InsufFunds (InsufficientFunds) means a transaction
+	// pays out more than its inputs.
+	InsufFunds Code = "insufficient-funds"
+	// Oversize means a transaction or block is over
+	// the maximum block size.
+	Oversize Code = "oversize"
+	// BadPOW (BadProofOfWork) means a block's hash does
+	// not satisfy its difficulty target.
+	BadPOW Code = "bad-pow"
+	// BadDifTrg (BadDifficultyTarget) means a block does
+	// not have the difficulty target the chain expects.
+	BadDifTrg Code = "bad-difficulty"
+	// BadMrkl (BadMerkleRoot) means a block's merkle root
+	// does not match its transactions.
+	BadMrkl Code = "bad-merkle"
+	// BadCB (BadCoinbase) means a block does not start
+	// with a valid coinbase transaction.
+	BadCB Code = "bad-coinbase"
+	// DupCB (DuplicateCoinbase) means a block has a
+	// coinbase transaction other than the first.
+	DupCB Code = "duplicate-coinbase"
+	// CBValue (CoinbaseValue) means the coinbase pays out
+	// more than the subsidy plus fees.
+	CBValue Code = "bad-coinbase-value"
+	// UnknownPrv (UnknownPrevious) means a block's
+	// previous block is not on the chain.
+	UnknownPrv Code = "unknown-parent"
+	// BadVer (BadVersion) means a block's version is not
+	// allowed.
+	BadVer Code = "bad-version"
+	// TimeTooOld means a block's timestamp is before the
+	// median time past.
+	TimeTooOld Code = "time-too-old"
+	// TimeTooNew means a block's timestamp is too far in
+	// the future.
+	TimeTooNew Code = "time-too-new"
+	// NonFinal means a block has a transaction whose lock
+	// time has not passed.
+	NonFinal Code = "non-final"
+	// BadData means a transaction has more than one data
+	// output, or one that holds money or carries too much
+	// data.
+	BadData Code = "bad-data"
+)
+
+// Err (Error) is a validation error.
+// Code is the reason code
+// Msg (Message) explains the reason in more detail
+type Err struct {
+	Code Code
+	Msg  string
+}
+
+// New creates a validation error.
+// Inputs:
+// c Code the reason code
+// format string, a ...interface{} the message, as for
+// fmt.Sprintf
+func New(c Code, format string, a ...interface{}) *Err {
+	return &Err{Code: c, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (e *Err) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Msg)
+}
+
+// CodeOf returns the reason code of an error.
+// Inputs:
+// err error any error
+// Returns:
+// Code the reason code if err is (or wraps) an *Err,
+// otherwise ""
+func CodeOf(err error) Code {
+	var e *Err
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// Status turns a validation error into a gRPC status
+// error carrying a proto.Rejection as a detail, so the
+// node that sent the transaction or block can find out
+// why it was rejected.
+// Inputs:
+// err error the validation error
+// h string the hash of the rejected transaction or block
+// Returns:
+// error the gRPC status error
+func Status(err error, h string) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+	dst, dErr := st.WithDetails(&proto.Rejection{
+		Code:   string(CodeOf(err)),
+		Reason: err.Error(),
+		Hash:   h,
+	})
+	if dErr != nil {
+		return st.Err()
+	}
+	return dst.Err()
+}
+
+// FromStatus gets the rejection out of an error
+// returned by a gRPC call.
+// Inputs:
+// err error the error returned by the call
+// Returns:
+// *proto.Rejection the rejection, or nil if the error
+// is not a rejection
+func FromStatus(err error) *proto.Rejection {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	for _, d := range st.Details() {
+		if r, ok := d.(*proto.Rejection); ok {
+			return r
+		}
+	}
+	return nil
+}
+
+// rjcts (rejections) counts the rejections of every
+// node in this process by reason code. It is published
+// through expvar (under /debug/vars if the program
+// serves http). A node serves its own counts with the
+// GetRejections RPC of the Admin service.
+var rjcts = expvar.NewMap("rejections")
+
+// Counts counts the rejections of a single node by
+// reason code. Every count is also added to the
+// process wide "rejections" expvar map.
+type Counts struct {
+	cts   map[Code]uint64
+	mutex sync.Mutex
+}
+
+// NewCounts creates an empty set of counts.
+func NewCounts() *Counts {
+	return &Counts{cts: make(map[Code]uint64)}
+}
+
+// Inc (Increment) counts a rejection.
+// Inputs:
+// err error the validation error. Errors without a
+// code are counted as "other".
+func (c *Counts) Inc(err error) {
+	cd := CodeOf(err)
+	if cd == "" {
+		cd = "other"
+	}
+	c.mutex.Lock()
+	c.cts[cd]++
+	c.mutex.Unlock()
+	rjcts.Add(string(cd), 1)
+}
+
+// Get returns how many rejections had a reason code.
+func (c *Counts) Get(cd Code) uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.cts[cd]
+}
+
+// Snapshot returns a copy of all counts.
+func (c *Counts) Snapshot() map[Code]uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cp := make(map[Code]uint64, len(c.cts))
+	for k, v := range c.cts {
+		cp[k] = v
+	}
+	return cp
+}
@@ -2,53 +2,147 @@ package pkg
 
 import (
 	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/enc"
 	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"fmt"
+	"time"
 )
 
 // ChkBlk (CheckBlock) validates a block based on multiple
-// conditions.
-// To be valid:
-// The block must be syntactically (ChkBlkSyn), semantically
-// (ChkBlkSem), and configurally (ChkBlkConf) valid.
-// Each transaction on the block must be syntactically (ChkTxSyn),
-// semantically (ChkTxSem), and configurally (ChkTxConf) valid.
-// Each transaction on the block must reference UTXO on the same
-// chain (main or forked chain) and not be a double spend on that
-// chain.
+// conditions. The reason a block is invalid is logged (see
+// BlkErr for the conditions).
 // Inputs:
 // b *block.Block the block to be checked for validity
 // Returns:
 // bool True if the block is valid. false
 // otherwise
 func (n *Node) ChkBlk(b *block.Block) bool {
-	if b == nil {
+	if err := n.BlkErr(b); err != nil {
+		if b != nil {
+			utils.Debug.Printf("%v rejected %v: %v", utils.FmtAddr(n.Addr), b.NameTag(), err)
+		}
 		return false
+	}
+	return true
+}
+
+// BlkErr (BlockError) validates a block and returns the
+// reason it is invalid.
+// To be valid:
+// The header must have a known version, the merkle root of
+// the block's transactions, and a previous block that is on
+// the chain. The timestamp can't be before the median time
+// past of the previous block, or more than MxTmDrft in the
+// future.
+// The first transaction must be the only coinbase, and it
+// can't pay out more than the subsidy (see blockchain.CalcSubsdy)
+// plus the fees of the other transactions.
+// Every transaction's hashes and scripts must be lowercase hex
+// (see hexErr).
+// Each transaction on the block must unlock UTXO on the same
+// chain (main or forked chain), be final at the block's height,
+// and not be a double spend on that chain (see
+// Blockchain.UTXOErr). Every transaction but the coinbase must
+// have valid data outputs (see dataErr).
+// The block must have the difficulty target that the chain
+// expects at its height (see Blockchain.ChkDifTrg).
+// Inputs:
+// b *block.Block the block to be checked for validity
+// Returns:
+// error why the block is invalid (a *valerr.Err), or nil
+// if it is valid
+func (n *Node) BlkErr(b *block.Block) error {
+	if b == nil {
+		return valerr.New(valerr.Malformed, "block is nil")
 	} else if len(b.Transactions) <= 0 {
-		return false
+		return valerr.New(valerr.Malformed, "block has no transactions")
 	}
 
+	if err := n.HdrErr(b); err != nil {
+		return err
+	}
+
+	h := uint32(n.Chain.IndexOf(b.Hdr.PrvBlkHsh) + 1)
+	var fees uint64
 	for i := range b.Transactions {
-		if i == 0 && (!b.Transactions[i].IsCoinbase() || len(b.Transactions[i].Outputs) <= 0 || b.Transactions[i].SumOutputs() <= 0) {
-			return false
+		t := b.Transactions[i]
+		if i == 0 && (!t.IsCoinbase() || len(t.Outputs) <= 0 || t.SumOutputs() <= 0) {
+			return valerr.New(valerr.BadCB, "first transaction is not a valid coinbase")
+		}
+		if i != 0 && t.IsCoinbase() {
+			return valerr.New(valerr.DupCB, "transaction %v is a coinbase but not first", i)
+		}
+		if err := hexErr(t); err != nil {
+			return valerr.New(valerr.Malformed, "transaction %v: %v", i, err)
 		}
-		if i != 0 && b.Transactions[i].IsCoinbase() {
-			return false
+		if i != 0 {
+			if _, err := dataErr(t); err != nil {
+				return valerr.New(valerr.BadData, "transaction %v: %v", i, err)
+			}
+			if t.SumInputs() < t.SumOutputs() {
+				return valerr.New(valerr.InsufFunds, "transaction %v pays out more than its inputs", i)
+			}
+			fees += uint64(t.SumInputs() - t.SumOutputs())
 		}
 	}
 
-	if !n.Chain.ChkChainsUTXO(b.Transactions[1:], b.Hdr.PrvBlkHsh) {
-		return false
+	mx := uint64(blockchain.CalcSubsdy(n.Conf.ChainConf, h)) + fees
+	if cb := uint64(b.Transactions[0].SumOutputs()); cb > mx {
+		return valerr.New(valerr.CBValue, "coinbase pays %v but subsidy plus fees is %v", cb, mx)
+	}
+
+	if err := n.Chain.UTXOErr(b.Transactions[1:], b.Hdr.PrvBlkHsh); err != nil {
+		return err
 	}
 
 	if b.Sz() > n.Conf.MxBlkSz {
-		return false
+		return valerr.New(valerr.Oversize, "block size %v is over the limit of %v", b.Sz(), n.Conf.MxBlkSz)
+	}
+
+	if !n.Chain.ChkDifTrg(b) {
+		return valerr.New(valerr.BadDifTrg, "difficulty target %v is not the expected target", b.Hdr.DiffTarg)
 	}
 
 	if !b.SatisfiesPOW(b.Hdr.DiffTarg) {
-		return false
+		return valerr.New(valerr.BadPOW, "block does not satisfy its difficulty target")
 	}
 
-	return true
+	return nil
+}
+
+// HdrErr (HeaderError) validates the header of a block
+// against the block's transactions and the chain.
+// Inputs:
+// b *block.Block the block whose header is checked
+// Returns:
+// error why the header is invalid (a *valerr.Err), or nil
+// if it is valid
+func (n *Node) HdrErr(b *block.Block) error {
+	prv := n.Chain.Get(b.Hdr.PrvBlkHsh)
+	if prv == nil {
+		return valerr.New(valerr.UnknownPrv, "previous block is unknown")
+	}
+	if b.Hdr.Ver > block.CurVer {
+		return valerr.New(valerr.BadVer, "unknown block version %v", b.Hdr.Ver)
+	}
+	if b.Hdr.Ver < prv.Hdr.Ver {
+		return valerr.New(valerr.BadVer, "block version %v is older than the previous block's %v", b.Hdr.Ver, prv.Hdr.Ver)
+	}
+	if b.Hdr.MrklRt != block.CalcMrklRt(b.Transactions) {
+		return valerr.New(valerr.BadMrkl, "merkle root does not match the transactions")
+	}
+	if mtp, _ := n.Chain.MedTmPst(b.Hdr.PrvBlkHsh); b.Hdr.Timestamp < mtp {
+		return valerr.New(valerr.TimeTooOld, "timestamp %v is before the median time past %v", b.Hdr.Timestamp, mtp)
+	}
+	if mx := time.Now().Add(n.Conf.MxTmDrft).Unix(); int64(b.Hdr.Timestamp) > mx {
+		return valerr.New(valerr.TimeTooNew, "timestamp %v is too far in the future", b.Hdr.Timestamp)
+	}
+	return nil
 }
 
 // ChkTx (CheckTransaction) validates a transaction.
@@ -58,28 +152,113 @@ func (n *Node) ChkBlk(b *block.Block) bool {
 // bool True if the transaction is syntactically valid. false
 // otherwise
 func (n *Node) ChkTx(t *tx.Transaction) bool {
+	return n.TxErr(t) == nil
+}
+
+// TxErr (TransactionError) validates a transaction and
+// returns the reason it is invalid.
+// Inputs:
+// t *tx.Transaction the transaction to be checked for validity
+// Returns:
+// error why the transaction is invalid (a *valerr.Err), or
+// nil if it is valid
+func (n *Node) TxErr(t *tx.Transaction) error {
+	if len(t.Inputs) == 0 || len(t.Outputs) == 0 {
+		return valerr.New(valerr.Malformed, "%v has no inputs or no outputs", t.NameTag())
+	}
+	if err := hexErr(t); err != nil {
+		return valerr.New(valerr.Malformed, "%v: %v", t.NameTag(), err)
+	}
+
+	spnt := make(map[string]bool)
 	for i := range t.Inputs {
-		if n.Chain.IsInvalidInput(t.Inputs[i]) {
-			return false
+		l := txo.MkTXOLoc(t.Inputs[i].TransactionHash, t.Inputs[i].OutputIndex)
+		if spnt[l] {
+			return valerr.New(valerr.DblSpnd, "input %v spends utxo %v twice", i, l)
 		}
+		spnt[l] = true
 
 		UTXO := n.Chain.GetUTXO(t.Inputs[i])
 
 		if UTXO == nil {
-			return false
+			return valerr.New(valerr.MissingInput, "input %v spends missing utxo", i)
+		}
+
+		// Fees (and so the coinbase) are calculated from
+		// the input amounts, so they have to be the real ones
+		if t.Inputs[i].Amount != UTXO.Amount {
+			return valerr.New(valerr.BadAmt, "input %v claims %v but its utxo is worth %v", i, t.Inputs[i].Amount, UTXO.Amount)
 		}
 
-		if !UTXO.IsUnlckd(t.Inputs[i].UnlockingScript) {
-			return false
+		if err := t.UnlckErr(i, UTXO); err != nil {
+			return valerr.New(valerr.BadSig, "input %v does not unlock its utxo: %v", i, err)
 		}
 	}
 
-	return t.Inputs != nil &&
-		t.Outputs != nil &&
-		len(t.Inputs) > 0 &&
-		len(t.Outputs) > 0 &&
-		t.SumOutputs() > 0 &&
-		t.SumInputs() > 0 &&
-		t.SumInputs() >= t.SumOutputs() &&
-		t.Sz() <= n.Conf.MxBlkSz
+	hasData, err := dataErr(t)
+	if err != nil {
+		return valerr.New(valerr.BadData, "%v: %v", t.NameTag(), err)
+	}
+	// A transaction that only carries data pays
+	// everything to the fee.
+	if (t.SumOutputs() <= 0 && !hasData) || t.SumInputs() <= 0 {
+		return valerr.New(valerr.Malformed, "%v moves no money", t.NameTag())
+	}
+	if t.SumInputs() < t.SumOutputs() {
+		return valerr.New(valerr.InsufFunds, "outputs %v are more than inputs %v", t.SumOutputs(), t.SumInputs())
+	}
+	if t.Sz() > n.Conf.MxBlkSz {
+		return valerr.New(valerr.Oversize, "size %v is over the limit of %v", t.Sz(), n.Conf.MxBlkSz)
+	}
+	return nil
+}
+
+// hexErr (hexError) checks that the hashes and scripts
+// of a transaction are lowercase hex (see enc.IsHex), so
+// that nobody can change its hash by writing them
+// another way.
+// Inputs:
+// t *tx.Transaction the transaction
+// Returns:
+// error which string isn't lowercase hex, or nil
+func hexErr(t *tx.Transaction) error {
+	for i, inp := range t.Inputs {
+		if !enc.IsHex(inp.TransactionHash) || !enc.IsHex(inp.UnlockingScript) {
+			return fmt.Errorf("input %v is not lowercase hex", i)
+		}
+	}
+	for i, o := range t.Outputs {
+		if !enc.IsHex(o.LockingScript) {
+			return fmt.Errorf("output %v is not lowercase hex", i)
+		}
+	}
+	return nil
+}
+
+// dataErr (dataError) checks the data outputs of a
+// transaction (see script.Data). A transaction can have
+// at most one, and it can't hold any money or carry more
+// than script.MxDataSz bytes.
+// Inputs:
+// t *tx.Transaction the transaction
+// Returns:
+// bool True if the transaction has a data output
+// error why the data outputs are invalid, or nil
+func dataErr(t *tx.Transaction) (bool, error) {
+	n := 0
+	for i, o := range t.Outputs {
+		if !script.IsData(o.LockingScript) {
+			continue
+		}
+		if n++; n > 1 {
+			return true, fmt.Errorf("output %v is a second data output", i)
+		}
+		if o.Amount != 0 {
+			return true, fmt.Errorf("data output %v holds %v", i, o.Amount)
+		}
+		if _, ok := script.DataOf(o.LockingScript); !ok {
+			return true, fmt.Errorf("data output %v is malformed or over %v bytes", i, script.MxDataSz)
+		}
+	}
+	return n > 0, nil
 }
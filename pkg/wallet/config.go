@@ -1,5 +1,7 @@
 package wallet
 
+import "BrunoCoin/pkg/block/tx"
+
 // Config represents the configuration (settings)
 // for the wallet.
 // HasWt (HasWallet) defines whether the wallet
@@ -12,8 +14,9 @@ package wallet
 // of blocks that need to be on top of the block
 // that contains a transaction for that transaction
 // to be considered valid by the wallet.
-// TxVer (TransactionVersion) is the same as the
-// software version of the node.
+// TxVer (TransactionVersion) is the version of the
+// transactions the wallet makes. From tx.EncVer on,
+// they are hashed with the canonical encoding.
 // DefLckTm (DefaultLockTime) is the default lock
 // time (when the utxo can be spent)
 type Config struct {
@@ -31,7 +34,7 @@ func DefaultConfig() *Config {
 		HasWt:        true,
 		TxRplyThresh: 3,
 		SafeBlkAmt:   5,
-		TxVer:        0,
+		TxVer:        tx.EncVer,
 		DefLckTm:     0,
 	}
 }
@@ -43,7 +46,7 @@ func NilConfig() *Config {
 		HasWt:        false,
 		TxRplyThresh: 0,
 		SafeBlkAmt:   0,
-		TxVer:        0,
+		TxVer:        tx.EncVer,
 		DefLckTm:     0,
 	}
 }
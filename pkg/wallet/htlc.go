@@ -0,0 +1,177 @@
+package wallet
+
+import (
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// MkHTLC (MakeHashTimeLockedContract) pays amt from the
+// wallet into a hash time locked contract, and sends the
+// transaction to the node to be broadcast. The receiver
+// can claim it with the preimage of hsh, and the wallet
+// can take it back once lck has passed.
+// Inputs:
+// hsh []byte the sha256 of the preimage
+// rcv []byte the public key of the receiver
+// lck uint32 a block height, or a Unix timestamp if it
+// is at least script.LckTmThrsh
+// amt uint32 the amount to lock in the contract
+// fee uint32 the fee
+// Returns:
+// *tx.Transaction the transaction paying the contract
+// []byte the contract (see script.HTLC), which the
+// receiver needs to claim it
+// error if there isn't enough utxo
+func (w *Wallet) MkHTLC(hsh, rcv []byte, lck, amt, fee uint32) (*tx.Transaction, []byte, error) {
+	if len(hsh) != sha256.Size {
+		return nil, nil, errors.New("hash is not a sha256")
+	}
+	rdm := script.HTLC(script.HTLCTrms{Hsh: hsh, Rcv: rcv, Snd: w.Id.GetPublicKeyBytes(), Lck: lck})
+	t, err := w.PayScr(script.P2SH(rdm), amt, fee)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, rdm, nil
+}
+
+// ClmHTLC (ClaimHashTimeLockedContract) spends the utxo
+// locked by a hash time locked contract that pays the
+// wallet, using the preimage of its hash. The money goes
+// to the wallet, less the fee.
+// Inputs:
+// rdm []byte the contract (see script.HTLC)
+// pre []byte the preimage
+// fee uint32 the fee
+// Returns:
+// *tx.Transaction the claim
+// error if the contract doesn't pay the wallet, the
+// preimage is wrong or there is no utxo to claim
+func (w *Wallet) ClmHTLC(rdm, pre []byte, fee uint32) (*tx.Transaction, error) {
+	trms, ok := script.PrsHTLC(rdm)
+	if !ok {
+		return nil, errors.New("not a hash time locked contract")
+	}
+	if !bytes.Equal(trms.Rcv, w.Id.GetPublicKeyBytes()) {
+		return nil, errors.New("contract doesn't pay the wallet")
+	}
+	if h := sha256.Sum256(pre); !bytes.Equal(h[:], trms.Hsh) {
+		return nil, errors.New("preimage doesn't match the contract's hash")
+	}
+	return w.spndHTLC(rdm, fee, w.Conf.DefLckTm, func(sig []byte) string {
+		return script.HTLCClm(sig, pre, rdm)
+	})
+}
+
+// RfndHTLC (RefundHashTimeLockedContract) takes back the
+// utxo locked by a hash time locked contract the wallet
+// made. The transaction has the contract's lock time,
+// so it can't be on a block until the lock time has
+// passed (see tx.Transaction.IsFinal), and the contract
+// can't be spent by a refund with an earlier one (see
+// script.OpChkLckTmVfy).
+// Inputs:
+// rdm []byte the contract (see script.HTLC)
+// fee uint32 the fee
+// Returns:
+// *tx.Transaction the refund
+// error if the wallet isn't the sender of the contract,
+// or there is no utxo to refund
+func (w *Wallet) RfndHTLC(rdm []byte, fee uint32) (*tx.Transaction, error) {
+	trms, ok := script.PrsHTLC(rdm)
+	if !ok {
+		return nil, errors.New("not a hash time locked contract")
+	}
+	if !bytes.Equal(trms.Snd, w.Id.GetPublicKeyBytes()) {
+		return nil, errors.New("wallet isn't the contract's sender")
+	}
+	return w.spndHTLC(rdm, fee, trms.Lck, func(sig []byte) string {
+		return script.HTLCRfnd(sig, rdm)
+	})
+}
+
+// spndHTLC (spendHashTimeLockedContract) spends all of
+// the utxo locked by the pay to script hash of a
+// contract to the wallet, and sends the transaction to
+// the node to be broadcast.
+// Inputs:
+// rdm []byte the contract
+// fee uint32 the fee
+// lckTm uint32 the lock time of the transaction
+// unlck func(sig []byte) string makes the unlocking
+// script of an input from the wallet's signature
+func (w *Wallet) spndHTLC(rdm []byte, fee, lckTm uint32, unlck func(sig []byte) string) (*tx.Transaction, error) {
+	UTXOinfo, amt := w.Chain.UTXOForScr(script.P2SH(rdm))
+	if len(UTXOinfo) == 0 {
+		return nil, errors.New("no utxo locked by the contract")
+	}
+	if !w.Chain.RsrvUTXO(UTXOinfo) {
+		return nil, errors.New("utxo locked by the contract is already being spent")
+	}
+
+	var protoTxI []*proto.TransactionInput
+	for _, u := range UTXOinfo {
+		protoTxI = append(protoTxI, proto.NewTxInpt(u.TxHsh, u.OutIdx, "", u.Amt))
+	}
+	protoTxO := []*proto.TransactionOutput{proto.NewTxOutpt(amt-fee, script.P2PKH(w.Id.GetPublicKeyBytes()))}
+	t := tx.Deserialize(proto.NewTx(w.Conf.TxVer, protoTxI, protoTxO, lckTm))
+
+	err := func() error {
+		if amt <= fee {
+			return fmt.Errorf("contract of %v doesn't cover the fee of %v", amt, fee)
+		}
+		for i, u := range UTXOinfo {
+			sig, err := t.MkSig(i, u.UTXO, w.Id, tx.SigHshAll)
+			if err != nil {
+				return err
+			}
+			sigB, _ := hex.DecodeString(sig)
+			t.Inputs[i].UnlockingScript = unlck(sigB)
+			if err := t.UnlckErr(i, u.UTXO); err != nil {
+				return fmt.Errorf("input %v: %v", i, err)
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		for _, i := range t.Inputs {
+			w.Chain.RlsUTXO(i)
+		}
+		return nil, err
+	}
+
+	w.LmnlTxs.Add(t)
+	w.SendTx <- t
+
+	utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> contract spend " + t.NameTag())
+	return t, nil
+}
+
+// FndHTLCPre (FindHashTimeLockedContractPreimage) looks
+// on the main chain for a claim that revealed the
+// preimage of a hash. In a swap, the side that didn't
+// pick the preimage finds it this way once the other
+// side claims its contract.
+// Inputs:
+// hsh []byte the sha256 of the preimage
+// Returns:
+// []byte the preimage
+// bool False if no claim on the main chain has it
+func (w *Wallet) FndHTLCPre(hsh []byte) ([]byte, bool) {
+	for _, b := range w.Chain.List() {
+		for _, t := range b.Transactions {
+			for _, i := range t.Inputs {
+				if pre, ok := script.HTLCPre(i.UnlockingScript, hsh); ok {
+					return pre, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,285 @@
+package wallet
+
+import (
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"encoding/hex"
+	"sync"
+)
+
+// UTXOSrc (UTXOSource) is where the wallet finds the
+// utxo it spends. On a full node it is the blockchain.
+// A light node has no utxo set, so its wallet keeps
+// its own (see LghtUTXO).
+type UTXOSrc interface {
+	GetUTXOForAmt(amt uint32, pubKey string) ([]*blockchain.UTXOInfo, uint32, bool)
+	GetUTXO(txi *txi.TransactionInput) *txo.TransactionOutput
+	RlsUTXO(txi *txi.TransactionInput)
+}
+
+// LghtUTXO (LightUTXO) is the utxo of a wallet on a
+// light node. It only holds the utxo that pays the
+// wallet, and learns about it from transactions on the
+// main chain whose merkle proofs the node checked.
+// scrs (scripts) are the locking scripts that pay the
+// wallet (see script.PKScrs)
+// utxo maps the locator of every utxo paying the wallet
+// to it (see txo.MkTXOLoc)
+// seen holds the hashes of the transactions added
+// txs (transactions) holds the transactions added with
+// the heights of their blocks, in the order they were
+// added, so they can be replayed after a reorganization
+// hshs (hashes) maps each height up to Hght to the hash
+// of the block that was on the main chain there when its
+// transactions were added
+// Hght (Height) is the height of the last block whose
+// transactions were added
+type LghtUTXO struct {
+	scrs  map[string]bool
+	utxo  map[string]*txo.TransactionOutput
+	seen  map[string]bool
+	txs   []lghtTx
+	hshs  map[uint32]string
+	Hght  uint32
+	mutex sync.Mutex
+}
+
+// lghtTx (lightTransaction) is a transaction a light
+// wallet added, and the height of its block.
+type lghtTx struct {
+	t    *tx.Transaction
+	hght uint32
+}
+
+// NewLghtUTXO (NewLightUTXO) creates the utxo of a
+// light node's wallet.
+// Inputs:
+// pk string the public key of the wallet, as a hex string
+func NewLghtUTXO(pk string) *LghtUTXO {
+	return &LghtUTXO{
+		scrs: script.PKScrs(pk),
+		utxo: make(map[string]*txo.TransactionOutput),
+		seen: make(map[string]bool),
+		hshs: make(map[uint32]string),
+	}
+}
+
+// Add adds a transaction on the main chain. The utxo
+// it spends is removed, and its outputs that pay the
+// wallet are added.
+// Inputs:
+// t *tx.Transaction the transaction
+// hght uint32 the height of its block
+// Returns:
+// bool False if the transaction was already added
+func (l *LghtUTXO) Add(t *tx.Transaction, hght uint32) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.seen[t.Hash()] {
+		return false
+	}
+	l.txs = append(l.txs, lghtTx{t, hght})
+	l.apply(t)
+	return true
+}
+
+// apply adds a transaction to the utxo. The caller must
+// hold the mutex.
+func (l *LghtUTXO) apply(t *tx.Transaction) {
+	h := t.Hash()
+	l.seen[h] = true
+	for _, i := range t.Inputs {
+		delete(l.utxo, txo.MkTXOLoc(i.TransactionHash, i.OutputIndex))
+	}
+	for j, o := range t.Outputs {
+		if l.scrs[o.LockingScript] {
+			l.utxo[txo.MkTXOLoc(h, uint32(j))] = &txo.TransactionOutput{Amount: o.Amount, LockingScript: o.LockingScript}
+		}
+	}
+}
+
+// Adv (Advance) records the blocks of the main chain
+// whose transactions were added, up to a new Hght.
+// Inputs:
+// from uint32 the height of the first block
+// hshs []string the hashes of the blocks, in chain order
+func (l *LghtUTXO) Adv(from uint32, hshs []string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for i, hsh := range hshs {
+		l.hshs[from+uint32(i)] = hsh
+	}
+	if len(hshs) > 0 && from+uint32(len(hshs))-1 > l.Hght {
+		l.Hght = from + uint32(len(hshs)) - 1
+	}
+}
+
+// Frk (Fork) returns the height of the last block that
+// is still on the main chain, of those whose
+// transactions were added.
+// Inputs:
+// main func(uint32) string returns the hash of the block
+// on the main chain at a height, or "" if there is none
+func (l *LghtUTXO) Frk(main func(uint32) string) uint32 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	h := l.Hght
+	for ; h > 0; h-- {
+		if hsh, found := l.hshs[h]; !found || main(h) == hsh {
+			break
+		}
+	}
+	return h
+}
+
+// Rllbck (Rollback) takes off the transactions of the
+// blocks above a height, after a reorganization took
+// them off of the main chain. The utxo is rebuilt from
+// the transactions that are left. The utxo the removed
+// transactions spent is liminal again, since they are
+// likely to be mined again.
+// Inputs:
+// hght uint32 the height of the last block to keep
+// Returns:
+// []*tx.Transaction the transactions taken off
+func (l *LghtUTXO) Rllbck(hght uint32) []*tx.Transaction {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var kept []lghtTx
+	var off []*tx.Transaction
+	for _, lt := range l.txs {
+		if lt.hght <= hght {
+			kept = append(kept, lt)
+		} else {
+			off = append(off, lt.t)
+		}
+	}
+	old := l.utxo
+	l.utxo = make(map[string]*txo.TransactionOutput)
+	l.seen = make(map[string]bool)
+	l.txs = kept
+	for _, lt := range kept {
+		l.apply(lt.t)
+	}
+	for loc, o := range l.utxo {
+		if p, found := old[loc]; found {
+			o.Liminal = p.Liminal
+		}
+	}
+	for _, t := range off {
+		for _, i := range t.Inputs {
+			if o, found := l.utxo[txo.MkTXOLoc(i.TransactionHash, i.OutputIndex)]; found {
+				o.Liminal = true
+			}
+		}
+	}
+	for h := range l.hshs {
+		if h > hght {
+			delete(l.hshs, h)
+		}
+	}
+	if hght < l.Hght {
+		l.Hght = hght
+	}
+	return off
+}
+
+// Locs (Locators) returns the locators of the utxo.
+func (l *LghtUTXO) Locs() []string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	locs := make([]string, 0, len(l.utxo))
+	for loc := range l.utxo {
+		locs = append(locs, loc)
+	}
+	return locs
+}
+
+// Bal (Balance) returns the sum of the utxo.
+func (l *LghtUTXO) Bal() uint32 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var bal uint32
+	for _, o := range l.utxo {
+		bal += o.Amount
+	}
+	return bal
+}
+
+// GetUTXOForAmt (GetUTXOForAmount) is
+// Blockchain.GetUTXOForAmt for the wallet's utxo.
+func (l *LghtUTXO) GetUTXOForAmt(amt uint32, pubKey string) ([]*blockchain.UTXOInfo, uint32, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var infos []*blockchain.UTXOInfo
+	if amt == 0 {
+		return infos, 0, true
+	}
+	scrs := script.PKScrs(pubKey)
+	for loc, o := range l.utxo {
+		if o.Liminal || !scrs[o.LockingScript] {
+			continue
+		}
+		h, i := txo.PrsTXOLoc(loc)
+		o.Liminal = true
+		infos = append(infos, &blockchain.UTXOInfo{TxHsh: h, OutIdx: i, UTXO: o, Amt: o.Amount})
+		if o.Amount >= amt {
+			return infos, o.Amount - amt, true
+		}
+		amt -= o.Amount
+	}
+	for _, u := range infos {
+		u.UTXO.Liminal = false
+	}
+	return nil, 0, false
+}
+
+// GetUTXO returns the utxo an input spends, or nil
+// if it isn't the wallet's.
+func (l *LghtUTXO) GetUTXO(txi *txi.TransactionInput) *txo.TransactionOutput {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.utxo[txo.MkTXOLoc(txi.TransactionHash, txi.OutputIndex)]
+}
+
+// RlsUTXO (ReleaseUTXO) marks the utxo an input spends
+// as no longer liminal.
+func (l *LghtUTXO) RlsUTXO(txi *txi.TransactionInput) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if o, found := l.utxo[txo.MkTXOLoc(txi.TransactionHash, txi.OutputIndex)]; found {
+		o.Liminal = false
+	}
+}
+
+// Fltr (Filter) returns what a full node needs to find
+// the transactions a light node's wallet cares about
+// (see proto.GetMerkleProofsRequest).
+// Returns:
+// []string the locking scripts that pay the wallet
+// []string the locators of the wallet's utxo
+func (w *Wallet) Fltr() ([]string, []string) {
+	pk := w.Id.GetPublicKeyBytes()
+	lcks := []string{hex.EncodeToString(pk), script.P2PK(pk), script.P2PKH(pk)}
+	return lcks, w.Lght.Locs()
+}
+
+// HndlCnfTx (HandleConfirmedTransaction) is called on a
+// light node with a transaction that pays or spends the
+// wallet's utxo, once the node has checked that it is on
+// the main chain. The wallet's utxo is updated, and the
+// wallet stops waiting on it if it made it.
+// Inputs:
+// t *tx.Transaction the transaction
+// hght uint32 the height of its block
+func (w *Wallet) HndlCnfTx(t *tx.Transaction, hght uint32) {
+	if w.Lght == nil || !w.Lght.Add(t, hght) {
+		return
+	}
+	w.LmnlTxs.Rmv(t)
+	utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> confirmed " + t.NameTag())
+}
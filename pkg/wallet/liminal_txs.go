@@ -74,3 +74,47 @@ func (l *LiminalTxs) Add(t *tx.Transaction) {
 
 	l.mutex.Unlock()
 }
+
+// Has returns whether a transaction is one of
+// the liminal transactions.
+// Inputs:
+// t *tx.Transaction the transaction to look for
+func (l *LiminalTxs) Has(t *tx.Transaction) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.TxQ.Has(t)
+}
+
+// Rmv (Remove) removes a transaction from the
+// liminal transactions.
+// Inputs:
+// t *tx.Transaction the transaction to remove
+// Returns:
+// bool True if the transaction was liminal
+func (l *LiminalTxs) Rmv(t *tx.Transaction) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return len(l.TxQ.Rmv([]*tx.Transaction{t})) > 0
+}
+
+// Spends returns whether any of the liminal
+// transactions spends an output.
+// Inputs:
+// txHsh string the hash of the transaction that
+// made the output
+// idx uint32 the index of the output
+func (l *LiminalTxs) Spends(txHsh string, idx uint32) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, v := range *l.TxQ {
+		for _, i := range v.T.Inputs {
+			if i.TransactionHash == txHsh && i.OutputIndex == idx {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package wallet
+
+import (
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MsSpnd (MultisigSpend) is a transaction spending
+// multisig utxo that is collecting the signatures
+// of the co-signers. Once every input has M
+// signatures, it can be finalized and broadcast.
+// Tx is the transaction, without unlocking scripts
+// Rdm (Redeem) is the multisig script (see script.MltSig)
+// P2SH is whether the utxo is locked by the pay to
+// script hash of Rdm, instead of Rdm itself
+// UTXO holds the output spent by each input
+// M is how many signatures each input needs
+// PKs (PublicKeys) are the public keys in Rdm
+// sigs (signatures) maps, for each input, the index of a
+// public key to its signature
+type MsSpnd struct {
+	Tx   *tx.Transaction
+	Rdm  []byte
+	P2SH bool
+	UTXO []*txo.TransactionOutput
+	M    int
+	PKs  [][]byte
+
+	sigs  []map[int][]byte
+	mutex sync.Mutex
+}
+
+// MsLck (MultisigLock) returns the locking script of
+// outputs locked by a multisig script.
+// Inputs:
+// rdm []byte the multisig script
+// p2sh bool whether to lock to the pay to script hash
+// of rdm instead of rdm itself
+// Returns:
+// string the locking script as a hex string
+func MsLck(rdm []byte, p2sh bool) string {
+	if p2sh {
+		return script.P2SH(rdm)
+	}
+	return hex.EncodeToString(rdm)
+}
+
+// MkMsSpnd (MakeMultisigSpend) makes an unsigned
+// transaction spending utxo locked by a multisig
+// script. Change goes back to the same script. The
+// utxo is liminal until the spend is finalized or
+// aborted.
+// Inputs:
+// rdm []byte the multisig script
+// p2sh bool whether the utxo is locked by the pay to
+// script hash of rdm
+// txR *TxReq who to pay, how much, and the fee
+// Returns:
+// *MsSpnd the spend, which co-signers can sign
+// error if rdm is not a multisig script or there isn't
+// enough utxo
+func (w *Wallet) MkMsSpnd(rdm []byte, p2sh bool, txR *TxReq) (*MsSpnd, error) {
+	if _, _, ok := script.PrsMltSig(rdm); !ok {
+		return nil, errors.New("not a multisig script")
+	}
+	if txR.Amt == 0 {
+		return nil, errors.New("amount is 0")
+	}
+	lck := MsLck(rdm, p2sh)
+	UTXOinfo, change, enough := w.Chain.GetUTXOForScr(txR.Amt+txR.Fee, lck)
+	if !enough {
+		return nil, errors.New("not enough utxo locked by the script")
+	}
+
+	var protoTxI []*proto.TransactionInput
+	for i := range UTXOinfo {
+		protoTxI = append(protoTxI, proto.NewTxInpt(UTXOinfo[i].TxHsh, UTXOinfo[i].OutIdx, "", UTXOinfo[i].Amt))
+	}
+	protoTxO := []*proto.TransactionOutput{proto.NewTxOutpt(txR.Amt, script.P2PKH(txR.PubK))}
+	if change > 0 {
+		protoTxO = append(protoTxO, proto.NewTxOutpt(change, lck))
+	}
+	t := tx.Deserialize(proto.NewTx(w.Conf.TxVer, protoTxI, protoTxO, w.Conf.DefLckTm))
+
+	s, err := w.LdMsSpnd(t, rdm, p2sh)
+	if err != nil {
+		for _, i := range t.Inputs {
+			w.Chain.RlsUTXO(i)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// LdMsSpnd (LoadMultisigSpend) loads a multisig spend
+// made by another wallet, so that this wallet can sign
+// it as a co-signer. The utxo it spends is looked up on
+// the chain.
+// Inputs:
+// t *tx.Transaction the transaction of the spend
+// rdm []byte the multisig script
+// p2sh bool whether the utxo is locked by the pay to
+// script hash of rdm
+// Returns:
+// *MsSpnd the spend
+// error if an input doesn't spend utxo locked by rdm
+func (w *Wallet) LdMsSpnd(t *tx.Transaction, rdm []byte, p2sh bool) (*MsSpnd, error) {
+	m, pks, ok := script.PrsMltSig(rdm)
+	if !ok {
+		return nil, errors.New("not a multisig script")
+	}
+	lck := MsLck(rdm, p2sh)
+	s := &MsSpnd{Tx: t, Rdm: rdm, P2SH: p2sh, M: m, PKs: pks}
+	for i, inp := range t.Inputs {
+		u := w.Chain.GetUTXO(inp)
+		if u == nil || u.LockingScript != lck {
+			return nil, fmt.Errorf("input %v doesn't spend utxo locked by the script", i)
+		}
+		s.UTXO = append(s.UTXO, u)
+		s.sigs = append(s.sigs, make(map[int][]byte))
+	}
+	return s, nil
+}
+
+// AddSig (AddSignature) adds a co-signer's signature
+// for an input. It is matched with the public key it
+// is valid for.
+// Inputs:
+// i int the index of the input
+// sig string the signature (with its sighash flag) as
+// a hex string
+// Returns:
+// error if the signature isn't valid for any of the
+// public keys
+func (s *MsSpnd) AddSig(i int, sig string) error {
+	if i < 0 || i >= len(s.Tx.Inputs) {
+		return fmt.Errorf("input %v is out of range", i)
+	}
+	sigB, err := hex.DecodeString(sig)
+	if err != nil {
+		return err
+	}
+	sp := &script.Spnd{
+		SigHsh: func(ht byte) (string, error) {
+			return s.Tx.SigHsh(i, s.UTXO[i], ht)
+		},
+		LckTm: s.Tx.LockTime,
+	}
+	for k, pk := range s.PKs {
+		if script.Run(script.New().Push(sigB).Hex(), script.P2PK(pk), sp) == nil {
+			s.mutex.Lock()
+			s.sigs[i][k] = sigB
+			s.mutex.Unlock()
+			return nil
+		}
+	}
+	return fmt.Errorf("signature for input %v isn't from a co-signer", i)
+}
+
+// Ready returns whether every input has enough
+// signatures.
+func (s *MsSpnd) Ready() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, sigs := range s.sigs {
+		if len(sigs) < s.M {
+			return false
+		}
+	}
+	return true
+}
+
+// SignMs (SignMultisig) signs every input of a
+// multisig spend with the wallet's key, if it is
+// one of the co-signers.
+// Inputs:
+// s *MsSpnd the spend
+// Returns:
+// []string the signatures for each input, as hex
+// strings, which can be sent to the wallet that
+// finalizes the spend (see MsSpnd.AddSig)
+// error if the wallet's key is not in the script
+func (w *Wallet) SignMs(s *MsSpnd) ([]string, error) {
+	pk := w.Id.GetPublicKeyBytes()
+	found := false
+	for _, k := range s.PKs {
+		found = found || bytes.Equal(k, pk)
+	}
+	if !found {
+		return nil, errors.New("wallet is not a co-signer")
+	}
+	sigs := make([]string, len(s.Tx.Inputs))
+	for i := range s.Tx.Inputs {
+		sig, err := s.Tx.MkSig(i, s.UTXO[i], w.Id, tx.SigHshAll)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.AddSig(i, sig); err != nil {
+			return nil, err
+		}
+		sigs[i] = sig
+	}
+	return sigs, nil
+}
+
+// FnlzMs (FinalizeMultisig) builds the unlocking
+// scripts of a multisig spend once it has enough
+// signatures, and sends it to the node to be broadcast.
+// Inputs:
+// s *MsSpnd the spend
+// Returns:
+// *tx.Transaction the finished transaction
+// error if an input doesn't have enough signatures
+func (w *Wallet) FnlzMs(s *MsSpnd) (*tx.Transaction, error) {
+	if !s.Ready() {
+		return nil, errors.New("not enough signatures")
+	}
+	var rdm []byte
+	if s.P2SH {
+		rdm = s.Rdm
+	}
+	s.mutex.Lock()
+	for i := range s.Tx.Inputs {
+		ks := make([]int, 0, len(s.sigs[i]))
+		for k := range s.sigs[i] {
+			ks = append(ks, k)
+		}
+		sort.Ints(ks)
+		sigs := make([][]byte, s.M)
+		for j := range sigs {
+			sigs[j] = s.sigs[i][ks[j]]
+		}
+		s.Tx.Inputs[i].UnlockingScript = script.MltSigUnlck(sigs, rdm)
+	}
+	s.mutex.Unlock()
+	for i := range s.Tx.Inputs {
+		if err := s.Tx.UnlckErr(i, s.UTXO[i]); err != nil {
+			return nil, fmt.Errorf("input %v: %v", i, err)
+		}
+	}
+
+	w.LmnlTxs.Add(s.Tx)
+	w.SendTx <- s.Tx
+
+	utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> multisig transaction " + s.Tx.NameTag())
+	return s.Tx, nil
+}
+
+// AbrtMs (AbortMultisig) gives up on a multisig spend
+// made by the wallet, so its utxo can be spent again.
+// Inputs:
+// s *MsSpnd the spend
+func (w *Wallet) AbrtMs(s *MsSpnd) {
+	for _, i := range s.Tx.Inputs {
+		w.Chain.RlsUTXO(i)
+	}
+}
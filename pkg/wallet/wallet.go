@@ -6,8 +6,10 @@ import (
 	"BrunoCoin/pkg/blockchain"
 	"BrunoCoin/pkg/id"
 	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
 	"BrunoCoin/pkg/utils"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync"
 )
@@ -19,10 +21,14 @@ import (
 // public key of the person they want to pay.
 // Amt (Amount) represents the amount of money
 // they want to pay the person.
+// LckTm (LockTime) is the lock time of the
+// transaction (see tx.Transaction.IsFinal). If it
+// is 0, the wallet's DefLckTm is used.
 type TxReq struct {
-	PubK []byte
-	Amt  uint32
-	Fee  uint32
+	PubK  []byte
+	Amt   uint32
+	Fee   uint32
+	LckTm uint32
 }
 
 // Wallet provides the functionality to make
@@ -36,6 +42,12 @@ type TxReq struct {
 // Chain represents the blockchain, as the
 // wallet needs to be able to query the chain
 // for enough UTXO to fulfill a transaction request.
+// UTXO is where the wallet gets the utxo it pays
+// with: the blockchain, or Lght on a light node.
+// Lght (Light) is the wallet's own utxo on a light
+// node, whose chain only has headers, otherwise nil.
+// Contracts and multisig need the blockchain's utxo,
+// so they don't work on a light node.
 // SendTx (SendTransaction) is a channel for sending
 // fulfilled transaction requests (now in the form of
 // a transaction) to the node, in order to be sent
@@ -50,6 +62,8 @@ type Wallet struct {
 	Conf    *Config
 	Id      id.ID
 	Chain   *blockchain.Blockchain
+	UTXO    UTXOSrc
+	Lght    *LghtUTXO
 	SendTx  chan *tx.Transaction
 	LmnlTxs *LiminalTxs
 	Addr    string
@@ -73,19 +87,30 @@ func (w *Wallet) SetAddr(a string) {
 // chain *blockchain.Blockchain the
 // blockchain that the wallet needs a
 // references to find UTXO for making transactions.
+// If it only has headers, the wallet keeps its own
+// utxo, starting with what the genesis block pays it.
 // Returns:
 // *Wallet the new wallet object
 func New(c *Config, id id.ID, chain *blockchain.Blockchain) *Wallet {
 	if !c.HasWt {
 		return nil
 	}
-	return &Wallet{
+	w := &Wallet{
 		Conf:    c,
 		Id:      id,
 		Chain:   chain,
+		UTXO:    chain,
 		SendTx:  make(chan *tx.Transaction),
 		LmnlTxs: NewLmnlTxs(c),
 	}
+	if chain.HdrsOnly() {
+		w.Lght = NewLghtUTXO(hex.EncodeToString(id.GetPublicKeyBytes()))
+		for _, t := range chain.Slice(0, 1)[0].Transactions {
+			w.Lght.Add(t, 0)
+		}
+		w.UTXO = w.Lght
+	}
+	return w
 }
 
 // HndlBlk (HandleBlock) is called after a new
@@ -114,16 +139,131 @@ func (w *Wallet) HndlBlk(b *block.Block) {
 			continue
 		}
 
-		abvThreshold[i].LockTime += 1
-		w.SendTx <- abvThreshold[i]
-		w.LmnlTxs.Add(abvThreshold[i])
+		t := w.rnw(abvThreshold[i])
+		w.SendTx <- t
+		w.LmnlTxs.Add(t)
 
-		utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> transaction " + abvThreshold[i].NameTag())
+		utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> transaction " + t.NameTag())
 	}
 
 	return
 }
 
+// rnw (renew) gives a transaction that is sent out
+// again a new hash, so nodes that have seen it before
+// take it again. A lock time that is a height below the
+// height of the main chain is moved up to it, which
+// keeps the transaction final for the next block, and
+// the inputs are signed again. Transactions with other
+// lock times, or with inputs the wallet can't sign on
+// its own, are sent out unchanged.
+// Inputs:
+// t *tx.Transaction the transaction being sent out again
+// Returns:
+// *tx.Transaction the transaction to send
+func (w *Wallet) rnw(t *tx.Transaction) *tx.Transaction {
+	h := uint32(w.Chain.Length() - 1)
+	if t.LockTime >= h {
+		return t
+	}
+	nt := tx.Deserialize(t.Serialize())
+	nt.LockTime = h
+	pk := w.Id.GetPublicKeyBytes()
+	for i, inp := range nt.Inputs {
+		u := w.UTXO.GetUTXO(inp)
+		if u == nil {
+			return t
+		}
+		sig, err := nt.MkSig(i, u, w.Id, tx.SigHshAll)
+		if err != nil {
+			return t
+		}
+		if nt.Inputs[i].UnlockingScript, err = script.Unlck(u.LockingScript, sig, pk); err != nil {
+			return t
+		}
+	}
+	return nt
+}
+
+// HndlUnconf (HandleUnconfirmed) is called when a
+// reorganization took transactions off of the main
+// chain. Transactions that spend the wallet's money
+// are not confirmed anymore, so they are made liminal
+// again, which means they are sent out again if they
+// don't make it back onto the main chain.
+// Inputs:
+// txs []*tx.Transaction the transactions that are no
+// longer on the main chain
+func (w *Wallet) HndlUnconf(txs []*tx.Transaction) {
+	scrs := script.PKScrs(hex.EncodeToString(w.Id.GetPublicKeyBytes()))
+	for _, t := range txs {
+		if t == nil || w.LmnlTxs.Has(t) {
+			continue
+		}
+		for _, i := range t.Inputs {
+			if u := w.UTXO.GetUTXO(i); u != nil && scrs[u.LockingScript] {
+				w.LmnlTxs.Add(t)
+				utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> unconfirmed " + t.NameTag())
+				break
+			}
+		}
+	}
+}
+
+// HndlCnfl (HandleConflict) is called when one of the
+// wallet's transactions was rejected or evicted because
+// another transaction spends the same output. That
+// transaction can never be mined, so the wallet stops
+// waiting on it, and the utxo it spent that is still
+// unspent (and not spent by another liminal transaction)
+// can be used again. If it lost to the same payment, such
+// as the transaction it was renewed from (see rnw), which
+// is still in the pool, the payment isn't lost: the wallet
+// waits on the transaction that won instead, and its utxo
+// stays reserved.
+// Inputs:
+// t *tx.Transaction the transaction that lost
+// by *tx.Transaction the transaction it lost to
+func (w *Wallet) HndlCnfl(t *tx.Transaction, by *tx.Transaction) {
+	if t == nil || !w.LmnlTxs.Rmv(t) {
+		return
+	}
+	if by != nil && samePay(t, by) {
+		w.LmnlTxs.Add(by)
+		utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> waiting on " + by.NameTag() + " for " + t.NameTag())
+		return
+	}
+	for _, i := range t.Inputs {
+		if !w.LmnlTxs.Spends(i.TransactionHash, i.OutputIndex) {
+			w.UTXO.RlsUTXO(i)
+		}
+	}
+	utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> dropped " + t.NameTag())
+}
+
+// samePay (samePayment) returns whether two transactions
+// spend the same outputs and pay the same amounts to the
+// same locking scripts, so that they only differ in their
+// lock time and signatures.
+func samePay(a *tx.Transaction, b *tx.Transaction) bool {
+	if len(a.Inputs) != len(b.Inputs) || len(a.Outputs) != len(b.Outputs) {
+		return false
+	}
+	for i := range a.Inputs {
+		if a.Inputs[i].TransactionHash != b.Inputs[i].TransactionHash ||
+			a.Inputs[i].OutputIndex != b.Inputs[i].OutputIndex {
+			return false
+		}
+	}
+	for i := range a.Outputs {
+		if a.Outputs[i].Amount != b.Outputs[i].Amount ||
+			a.Outputs[i].LockingScript != b.Outputs[i].LockingScript {
+			return false
+		}
+	}
+	return true
+}
+
 // HndlTxReq (HandleTransactionRequest) attempts to
 // create a transaction from the request, as well as
 // sending this transaction to the node to be forwarded
@@ -141,36 +281,137 @@ func (w *Wallet) HndlTxReq(txR *TxReq) {
 		return
 	}
 
+	lckTm := w.Conf.DefLckTm
+	if txR.LckTm != 0 {
+		lckTm = txR.LckTm
+	}
+	Tx, err := w.mkPay(script.P2PKH(txR.PubK), txR.Amt, txR.Fee, lckTm)
+	if err != nil {
+		utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> no transaction: " + err.Error())
+		return
+	}
+
+	w.LmnlTxs.Add(Tx)
+	w.SendTx <- Tx
+
+	utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> transaction " + Tx.NameTag())
+
+	return
+}
+
+// Snd (Send) sends a transaction that was finished
+// outside of the wallet, such as the close of a payment
+// channel, to the node to be broadcast. The wallet waits
+// on it like on the transactions it made.
+// Inputs:
+// t *tx.Transaction the transaction
+func (w *Wallet) Snd(t *tx.Transaction) {
+	w.LmnlTxs.Add(t)
+	w.SendTx <- t
+
+	utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> transaction " + t.NameTag())
+}
+
+// PayScr (PayScript) pays an amount to a locking script,
+// such as the pay to script hash of a contract, and
+// sends the transaction to the node to be broadcast.
+// Inputs:
+// lck string the locking script as a hex string
+// amt uint32 the amount to pay
+// fee uint32 the fee
+// Returns:
+// *tx.Transaction the transaction, whose first output
+// pays lck
+// error if there isn't enough utxo
+func (w *Wallet) PayScr(lck string, amt, fee uint32) (*tx.Transaction, error) {
+	if amt == 0 {
+		return nil, errors.New("amount is 0")
+	}
+	t, err := w.mkPay(lck, amt, fee, w.Conf.DefLckTm)
+	if err != nil {
+		return nil, err
+	}
+	w.Snd(t)
+	return t, nil
+}
+
+// PubData (PublishData) publishes a payload on the
+// chain in a data output (see script.Data), and sends
+// the transaction to the node to be broadcast. The
+// transaction only pays the fee.
+// Inputs:
+// d []byte the payload
+// fee uint32 the fee
+// Returns:
+// *tx.Transaction the transaction, whose first output
+// carries d
+// error if the payload is too big, the fee is 0, or
+// there isn't enough utxo
+func (w *Wallet) PubData(d []byte, fee uint32) (*tx.Transaction, error) {
+	// Without a fee the transaction would spend nothing
+	if fee == 0 {
+		return nil, errors.New("fee is 0")
+	}
+	lck, err := script.Data(d)
+	if err != nil {
+		return nil, err
+	}
+	t, err := w.mkPay(lck, 0, fee, w.Conf.DefLckTm)
+	if err != nil {
+		return nil, err
+	}
+	w.Snd(t)
+	return t, nil
+}
+
+// mkPay (makePayment) makes a signed transaction that
+// pays amt to a locking script out of the wallet's utxo,
+// with the change going back to the wallet. The utxo it
+// spends is liminal, unless the transaction can't be
+// made.
+// Inputs:
+// lck string the locking script to pay, as a hex string
+// amt uint32 the amount to pay
+// fee uint32 the fee
+// lckTm uint32 the lock time of the transaction
+// Returns:
+// *tx.Transaction the transaction
+// error if there isn't enough utxo, or it can't be signed
+func (w *Wallet) mkPay(lck string, amt, fee, lckTm uint32) (*tx.Transaction, error) {
 	var protoTxI []*proto.TransactionInput
 	var protoTxO []*proto.TransactionOutput
 
-	UTXOinfo, change, enough := w.Chain.GetUTXOForAmt(txR.Amt+txR.Fee, hex.EncodeToString(w.Id.GetPublicKeyBytes()))
+	UTXOinfo, change, enough := w.UTXO.GetUTXOForAmt(amt+fee, hex.EncodeToString(w.Id.GetPublicKeyBytes()))
 
 	if !enough {
-		return
+		return nil, errors.New("not enough utxo")
 	}
 
 	for i := range UTXOinfo {
-		sig, Error := UTXOinfo[i].UTXO.MkSig(w.Id)
-		if Error != nil {
-			fmt.Println("ERROR {m.HndlTxReq}: unable to generate locking script")
-			return
-		}
-		protoTxI = append(protoTxI, proto.NewTxInpt(UTXOinfo[i].TxHsh, UTXOinfo[i].OutIdx, sig, UTXOinfo[i].Amt))
+		protoTxI = append(protoTxI, proto.NewTxInpt(UTXOinfo[i].TxHsh, UTXOinfo[i].OutIdx, "", UTXOinfo[i].Amt))
 	}
 
-	protoTxO = append(protoTxO, proto.NewTxOutpt(txR.Amt, hex.EncodeToString(txR.PubK)))
+	protoTxO = append(protoTxO, proto.NewTxOutpt(amt, lck))
 	if change > 0 {
-		protoTxO = append(protoTxO, proto.NewTxOutpt(change, hex.EncodeToString(w.Id.GetPublicKeyBytes())))
+		protoTxO = append(protoTxO, proto.NewTxOutpt(change, script.P2PKH(w.Id.GetPublicKeyBytes())))
 	}
 
-	protoTx := proto.NewTx(w.Conf.TxVer, protoTxI, protoTxO, w.Conf.DefLckTm)
+	protoTx := proto.NewTx(w.Conf.TxVer, protoTxI, protoTxO, lckTm)
 	Tx := tx.Deserialize(protoTx)
 
-	w.LmnlTxs.Add(Tx)
-	w.SendTx <- Tx
-
-	utils.Debug.Printf("Address " + utils.FmtAddr(w.Addr) + " -> transaction " + Tx.NameTag())
-
-	return
+	// Every input signs the whole transaction, so the
+	// signatures can't be reused in another transaction.
+	for i := range UTXOinfo {
+		sig, err := Tx.MkSig(i, UTXOinfo[i].UTXO, w.Id, tx.SigHshAll)
+		if err == nil {
+			Tx.Inputs[i].UnlockingScript, err = script.Unlck(UTXOinfo[i].UTXO.LockingScript, sig, w.Id.GetPublicKeyBytes())
+		}
+		if err != nil {
+			for _, inp := range Tx.Inputs {
+				w.UTXO.RlsUTXO(inp)
+			}
+			return nil, fmt.Errorf("unable to sign input %v: %v", i, err)
+		}
+	}
+	return Tx, nil
 }
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/valerr"
+	"testing"
+)
+
+// TestInptAmtMismatch signs a transaction that spends the
+// genesis utxo but claims the input is worth more than
+// it is. It should be rejected both on its own and as
+// part of a block.
+func TestInptAmtMismatch(t *testing.T) {
+	n := NewGenNd()
+	gen := n.Chain.GetLastBlock()
+	cb := gen.Transactions[0]
+	utxo := cb.Outputs[0]
+	tt := &tx.Transaction{
+		Inputs: []*txi.TransactionInput{
+			{TransactionHash: cb.Hash(), OutputIndex: 0, Amount: utxo.Amount * 2},
+		},
+		Outputs: []*txo.TransactionOutput{
+			{Amount: utxo.Amount + 10, LockingScript: blockchain.GENPK},
+		},
+	}
+	tt.Inputs[0].UnlockingScript, _ = tt.MkSig(0, utxo, n.Id, tx.SigHshAll)
+
+	if err := n.TxErr(tt); valerr.CodeOf(err) != valerr.BadAmt {
+		t.Errorf("Expected: %v - Actual: %v", valerr.BadAmt, err)
+	}
+	err := n.Chain.UTXOErr([]*tx.Transaction{tt}, gen.Hash())
+	if valerr.CodeOf(err) != valerr.BadAmt {
+		t.Errorf("Expected: %v - Actual: %v", valerr.BadAmt, err)
+	}
+
+	tt.Inputs[0].Amount = utxo.Amount
+	tt.Outputs[0].Amount = utxo.Amount - 10
+	tt.Inputs[0].UnlockingScript, _ = tt.MkSig(0, utxo, n.Id, tx.SigHshAll)
+	if err := n.TxErr(tt); err != nil {
+		t.Errorf("transaction with the real amount was rejected: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"BrunoCoin/pkg"
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/valerr"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestMisbhvBan has a peer send invalid blocks until it
+// is banned. Innocent rejections, like a transaction
+// spending unknown utxo, should not count, and neither
+// should blocks from a node that only claims to be the
+// peer.
+func TestMisbhvBan(t *testing.T) {
+	c := NewCluster(2)
+	StartCluster(c)
+	ConnectCluster(c)
+	time.Sleep(time.Second)
+	p := c[0].PeerDb.Get(c[1].Addr)
+	if p == nil || c[1].PeerDb.Get(c[0].Addr) == nil {
+		t.Fatalf("nodes did not peer")
+	}
+	// Requests on c[1]'s connection to c[0] carry its token
+	a := c[1].PeerDb.Get(c[0].Addr).Addr
+	gen := c[0].Chain.GetLastBlock().Hash()
+	bad := func(tag uint32) *proto.Block {
+		b := MkTstBlk(gen, tag)
+		b.Hdr.MrklRt = "bad"
+		return b.Serialize()
+	}
+
+	if _, err := address.New(c[0].Addr, 0).ForwardBlockRPC(bad(1), address.From(c[1].Addr)); err == nil {
+		t.Fatalf("invalid block was accepted")
+	}
+	if _, err := a.ForwardTransactionRPC(CreateTx(c[1], c[0].Id.GetPublicKeyBytes(), 1).Serialize(), address.From(c[1].Addr)); err == nil {
+		t.Fatalf("transaction spending unknown utxo was accepted")
+	}
+	if s := p.Scrs(); len(s) != 0 {
+		t.Errorf("forged or innocent rejection was scored: %v", s)
+	}
+
+	for i := uint32(2); i <= 3; i++ {
+		if !c[0].PeerDb.In(c[1].Addr) {
+			t.Fatalf("peer was disconnected after %v invalid blocks", i-2)
+		}
+		if _, err := a.ForwardBlockRPC(bad(i), address.From(c[1].Addr)); err == nil {
+			t.Fatalf("invalid block was accepted")
+		}
+	}
+	if s := p.Scrs()[peer.BadBlk]; s != 2*peer.Pnlts[peer.BadBlk] {
+		t.Errorf("Expected: %v - Actual: %v", 2*peer.Pnlts[peer.BadBlk], s)
+	}
+	if c[0].PeerDb.In(c[1].Addr) || !c[0].Bans.Has(c[1].Addr) {
+		t.Fatalf("peer was not disconnected and banned")
+	}
+
+	// The ban is on the host, so another port doesn't get back in
+	m := pkg.New(pkg.DefaultConfig(GetFreePort()))
+	m.Start()
+	for _, n := range []*pkg.Node{c[1], m} {
+		err := n.ConnectToPeer(c[0].Addr)
+		if r := valerr.FromStatus(err); r == nil || r.Code != pkg.RjctBan {
+			t.Errorf("Expected: %v - Actual: %v", pkg.RjctBan, err)
+		}
+		if c[0].PeerDb.In(n.Addr) {
+			t.Errorf("banned node %v peered", n.Addr)
+		}
+	}
+}
+
+// TestAdminBans bans and unbans a node through the
+// Admin service.
+func TestAdminBans(t *testing.T) {
+	c := NewCluster(2)
+	c[0].Conf.AdmnPort = GetFreePort()
+	StartCluster(c)
+	ConnectCluster(c)
+	time.Sleep(time.Second)
+	a := address.New(fmt.Sprintf("127.0.0.1:%v", c[0].Conf.AdmnPort), 0)
+
+	if _, err := a.AddBanRPC(&proto.BanRequest{Addr: c[1].Addr, Seconds: 60}); err != nil {
+		t.Fatalf("could not ban: %v", err)
+	}
+	if _, err := a.AddBanRPC(&proto.BanRequest{Addr: "banned:1"}); err != nil {
+		t.Fatalf("could not ban: %v", err)
+	}
+	if c[0].PeerDb.In(c[1].Addr) {
+		t.Errorf("banned peer was not disconnected")
+	}
+	res, err := a.ListBansRPC(&proto.Empty{})
+	if err != nil || len(res.Bans) != 2 {
+		t.Fatalf("Expected: 2 bans - Actual: %v (%v)", res, err)
+	}
+	// Addresses are banned by host
+	for _, b := range res.Bans {
+		if until := time.Unix(b.Until, 0); b.Addr != "banned" && until.After(time.Now().Add(time.Minute)) {
+			t.Errorf("ban of %v lasts until %v", b.Addr, until)
+		}
+	}
+
+	if _, err := a.RmvBanRPC(&proto.BanRequest{Addr: c[1].Addr}); err != nil {
+		t.Fatalf("could not unban: %v", err)
+	}
+	if _, err := a.RmvBanRPC(&proto.BanRequest{Addr: c[1].Addr}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected: %v - Actual: %v", codes.NotFound, status.Code(err))
+	}
+	c[1].ConnectToPeer(c[0].Addr)
+	time.Sleep(500 * time.Millisecond)
+	if !c[0].PeerDb.In(c[1].Addr) {
+		t.Errorf("unbanned node did not peer again")
+	}
+
+	// Banned addresses that are sent should not be kept
+	if _, err := address.New(c[0].Addr, 0).SendAddressesRPC(&proto.Addresses{Addrs: []*proto.Address{{Addr: "banned:1"}}}); err != nil {
+		t.Fatalf("could not send addresses: %v", err)
+	}
+	if c[0].AddrDb.Get("banned:1") != nil {
+		t.Errorf("banned address was kept")
+	}
+
+	// The Admin service is not served to peers
+	if _, err := address.New(c[0].Addr, 0).ListBansRPC(&proto.Empty{}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected: %v - Actual: %v", codes.Unimplemented, status.Code(err))
+	}
+}
@@ -0,0 +1,143 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/blockchain/blockdb"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// MkTstBlk makes a block on top of prv that only
+// has a coinbase transaction and satisfies its
+// difficulty target. The tag is used to give sibling
+// blocks different hashes.
+func MkTstBlk(prv string, tag uint32) *block.Block {
+	cb := tx.Deserialize(proto.NewTx(0, nil,
+		[]*proto.TransactionOutput{proto.NewTxOutpt(10, blockchain.GENPK)}, tag))
+	b := block.New(prv, []*tx.Transaction{cb}, utils.CalcPOWD(1))
+	for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+		b.Hdr.Nonce++
+	}
+	return b
+}
+
+// TestBlockDbReload builds a main chain with a fork,
+// closes the chain and opens it again from the same
+// file. The main chain and the fork should both be
+// back.
+func TestBlockDbReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocks.db")
+	bc := blockchain.New(blockchain.PersistentConfig(path))
+	gen := bc.GetLastBlock().Hash()
+	b1 := MkTstBlk(gen, 1)
+	b2 := MkTstBlk(b1.Hash(), 2)
+	fork := MkTstBlk(gen, 3)
+	bc.Add(b1)
+	bc.Add(b2)
+	bc.Add(fork)
+	if err := bc.Close(); err != nil {
+		t.Fatalf("could not close chain: %v", err)
+	}
+
+	bc = blockchain.New(blockchain.PersistentConfig(path))
+	defer bc.Close()
+	if bc.Length() != 3 {
+		t.Errorf("Expected: %v - Actual: %v", 3, bc.Length())
+	}
+	if bc.GetLastBlock().Hash() != b2.Hash() {
+		t.Errorf("reloaded chain has the wrong last block")
+	}
+	if bc.IndexOf(fork.Hash()) != 1 {
+		t.Errorf("fork was not reloaded")
+	}
+	ChkEqBlks(t, bc.List(), []*block.Block{blockchain.GenesisBlock(blockchain.DefaultConfig()), b1, b2})
+}
+
+// TestBlockDbTornWrite simulates a crash in the middle
+// of writing a block by appending half a record to the
+// block file. The blocks written before should load and
+// new blocks should still be stored properly.
+func TestBlockDbTornWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocks.db")
+	bc := blockchain.New(blockchain.PersistentConfig(path))
+	b1 := MkTstBlk(bc.GetLastBlock().Hash(), 1)
+	bc.Add(b1)
+	bc.Close()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{200, 0, 0, 0, 1, 2, 3, 4, 5, 6, 7})
+	f.Close()
+
+	bc = blockchain.New(blockchain.PersistentConfig(path))
+	if bc.Length() != 2 {
+		t.Fatalf("Expected: %v - Actual: %v", 2, bc.Length())
+	}
+	b2 := MkTstBlk(b1.Hash(), 2)
+	bc.Add(b2)
+	bc.Close()
+
+	bc = blockchain.New(blockchain.PersistentConfig(path))
+	defer bc.Close()
+	if bc.Length() != 3 || bc.GetLastBlock().Hash() != b2.Hash() {
+		t.Errorf("block written after recovery was lost")
+	}
+}
+
+// TestBlockDbReads builds a chain longer than the block
+// file keeps cached, and checks that blocks far down the
+// chain are read back whole from the file. Corrupting the
+// record of a block that isn't cached anymore should make
+// it unreadable, since it isn't kept in memory.
+func TestBlockDbReads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocks.db")
+	bc := blockchain.New(blockchain.PersistentConfig(path))
+	var blks []*block.Block
+	prv := bc.GetLastBlock().Hash()
+	for i := uint32(1); i <= 40; i++ {
+		b := MkTstBlk(prv, i)
+		bc.Add(b)
+		blks = append(blks, b)
+		prv = b.Hash()
+	}
+	for i, b := range bc.Slice(1, 3) {
+		if len(b.Transactions) != 1 || b.Transactions[0].Hash() != blks[i].Transactions[0].Hash() {
+			t.Errorf("block %v was not read back whole", i+1)
+		}
+	}
+	if b, h, _, ok := bc.FndTx(blks[0].Transactions[0].Hash()); !ok || h != 1 || b.Hash() != blks[0].Hash() {
+		t.Errorf("transaction on the first block was not found")
+	}
+	bc.Close()
+
+	db, err := blockdb.OpenFileBlockDb(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if db.Len() != 40 {
+		t.Fatalf("Expected: %v - Actual: %v", 40, db.Len())
+	}
+	if b := db.Get(blks[0].Hash()); b == nil || len(b.Transactions) != 1 {
+		t.Fatalf("first block was not read back whole")
+	}
+	for _, b := range blks[20:] {
+		db.Get(b.Hash())
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteAt([]byte{0xff}, 10)
+	f.Close()
+	if db.Get(blks[0].Hash()) != nil {
+		t.Errorf("corrupted block was not read from the file")
+	}
+}
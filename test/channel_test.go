@@ -0,0 +1,168 @@
+package test
+
+import (
+	"BrunoCoin/pkg"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/channel"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// strtChnlNds (startChannelNodes) starts a genesis node,
+// which pays into channels and mines, and a node it pays.
+func strtChnlNds(t *testing.T) (*pkg.Node, *pkg.Node) {
+	utils.SetDebug(true)
+	payer := NewGenNd()
+	payee := pkg.New(pkg.DefaultConfig(GetFreePort()))
+	StartCluster([]*pkg.Node{payer, payee})
+	ConnectCluster([]*pkg.Node{payer, payee})
+	payer.StartMiner()
+	time.Sleep(1 * time.Second)
+	return payer, payee
+}
+
+// opnChnl (openChannel) opens a channel and waits for the
+// funding transaction to be mined.
+func opnChnl(t *testing.T, payer, payee *pkg.Node, amt, lck uint32) *channel.Chnl {
+	t.Helper()
+	l := payer.Chain.Length()
+	c, err := payer.Chnls.Opn(payee.Addr, payee.Id.GetPublicKeyBytes(), amt, lck)
+	if err != nil {
+		t.Fatalf("could not open the channel: %v", err)
+	}
+	wtChnLen(t, []*pkg.Node{payer, payee}, l+1)
+	return c
+}
+
+// TestChnlPay makes many small payments over a channel,
+// and closes it with one transaction that pays the total.
+func TestChnlPay(t *testing.T) {
+	payer, payee := strtChnlNds(t)
+	c := opnChnl(t, payer, payee, 100, 50)
+	if pc := payee.Chnls.Get(c.ID); pc == nil || pc.Cap != 100 {
+		t.Fatalf("payee didn't take the channel")
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := payer.Chnls.Pay(c.ID, 3); err != nil {
+			t.Fatalf("payment %v failed: %v", i, err)
+		}
+	}
+	if err := payer.Chnls.Pay(c.ID, 60); err == nil {
+		t.Errorf("paid more than the channel has")
+	}
+	if pc := payee.Chnls.Get(c.ID); pc.Paid != 30 {
+		t.Errorf("Expected: 30 - Actual: %v", pc.Paid)
+	}
+	if err := payee.Chnls.Pay(c.ID, 1); err == nil {
+		t.Errorf("payee paid on the channel")
+	}
+
+	if _, err := payer.Chnls.Cls(c.ID); err != nil {
+		t.Fatalf("could not close the channel: %v", err)
+	}
+	wtChnLen(t, []*pkg.Node{payer, payee}, 3)
+	time.Sleep(500 * time.Millisecond)
+
+	AsrtBal(t, payee, 30)
+	for _, n := range []*pkg.Node{payer, payee} {
+		if n.Chnls.Get(c.ID).Cls == nil {
+			t.Errorf("%v didn't see the channel close", n.Addr)
+		}
+		if len(n.Chnls.Dspts()) != 0 {
+			t.Errorf("%v found a dispute on a cooperative close", n.Addr)
+		}
+	}
+}
+
+// TestChnlWatch checks that closes without the last
+// update are disputes, and that the payee closes a
+// channel before its lock time.
+func TestChnlWatch(t *testing.T) {
+	payer, payee := strtChnlNds(t)
+	a := opnChnl(t, payer, payee, 100, 50)
+	b := opnChnl(t, payer, payee, 100, 5)
+	for _, c := range []*channel.Chnl{a, b} {
+		for i := 0; i < 2; i++ {
+			if err := payer.Chnls.Pay(c.ID, 3); err != nil {
+				t.Fatalf("payment failed: %v", err)
+			}
+		}
+	}
+
+	// The payer takes back channel a, and the payer sees
+	// an old commitment close it.
+	old, _ := a.Cmt(3)
+	fake := block.New(payer.Chain.GetLastBlock().Hash(), []*tx.Transaction{a.Rfnd()}, "")
+	payee.Chnls.HndlBlks(&blockchain.ChnUpd{Connected: []*block.Block{fake}})
+	payer.Chnls.HndlBlks(&blockchain.ChnUpd{Connected: []*block.Block{block.New(fake.Hash(), []*tx.Transaction{old}, "")}})
+	for _, c := range []struct {
+		n   *pkg.Node
+		rsn string
+	}{{payee, "refunded"}, {payer, "closed with an old commitment"}} {
+		if d := c.n.Chnls.Dspts(); len(d) != 1 || d[0].Rsn != c.rsn || d[0].Chnl.ID != a.ID {
+			t.Errorf("%v: Expected: dispute %v - Actual: %v", c.n.Addr, c.rsn, d)
+		}
+	}
+
+	// Channel b is close enough to its lock time that the
+	// payee closes it on the next block it sees.
+	payee.Chnls.HndlBlks(&blockchain.ChnUpd{})
+	wtChnLen(t, []*pkg.Node{payer, payee}, 4)
+	time.Sleep(500 * time.Millisecond)
+	AsrtBal(t, payee, 6)
+	if payee.Chnls.Get(b.ID).Cls == nil {
+		t.Errorf("payee didn't close the channel before its lock time")
+	}
+	if len(payee.Chnls.Dspts()) != 1 {
+		t.Errorf("Expected: 1 dispute - Actual: %v", len(payee.Chnls.Dspts()))
+	}
+
+	// The refund of channel a leaves the main chain, so
+	// the channel is open again and there is no dispute.
+	payee.Chnls.HndlBlks(&blockchain.ChnUpd{Disconnected: []*block.Block{fake}})
+	if payee.Chnls.Get(a.ID).Cls != nil {
+		t.Errorf("channel closed by a disconnected block is still closed")
+	}
+	if len(payee.Chnls.Dspts()) != 0 {
+		t.Errorf("Expected: 0 disputes - Actual: %v", len(payee.Chnls.Dspts()))
+	}
+}
+
+// TestChnlEarlyRfnd checks that a payer who mines can't
+// put a refund of a channel on a block before the
+// channel's lock time, with the channel's lock time or
+// an earlier one.
+func TestChnlEarlyRfnd(t *testing.T) {
+	payer, payee := strtChnlNds(t)
+	c := opnChnl(t, payer, payee, 100, 50)
+	mkBlk := func(t *tx.Transaction) *block.Block {
+		cb := tx.Deserialize(proto.NewTx(0, nil,
+			[]*proto.TransactionOutput{proto.NewTxOutpt(1, blockchain.GENPK)}, 77))
+		b := block.New(payer.Chain.GetLastBlock().Hash(), []*tx.Transaction{cb, t}, utils.CalcPOWD(1))
+		for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+			b.Hdr.Nonce++
+		}
+		return b
+	}
+	for _, cs := range []struct {
+		lckTm uint32
+		code  valerr.Code
+	}{{c.Lck, valerr.NonFinal}, {0, valerr.BadSig}} {
+		rf := c.Rfnd()
+		rf.LockTime = cs.lckTm
+		sig, _ := rf.MkSig(0, c.Out(), payer.Id, tx.SigHshAll)
+		sigB, _ := hex.DecodeString(sig)
+		rf.Inputs[0].UnlockingScript = script.ChnlRfnd(sigB, c.Rdm)
+		if err := payer.BlkErr(mkBlk(rf)); valerr.CodeOf(err) != cs.code {
+			t.Errorf("lock time %v: Expected: %v - Actual: %v", cs.lckTm, cs.code, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"BrunoCoin/pkg"
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestCmpctRcnstr makes a compact block and rebuilds it
+// from some of its transactions. The rest should be
+// missing, and malformed compact blocks rejected.
+func TestCmpctRcnstr(t *testing.T) {
+	n := NewGenNd()
+	txs := []*tx.Transaction{n.Chain.GetLastBlock().Transactions[0]}
+	for i := 0; i < 5; i++ {
+		txs = append(txs, CreateTx(n, n.Id.GetPublicKeyBytes(), uint32(i+1)))
+	}
+	b := block.New(n.Chain.GetLastBlock().Hash(), txs, utils.CalcPOWD(1))
+	cb := b.Cmpct(7, func(t *tx.Transaction) bool { return t.Hash() == txs[1].Hash() })
+	if len(cb.Prefilled) != 2 || len(cb.ShortIds) != 4 {
+		t.Fatalf("Expected: 2 prefilled and 4 short ids - Actual: %v and %v", len(cb.Prefilled), len(cb.ShortIds))
+	}
+
+	got, miss, err := block.Rcnstr(cb, txs[2:4])
+	if err != nil {
+		t.Fatalf("could not rebuild: %v", err)
+	}
+	if len(miss) != 2 || miss[0] != 4 || miss[1] != 5 {
+		t.Errorf("Expected: missing [4 5] - Actual: %v", miss)
+	}
+	for i := 0; i < 4; i++ {
+		if got[i] == nil || got[i].Hash() != txs[i].Hash() {
+			t.Errorf("transaction %v was not rebuilt", i)
+		}
+	}
+
+	cb.Prefilled[1].Index = 9
+	if _, _, err := block.Rcnstr(cb, txs); err == nil {
+		t.Errorf("out of range prefilled transaction was accepted")
+	}
+}
+
+// TestCmpctRelay has a cluster relay a mined payment as
+// compact blocks. A node without the payment should get
+// it with GetBlockTxn.
+func TestCmpctRelay(t *testing.T) {
+	utils.SetDebug(true)
+	c := NewCluster(3)
+	StartCluster(c)
+	ConnectCluster(c)
+	time.Sleep(time.Second)
+	pt, err := c[0].Wallet.PayScr(script.P2PKH(c[1].Id.GetPublicKeyBytes()), 10, 20)
+	if err != nil {
+		t.Fatalf("could not pay: %v", err)
+	}
+	time.Sleep(time.Second)
+	c[0].StartMiner()
+	for end := time.Now().Add(15 * time.Second); time.Now().Before(end); time.Sleep(100 * time.Millisecond) {
+		if _, _, _, ok := c[2].Chain.FndTx(pt.Hash()); ok {
+			break
+		}
+	}
+	for _, n := range c {
+		if _, _, _, ok := n.Chain.FndTx(pt.Hash()); !ok {
+			t.Fatalf("%v did not get %v", n.Addr, pt.NameTag())
+		}
+	}
+
+	// A new node has none of the transactions, so they
+	// have to be asked for
+	d := pkg.New(pkg.DefaultConfig(GetFreePort()))
+	d.Start()
+	if err := d.ConnectToPeer(c[0].Addr); err != nil {
+		t.Fatalf("could not peer: %v", err)
+	}
+	a := c[0].PeerDb.Get(d.Addr).Addr
+	blks := c[0].Chain.List()
+	for i, b := range blks[1:] {
+		cb := b.Cmpct(1, func(*tx.Transaction) bool { return false })
+		if _, err := a.SendCmpctBlkRPC(cb, address.From(c[0].Addr)); err != nil {
+			t.Fatalf("could not send compact block: %v", err)
+		}
+		for end := time.Now().Add(5 * time.Second); d.Chain.Length() < i+2 && time.Now().Before(end); {
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	if _, _, _, ok := d.Chain.FndTx(pt.Hash()); !ok {
+		t.Errorf("%v was not rebuilt from compact blocks", pt.NameTag())
+	}
+
+	// A compact block without proof of work is rejected
+	// before it is rebuilt
+	bad := MkTstBlk(d.Chain.GetLastBlock().Hash(), 77)
+	bad.Hdr.DiffTarg = d.Chain.GetLastBlock().Hdr.DiffTarg
+	for bad.SatisfiesPOW(bad.Hdr.DiffTarg) {
+		bad.Hdr.Nonce++
+	}
+	_, err = a.SendCmpctBlkRPC(bad.Cmpct(1, func(*tx.Transaction) bool { return false }), address.From(c[0].Addr))
+	if r := valerr.FromStatus(err); r == nil || r.Code != string(valerr.BadPOW) {
+		t.Errorf("Expected: %v - Actual: %v", valerr.BadPOW, err)
+	}
+	if p := d.PeerDb.Get(c[0].Addr); p == nil || p.Scrs()[peer.BadBlk] != peer.Pnlts[peer.BadBlk] {
+		t.Errorf("peer that sent a compact block without proof of work was not scored")
+	}
+
+	_, err = address.New(c[0].Addr, 0).GetBlockTxnRPC(&proto.GetBlockTxnRequest{BlockHash: blks[0].Hash(), Indexes: []uint32{9}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected: %v - Actual: %v", codes.InvalidArgument, status.Code(err))
+	}
+	_, err = address.New(c[0].Addr, 0).GetBlockTxnRPC(&proto.GetBlockTxnRequest{BlockHash: "aa"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected: %v - Actual: %v", codes.NotFound, status.Code(err))
+	}
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"testing"
+	"time"
+)
+
+// TestPeerConnShared checks that requests to a peer
+// share one connection, which is closed when the peer
+// is evicted.
+func TestPeerConnShared(t *testing.T) {
+	c := NewCluster(2)
+	StartCluster(c)
+	ConnectCluster(c)
+	time.Sleep(time.Second)
+	p := c[0].PeerDb.Get(c[1].Addr)
+	if p == nil {
+		t.Fatalf("nodes did not peer")
+	}
+
+	cc, err := p.Conn.Get()
+	if err != nil {
+		t.Fatalf("could not connect: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := p.Addr.GetAddressesRPC(&proto.Empty{}); err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+	}
+	if cc2, _ := p.Conn.Get(); cc2 != cc {
+		t.Errorf("requests did not share the connection")
+	}
+
+	if !c[0].PeerDb.Rmv(c[1].Addr) || c[0].PeerDb.In(c[1].Addr) {
+		t.Fatalf("peer was not removed")
+	}
+	if _, err := p.Conn.Get(); err != peer.ErrClosed {
+		t.Errorf("Expected: %v - Actual: %v", peer.ErrClosed, err)
+	}
+	if _, err := p.Addr.GetAddressesRPC(&proto.Empty{}); err != nil {
+		t.Errorf("request after eviction failed: %v", err)
+	}
+}
+
+// TestPeerConnReconnect checks that the connection to
+// a peer comes back after the peer's network does.
+func TestPeerConnReconnect(t *testing.T) {
+	c := NewCluster(2)
+	StartCluster(c)
+	ConnectCluster(c)
+	time.Sleep(time.Second)
+	p := c[0].PeerDb.Get(c[1].Addr)
+	if p == nil {
+		t.Fatalf("nodes did not peer")
+	}
+	if _, err := p.Addr.GetAddressesRPC(&proto.Empty{}); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	cc, _ := p.Conn.Get()
+
+	c[1].PauseNetwork()
+	if _, err := p.Addr.GetAddressesRPC(&proto.Empty{}); err == nil {
+		t.Errorf("request to a paused peer succeeded")
+	}
+	c[1].ResumeNetwork()
+	var err error
+	for end := time.Now().Add(5 * time.Second); time.Now().Before(end); time.Sleep(100 * time.Millisecond) {
+		if _, err = p.Addr.GetAddressesRPC(&proto.Empty{}); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Errorf("did not reconnect: %v", err)
+	}
+	if cc2, _ := p.Conn.Get(); cc2 != cc {
+		t.Errorf("connection was made again instead of reconnecting")
+	}
+}
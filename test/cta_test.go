@@ -4,7 +4,6 @@ import (
 	"BrunoCoin/pkg"
 	"BrunoCoin/pkg/block/tx"
 	"BrunoCoin/pkg/blockchain"
-	"BrunoCoin/pkg/miner"
 	"BrunoCoin/pkg/proto"
 	"BrunoCoin/pkg/utils"
 	"fmt"
@@ -111,10 +110,9 @@ func TestBasicSystem(t *testing.T) {
 	// Creates an invalid transaction of the malicious node
 	// trying to spend the money given to the genesis node
 	// in the genesis transaction.
-	tforinp := genNd.Chain.LastBlock.PrevNode.Block.Transactions[0]
-	unlck, _ := tforinp.Outputs[0].MkSig(genNd.Id)
+	tforinp := genNd.Chain.Get(genNd.Chain.LastBlock.PrevNode.Hash()).Transactions[0]
 	txi := []*proto.TransactionInput{
-		proto.NewTxInpt(tforinp.Hash(), 0, unlck, tforinp.Outputs[0].Amount),
+		proto.NewTxInpt(tforinp.Hash(), 0, "", tforinp.Outputs[0].Amount),
 	}
 	amt2 := tforinp.Outputs[0].Amount - 10 - 40
 	txo := []*proto.TransactionOutput{
@@ -122,6 +120,7 @@ func TestBasicSystem(t *testing.T) {
 		proto.NewTxOutpt(amt2, fmt.Sprintf("%x", malNd.Id.GetPublicKeyBytes())),
 	}
 	txx := tx.Deserialize(proto.NewTx(0, txi, txo, 0))
+	txx.Inputs[0].UnlockingScript, _ = txx.MkSig(0, tforinp.Outputs[0], genNd.Id, tx.SigHshAll)
 
 	// Malicious node sends the invalid transaction to the
 	// network. This invalid transaction will be treated as
@@ -152,5 +151,5 @@ func TestBasicSystem(t *testing.T) {
 	// since it mined the transaction as well. Lastly it should
 	// also have the minting reward amount added to it's balance.
 	AsrtBal(t, genNd, blockchain.DefaultConfig().InitSbsdy-60)
-	AsrtBal(t, node2, 50+10+miner.DefaultConfig(4).InitSubsdy)
+	AsrtBal(t, node2, 50+10+blockchain.DefaultConfig().MntRwrd)
 }
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"bytes"
+	"testing"
+)
+
+// TestDataScr checks the data output script and the
+// merkle branches of block.MrklPrf.
+func TestDataScr(t *testing.T) {
+	d := []byte("document hash")
+	lck, err := script.Data(d)
+	if err != nil {
+		t.Fatalf("could not make the data script: %v", err)
+	}
+	if od, ok := script.DataOf(lck); !ok || !bytes.Equal(od, d) || !script.IsData(lck) {
+		t.Errorf("data script didn't parse back to its payload")
+	}
+	if _, err := script.Data(make([]byte, script.MxDataSz+1)); err == nil {
+		t.Errorf("made a data script over the size limit")
+	}
+	if script.IsData(script.P2PKH(d)) {
+		t.Errorf("pay to public key hash is a data script")
+	}
+	if script.Run("51", lck, nil) == nil {
+		t.Errorf("data script was unlocked")
+	}
+
+	var txs []*tx.Transaction
+	for i := 0; i < 5; i++ {
+		txs = append(txs, tx.Deserialize(proto.NewTx(0, nil,
+			[]*proto.TransactionOutput{proto.NewTxOutpt(uint32(i+1), "01")}, 0)))
+		hdr := block.Header{MrklRt: block.CalcMrklRt(txs)}
+		for j := range txs {
+			prf, ok := block.MrklPrf(txs, j)
+			if !ok || !hdr.ChkMrklPrf(txs[j].Hash(), j, prf) {
+				t.Errorf("%v txs: branch of %v doesn't lead to the root", len(txs), j)
+			}
+		}
+	}
+	if _, ok := block.MrklPrf(txs, len(txs)); ok {
+		t.Errorf("made a branch for a missing transaction")
+	}
+}
+
+// TestDataAnchr publishes a payload, and checks that it
+// can be found on the chain but never becomes utxo.
+func TestDataAnchr(t *testing.T) {
+	n := NewGenNd()
+	sent := make(chan *tx.Transaction, 2)
+	go func() {
+		for {
+			sent <- <-n.Wallet.SendTx
+		}
+	}()
+	addBlk := func(txs ...*tx.Transaction) *block.Block {
+		cb := tx.Deserialize(proto.NewTx(0, nil,
+			[]*proto.TransactionOutput{proto.NewTxOutpt(1, blockchain.GENPK)}, uint32(n.Chain.Length())))
+		b := block.New(n.Chain.GetLastBlock().Hash(), append([]*tx.Transaction{cb}, txs...), utils.CalcPOWD(1))
+		for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+			b.Hdr.Nonce++
+		}
+		return b
+	}
+
+	d := []byte("document hash")
+	if _, err := n.PubData(make([]byte, script.MxDataSz+1), 20); err == nil {
+		t.Errorf("published a payload over the size limit")
+	}
+	dt, err := n.PubData(d, 20)
+	if err != nil {
+		t.Fatalf("could not publish: %v", err)
+	}
+	<-sent
+	if err := n.TxErr(dt); err != nil {
+		t.Errorf("node rejected the data transaction: %v", err)
+	}
+
+	// Data outputs can't hold money, and there can only
+	// be one per transaction
+	lck := dt.Outputs[0].LockingScript
+	for _, outs := range [][]*txo.TransactionOutput{
+		{{Amount: 1, LockingScript: lck}, dt.Outputs[1]},
+		{dt.Outputs[0], {Amount: 0, LockingScript: lck}, dt.Outputs[1]},
+	} {
+		bad := tx.Deserialize(dt.Serialize())
+		bad.Outputs = outs
+		if err := n.BlkErr(addBlk(bad)); valerr.CodeOf(err) != valerr.BadData {
+			t.Errorf("Expected: %v - Actual: %v", valerr.BadData, err)
+		}
+	}
+
+	n.Chain.Add(addBlk(dt))
+	if n.Chain.GetUTXO(&txi.TransactionInput{TransactionHash: dt.Hash(), OutputIndex: 0}) != nil {
+		t.Errorf("data output is utxo")
+	}
+	if n.Chain.GetUTXO(&txi.TransactionInput{TransactionHash: dt.Hash(), OutputIndex: 1}) == nil {
+		t.Errorf("change of the data transaction isn't utxo")
+	}
+
+	a, ok := n.FndData(d)
+	if !ok {
+		t.Fatalf("payload wasn't found")
+	}
+	if a.Hght != 1 || a.Tx.Hash() != dt.Hash() || !a.Blk.Hdr.ChkMrklPrf(dt.Hash(), a.Idx, a.Prf) {
+		t.Errorf("Expected: %v at height 1 with a valid branch - Actual: %v at %v", dt.NameTag(), a.Tx.NameTag(), a.Hght)
+	}
+	if _, ok := n.FndData([]byte("other")); ok {
+		t.Errorf("found a payload that wasn't published")
+	}
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// mkBlkAt makes a coinbase only block with a particular
+// difficulty target and timestamp, and finds its nonce.
+func mkBlkAt(prv string, tag uint32, dt string, ts uint32) *block.Block {
+	cb := tx.Deserialize(proto.NewTx(0, nil,
+		[]*proto.TransactionOutput{proto.NewTxOutpt(10, blockchain.GENPK)}, tag))
+	b := block.New(prv, []*tx.Transaction{cb}, dt)
+	b.Hdr.Timestamp = ts
+	for !b.SatisfiesPOW(dt) {
+		b.Hdr.Nonce++
+	}
+	return b
+}
+
+// scaleTrg multiplies a difficulty target by num/den.
+func scaleTrg(dt string, num int64, den int64) string {
+	trg, _ := new(big.Int).SetString(dt, 16)
+	trg.Mul(trg, big.NewInt(num))
+	trg.Div(trg, big.NewInt(den))
+	return fmt.Sprintf("%064x", trg)
+}
+
+// TestDifTrgRetarget checks that the first block has the
+// initial target, that blocks keep the target of their
+// previous block inside a retarget interval, and that
+// the target is adjusted (at most 4x) at the end of an
+// interval based on the timestamps.
+func TestDifTrgRetarget(t *testing.T) {
+	conf := blockchain.DefaultConfig()
+	conf.RtrgtIntvl = 3
+	conf.TrgtBlkTm = 10
+	conf.InitTrg = utils.CalcPOWD(1)
+	bc := blockchain.New(conf)
+	gen := bc.GetLastBlock().Hash()
+	dt := conf.InitTrg
+
+	if nxt := bc.NextDifTrg(); nxt != dt {
+		t.Errorf("Expected: %v - Actual: %v", dt, nxt)
+	}
+	for _, bad := range []string{strings.Repeat("f", 64), conf.POWLmt, utils.CalcPOWD(2)} {
+		if bc.ChkDifTrg(mkBlkAt(gen, 0, bad, 100)) {
+			t.Errorf("first block picked its own target %v", bad)
+		}
+	}
+	b1 := mkBlkAt(gen, 1, dt, 100)
+	if !bc.ChkDifTrg(b1) {
+		t.Fatalf("first block with the initial target was rejected")
+	}
+	bc.Add(b1)
+	if bc.ChkDifTrg(mkBlkAt(b1.Hash(), 0, utils.CalcPOWD(2), 100)) {
+		t.Errorf("target changed inside a retarget interval")
+	}
+	b2 := mkBlkAt(b1.Hash(), 2, dt, 100)
+	b3 := mkBlkAt(b2.Hash(), 3, dt, 101)
+	bc.Add(b2)
+	bc.Add(b3)
+
+	// 1 second instead of 20, so 4x harder
+	hrdr := scaleTrg(dt, 1, 4)
+	if nxt := bc.NextDifTrg(); nxt != hrdr {
+		t.Errorf("Expected: %v - Actual: %v", hrdr, nxt)
+	}
+	if bc.ChkDifTrg(mkBlkAt(b3.Hash(), 4, dt, 102)) {
+		t.Errorf("block kept the old target after a retarget")
+	}
+	if !bc.ChkDifTrg(mkBlkAt(b3.Hash(), 4, hrdr, 102)) {
+		t.Errorf("block with the new target was rejected")
+	}
+
+	// 30 seconds instead of 20 on a fork, so 1.5x easier
+	f2 := mkBlkAt(b1.Hash(), 12, dt, 120)
+	f3 := mkBlkAt(f2.Hash(), 13, dt, 130)
+	bc.Add(f2)
+	bc.Add(f3)
+	if !bc.ChkDifTrg(mkBlkAt(f3.Hash(), 14, scaleTrg(dt, 30, 20), 140)) {
+		t.Errorf("fork was not retargeted from its own timestamps")
+	}
+}
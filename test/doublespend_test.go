@@ -0,0 +1,110 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/miner"
+	"BrunoCoin/pkg/valerr"
+	"testing"
+)
+
+// mkSpnd makes a transaction spending the first output
+// of src that pays the rest of the input as a fee.
+func mkSpnd(src *tx.Transaction, out uint32, tag string) *tx.Transaction {
+	return &tx.Transaction{
+		Inputs: []*txi.TransactionInput{
+			{TransactionHash: src.Hash(), OutputIndex: 0, Amount: src.Outputs[0].Amount},
+		},
+		Outputs: []*txo.TransactionOutput{
+			{Amount: out, LockingScript: tag},
+		},
+	}
+}
+
+// TestTxPoolDblSpnd checks that the transaction pool rejects
+// a second spend of an output, unless replace-by-fee is on
+// and the second spend pays more.
+func TestTxPoolDblSpnd(t *testing.T) {
+	src := &tx.Transaction{Outputs: []*txo.TransactionOutput{{Amount: 100, LockingScript: "00"}}}
+	a := mkSpnd(src, 90, "01")
+	b := mkSpnd(src, 80, "02")
+
+	tp := miner.NewTxPool(miner.DefaultConfig(1))
+	if err := tp.Add(a); err != nil {
+		t.Fatalf("first spend was rejected: %v", err)
+	}
+	if err := tp.Add(b); err == nil {
+		t.Errorf("second spend was accepted without replace-by-fee")
+	}
+	if c := <-tp.Conflicts; c.Tx != b || c.By != a || c.Mined {
+		t.Errorf("wrong conflict reported: %v lost to %v", c.Tx.NameTag(), c.By.NameTag())
+	}
+	if !tp.TxQ.Has(a) || tp.TxQ.Has(b) || tp.Length() != 1 {
+		t.Errorf("pool changed after rejecting a double spend")
+	}
+
+	tp = miner.NewTxPool(miner.DefaultConfig(1))
+	tp.RBF = true
+	tp.Add(b)
+	if err := tp.Add(a); err == nil {
+		t.Errorf("replacement with a lower fee was accepted")
+	}
+	<-tp.Conflicts
+	tp.Add(b)
+	c := mkSpnd(src, 70, "03")
+	if err := tp.Add(c); err != nil {
+		t.Errorf("replacement with a higher fee was rejected: %v", err)
+	}
+	if cf := <-tp.Conflicts; cf.Tx != b || cf.By != c {
+		t.Errorf("wrong conflict reported for the replacement")
+	}
+	if tp.TxQ.Has(b) || !tp.TxQ.Has(c) || tp.Length() != 1 {
+		t.Errorf("replacement didn't evict the lower fee spend")
+	}
+
+	// A mined spend evicts the spend in the pool
+	tp.ChkTxs([]*tx.Transaction{a})
+	if cf := <-tp.Conflicts; cf.Tx != c || cf.By != a || !cf.Mined {
+		t.Errorf("wrong conflict reported for the mined spend")
+	}
+	if tp.Length() != 0 || tp.CurPri.Load() != 0 {
+		t.Errorf("mined spend didn't evict the conflicting spend")
+	}
+}
+
+// TestBlkDblSpnd checks that two transactions in the same
+// block can't spend the same utxo, and that a transaction
+// can't spend the same utxo twice.
+func TestBlkDblSpnd(t *testing.T) {
+	n := NewGenNd()
+	gen := n.Chain.GetLastBlock()
+	cb := gen.Transactions[0]
+	utxo := cb.Outputs[0]
+	sign := func(tt *tx.Transaction) *tx.Transaction {
+		for i := range tt.Inputs {
+			tt.Inputs[i].UnlockingScript, _ = tt.MkSig(i, utxo, n.Id, tx.SigHshAll)
+		}
+		return tt
+	}
+	a := sign(mkSpnd(cb, utxo.Amount-10, blockchain.GENPK))
+	b := sign(mkSpnd(cb, utxo.Amount-20, blockchain.GENPK))
+
+	if err := n.Chain.UTXOErr([]*tx.Transaction{a}, gen.Hash()); err != nil {
+		t.Errorf("single spend was rejected: %v", err)
+	}
+	err := n.Chain.UTXOErr([]*tx.Transaction{a, b}, gen.Hash())
+	if valerr.CodeOf(err) != valerr.DblSpnd {
+		t.Errorf("Expected: %v - Actual: %v", valerr.DblSpnd, err)
+	}
+
+	tt := mkSpnd(cb, utxo.Amount-10, blockchain.GENPK)
+	tt.Inputs = append(tt.Inputs, &txi.TransactionInput{
+		TransactionHash: cb.Hash(), OutputIndex: 0, Amount: utxo.Amount,
+	})
+	sign(tt)
+	if err := n.TxErr(tt); valerr.CodeOf(err) != valerr.DblSpnd {
+		t.Errorf("Expected: %v - Actual: %v", valerr.DblSpnd, err)
+	}
+}
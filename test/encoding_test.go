@@ -0,0 +1,172 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/enc"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// The vectors below are the same as the ones in
+// docs/encoding.md. They were computed separately
+// from this implementation, from the description in
+// the docs.
+const (
+	vecTxEnc  = "010000000101aa01000000010b1e000000011900000001cc07000000"
+	vecTxHsh  = "7e6c050ebd88dd2b04f3766ed1dc22e420cf91d295b10b3bff8144de451ec95a"
+	vecHdrEnc = "0100000001ab01cd00105e5f010f2a000000"
+	vecHdrHsh = "c13365029159fbbb464c96e96803e6a49f25008ddd6c2800f0f4a725dabd8a74"
+)
+
+func vecTx(ver uint32) *tx.Transaction {
+	return &tx.Transaction{
+		Version: ver,
+		Inputs: []*txi.TransactionInput{
+			{TransactionHash: "aa", OutputIndex: 1, UnlockingScript: "0b", Amount: 30},
+		},
+		Outputs:  []*txo.TransactionOutput{{Amount: 25, LockingScript: "cc"}},
+		LockTime: 7,
+	}
+}
+
+func vecBlk(ver uint32, txs []*tx.Transaction) *block.Block {
+	return &block.Block{
+		Hdr: block.Header{
+			Ver:       ver,
+			PrvBlkHsh: "ab",
+			MrklRt:    "cd",
+			Timestamp: 1600000000,
+			DiffTarg:  "0f",
+			Nonce:     42,
+		},
+		Transactions: txs,
+	}
+}
+
+// TestEncVectors checks the canonical encoding and
+// hashes against the documented test vectors.
+func TestEncVectors(t *testing.T) {
+	tt := vecTx(tx.EncVer)
+	if e := hex.EncodeToString(tt.Enc()); e != vecTxEnc {
+		t.Errorf("Expected: %v - Actual: %v", vecTxEnc, e)
+	}
+	if tt.Hash() != vecTxHsh {
+		t.Errorf("Expected: %v - Actual: %v", vecTxHsh, tt.Hash())
+	}
+	if tt.Sz() != uint32(len(vecTxEnc)/2) {
+		t.Errorf("Expected: %v - Actual: %v", len(vecTxEnc)/2, tt.Sz())
+	}
+
+	b := vecBlk(block.EncVer, []*tx.Transaction{tt})
+	if e := hex.EncodeToString(b.Hdr.Enc()); e != vecHdrEnc {
+		t.Errorf("Expected: %v - Actual: %v", vecHdrEnc, e)
+	}
+	if b.Hash() != vecHdrHsh {
+		t.Errorf("Expected: %v - Actual: %v", vecHdrHsh, b.Hash())
+	}
+	blkEnc := vecHdrEnc + "01" + vecTxEnc
+	if e := hex.EncodeToString(b.Enc()); e != blkEnc {
+		t.Errorf("Expected: %v - Actual: %v", blkEnc, e)
+	}
+	if b.Sz() != uint32(len(blkEnc)/2) {
+		t.Errorf("Expected: %v - Actual: %v", len(blkEnc)/2, b.Sz())
+	}
+	if r := block.CalcMrklRt([]*tx.Transaction{tt}); r != vecTxHsh {
+		t.Errorf("merkle leaf is not the encoded hash: %v", r)
+	}
+
+	cnts := map[uint64]string{
+		0:           "00",
+		0xfc:        "fc",
+		0xfd:        "fdfd00",
+		0xffff:      "fdffff",
+		0x10000:     "fe00000100",
+		0xffffffff:  "feffffffff",
+		0x100000000: "ff0000000001000000",
+	}
+	for n, exp := range cnts {
+		w := &enc.Wtr{}
+		w.Cnt(n)
+		if e := hex.EncodeToString(w.Bytes()); e != exp {
+			t.Errorf("count %#x: Expected: %v - Actual: %v", n, exp, e)
+		}
+	}
+
+	hexs := map[string]string{
+		"":     "00",
+		"0b":   "010b",
+		"aabb": "02aabb",
+		"AA":   "ff02000000000000004141",
+		"abc":  "ff0300000000000000616263",
+	}
+	for s, exp := range hexs {
+		w := &enc.Wtr{}
+		w.Hex(s)
+		if e := hex.EncodeToString(w.Bytes()); e != exp {
+			t.Errorf("hex %q: Expected: %v - Actual: %v", s, exp, e)
+		}
+	}
+}
+
+// TestEncHexCase checks that a transaction can't be given
+// another hash by writing its hex in upper case: the
+// hash changes, but the transaction is rejected, on its
+// own and on a block.
+func TestEncHexCase(t *testing.T) {
+	n := NewGenNd()
+	gen := n.Chain.GetLastBlock()
+	cb := gen.Transactions[0]
+	tt := mkSpnd(cb, cb.Outputs[0].Amount-10, blockchain.GENPK)
+	tt.Version = tx.EncVer
+	tt.Inputs[0].UnlockingScript, _ = tt.MkSig(0, cb.Outputs[0], n.Id, tx.SigHshAll)
+	if err := n.TxErr(tt); err != nil {
+		t.Fatalf("signed transaction was rejected: %v", err)
+	}
+
+	up := tx.Deserialize(tt.Serialize())
+	up.Inputs[0].UnlockingScript = strings.ToUpper(up.Inputs[0].UnlockingScript)
+	if up.Hash() == tt.Hash() {
+		t.Errorf("upper case script has the same hash")
+	}
+	if err := up.UnlckErr(0, cb.Outputs[0]); err != nil {
+		t.Fatalf("upper case script should still unlock: %v", err)
+	}
+	if err := n.TxErr(up); valerr.CodeOf(err) != valerr.Malformed {
+		t.Errorf("Expected: %v - Actual: %v", valerr.Malformed, err)
+	}
+	b := block.New(gen.Hash(), []*tx.Transaction{MkTstBlk("", 1).Transactions[0], up}, utils.CalcPOWD(1))
+	for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+		b.Hdr.Nonce++
+	}
+	if err := n.BlkErr(b); valerr.CodeOf(err) != valerr.Malformed {
+		t.Errorf("Expected: %v - Actual: %v", valerr.Malformed, err)
+	}
+}
+
+// TestEncVerGate checks that transactions and blocks
+// older than the encoding version keep their legacy
+// hashes.
+func TestEncVerGate(t *testing.T) {
+	tt := vecTx(0)
+	lgcy := utils.Hash([]byte(fmt.Sprintf("%v/%v/%v/%v", tt.Version, tt.LockTime,
+		strings.Join([]string{tt.Inputs[0].Hash()}, "/"),
+		strings.Join([]string{tt.Outputs[0].Hash()}, "/"))))
+	if tt.Hash() != lgcy {
+		t.Errorf("Expected: %v - Actual: %v", lgcy, tt.Hash())
+	}
+	b := vecBlk(0, nil)
+	if lgcy := utils.Hash([]byte(fmt.Sprintf("%v", b.Hdr))); b.Hash() != lgcy {
+		t.Errorf("Expected: %v - Actual: %v", lgcy, b.Hash())
+	}
+	if vecTx(0).Hash() == vecTx(tx.EncVer).Hash() {
+		t.Errorf("version didn't change the hash")
+	}
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"BrunoCoin/pkg"
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/valerr"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestHndshk has a full node and a light node peer with
+// the genesis node. Each side should know the other's
+// services and user agent.
+func TestHndshk(t *testing.T) {
+	gen := NewGenNd()
+	full := pkg.New(pkg.DefaultConfig(GetFreePort()))
+	lght := pkg.New(pkg.LightConfig(GetFreePort()))
+	StartCluster([]*pkg.Node{gen, full, lght})
+	for _, n := range []*pkg.Node{full, lght} {
+		if err := n.ConnectToPeer(gen.Addr); err != nil {
+			t.Fatalf("%v could not peer: %v", n.Addr, err)
+		}
+		for _, c := range [][2]*pkg.Node{{gen, n}, {n, gen}} {
+			p := c[0].PeerDb.Get(c[1].Addr)
+			if p == nil {
+				t.Fatalf("%v did not add %v as a peer", c[0].Addr, c[1].Addr)
+			}
+			if p.Svcs != c[1].Svcs() || p.UsrAgnt != pkg.UsrAgnt {
+				t.Errorf("Expected: %v %v - Actual: %v %v", c[1].Svcs(), pkg.UsrAgnt, p.Svcs, p.UsrAgnt)
+			}
+		}
+	}
+	if s := gen.PeerDb.Get(lght.Addr).Svcs; !s.Has(peer.SvcLght) || s.Has(peer.SvcChn) {
+		t.Errorf("light node advertised %b", s)
+	}
+	if s := gen.PeerDb.Get(full.Addr).Svcs; !s.Has(peer.SvcChn | peer.SvcWt) {
+		t.Errorf("full node advertised %b", s)
+	}
+}
+
+// TestHndshkRjct checks that nodes that can't peer are
+// rejected with the reason why.
+func TestHndshkRjct(t *testing.T) {
+	n := NewGenNd()
+	n.Conf.MinVer = 1
+	StartCluster([]*pkg.Node{n})
+	othr := pkg.DefaultConfig(GetFreePort())
+	othr.ChainID = pkg.MainNet + 1
+	old := pkg.DefaultConfig(GetFreePort())
+	old.Version = 0
+	bnd := pkg.NoMnrConfig(GetFreePort())
+	cases := []struct {
+		c    *pkg.Config
+		code string
+	}{
+		{othr, pkg.RjctChn},
+		{old, pkg.RjctVer},
+		{bnd, pkg.RjctBan},
+	}
+	err := n.ConnectToPeer(n.Addr)
+	if r := valerr.FromStatus(err); r == nil || r.Code != pkg.RjctSlf {
+		t.Errorf("Expected: %v - Actual: %v", pkg.RjctSlf, err)
+	}
+	if n.PeerDb.In(n.Addr) {
+		t.Errorf("node peered with itself")
+	}
+
+	// A node that refuses the verack never accepts it, so
+	// neither side adds the other
+	pck := pkg.New(pkg.DefaultConfig(GetFreePort()))
+	pck.Conf.MinVer = n.Conf.Version + 1
+	pck.Start()
+	err = pck.ConnectToPeer(n.Addr)
+	if r := valerr.FromStatus(err); r == nil || r.Code != pkg.RjctVer {
+		t.Errorf("Expected: %v - Actual: %v", pkg.RjctVer, err)
+	}
+	if n.PeerDb.In(pck.Addr) || pck.PeerDb.In(n.Addr) {
+		t.Errorf("%v was added as a peer after refusing the verack", pck.Addr)
+	}
+	_, err = address.New(n.Addr, 0).SendVerackRPC(&proto.Empty{}, address.From(pck.Addr))
+	if status.Code(err) != codes.PermissionDenied || n.PeerDb.In(pck.Addr) {
+		t.Errorf("Expected: %v - Actual: %v", codes.PermissionDenied, err)
+	}
+
+	// Bans are by host, so banning comes last
+	for _, c := range cases {
+		m := pkg.New(c.c)
+		m.Start()
+		if c.code == pkg.RjctBan {
+			n.Ban(m.Addr, time.Minute, 0)
+		}
+		err := m.ConnectToPeer(n.Addr)
+		if r := valerr.FromStatus(err); r == nil || r.Code != c.code {
+			t.Errorf("Expected: %v - Actual: %v", c.code, err)
+		}
+		if n.PeerDb.In(m.Addr) || m.PeerDb.In(n.Addr) {
+			t.Errorf("%v peered despite %v", m.Addr, c.code)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestBlkErrReasons checks that each kind of invalid
+// header or coinbase is rejected with its own reason.
+func TestBlkErrReasons(t *testing.T) {
+	n := NewGenNd()
+	gen := n.Chain.GetLastBlock().Hash()
+	b1 := MkTstBlk(gen, 1)
+	if err := n.BlkErr(b1); err != nil {
+		t.Fatalf("valid block was rejected: %v", err)
+	}
+	n.Chain.Add(b1)
+
+	mod := func(tag uint32, f func(b *block.Block)) *block.Block {
+		b := MkTstBlk(b1.Hash(), tag)
+		f(b)
+		return b
+	}
+	cb := tx.Deserialize(proto.NewTx(0, nil,
+		[]*proto.TransactionOutput{proto.NewTxOutpt(11, blockchain.GENPK)}, 0))
+	cases := []struct {
+		b   *block.Block
+		rsn string
+	}{
+		{MkTstBlk("unknown", 2), "previous block"},
+		{mod(3, func(b *block.Block) { b.Hdr.MrklRt = "00" }), "merkle root"},
+		{mod(4, func(b *block.Block) { b.Hdr.Ver = block.CurVer + 1 }), "version"},
+		{mod(5, func(b *block.Block) { b.Hdr.Timestamp = 1 }), "median time past"},
+		{mod(6, func(b *block.Block) {
+			b.Hdr.Timestamp = uint32(time.Now().Add(3 * time.Hour).Unix())
+		}), "future"},
+		{block.New(b1.Hash(), []*tx.Transaction{cb}, utils.CalcPOWD(1)), "coinbase"},
+	}
+	for _, c := range cases {
+		err := n.BlkErr(c.b)
+		if err == nil || !strings.Contains(err.Error(), c.rsn) {
+			t.Errorf("Expected: %v - Actual: %v", c.rsn, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"BrunoCoin/pkg"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/peer"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLocator checks that a locator starts at the last
+// block and ends at the genesis block, and that a chain
+// finds where a fork's locator leaves its main chain.
+func TestLocator(t *testing.T) {
+	bc := blockchain.New(blockchain.DefaultConfig())
+	gen := bc.GetLastBlock().Hash()
+	prv := gen
+	var main []string
+	for i := 0; i < 30; i++ {
+		b := MkTstBlk(prv, uint32(i))
+		bc.Add(b)
+		prv = b.Hash()
+		main = append(main, prv)
+	}
+	loc := bc.Locator()
+	if loc[0] != prv || loc[len(loc)-1] != gen || len(loc) >= 30 {
+		t.Errorf("bad locator of %v hashes", len(loc))
+	}
+	if h := bc.FndFork(loc); h != 30 {
+		t.Errorf("Expected: %v - Actual: %v", 30, h)
+	}
+
+	fork := blockchain.New(blockchain.DefaultConfig())
+	for _, h := range main[:20] {
+		fork.Add(bc.Get(h))
+	}
+	fprv := main[19]
+	for i := 0; i < 3; i++ {
+		b := MkTstBlk(fprv, uint32(100+i))
+		fork.Add(b)
+		fprv = b.Hash()
+	}
+	if h := bc.FndFork(fork.Locator()); h != 20 {
+		t.Errorf("Expected: %v - Actual: %v", 20, h)
+	}
+	if h := bc.FndFork([]string{"unknown"}); h != -1 {
+		t.Errorf("Expected: %v - Actual: %v", -1, h)
+	}
+}
+
+// TestChkHdrs checks that a chain of headers is only
+// valid if each header follows the one before it and has
+// valid proof of work, and that nothing is added.
+func TestChkHdrs(t *testing.T) {
+	bc := blockchain.New(blockchain.DefaultConfig())
+	prv := bc.GetLastBlock().Hash()
+	var hdrs []block.Header
+	for i := 0; i < 3; i++ {
+		b := MkTstBlk(prv, uint32(i))
+		hdrs = append(hdrs, b.Hdr)
+		prv = b.Hash()
+	}
+	if err := bc.ChkHdrs(hdrs); err != nil {
+		t.Fatalf("valid headers were rejected: %v", err)
+	}
+	if bc.Length() != 1 {
+		t.Errorf("headers were added to the chain")
+	}
+
+	bad := MkTstBlk(hdrs[1].PrvBlkHsh, 10)
+	for bad.SatisfiesPOW(bad.Hdr.DiffTarg) {
+		bad.Hdr.Nonce++
+	}
+	cases := []struct {
+		hdrs []block.Header
+		rsn  string
+	}{
+		{[]block.Header{hdrs[0], hdrs[2]}, "does not follow"},
+		{hdrs[1:], "previous block"},
+		{[]block.Header{hdrs[0], bad.Hdr}, "difficulty target"},
+	}
+	for _, c := range cases {
+		err := bc.ChkHdrs(c.hdrs)
+		if err == nil || !strings.Contains(err.Error(), c.rsn) {
+			t.Errorf("Expected: %v - Actual: %v", c.rsn, err)
+		}
+	}
+}
+
+// TestBootstrapPastLimit has a node bootstrap a chain
+// longer than one reply of headers from two peers, which
+// it should download in full.
+func TestBootstrapPastLimit(t *testing.T) {
+	utils.SetDebug(false)
+	defer utils.SetDebug(true)
+	mkNd := func(c *pkg.Config) *pkg.Node {
+		c.ChainConf.RtrgtIntvl = 0
+		c.ChainConf.SbsdyHlvRt = 10000
+		return pkg.New(c)
+	}
+	genNd := mkNd(GenConf(GetFreePort()))
+	node2 := mkNd(pkg.DefaultConfig(GetFreePort()))
+	node3 := mkNd(pkg.DefaultConfig(GetFreePort()))
+	prv := genNd.Chain.GetLastBlock().Hash()
+	for i := 0; i < pkg.MxHdrs+20; i++ {
+		b := MkTstBlk(prv, uint32(i))
+		genNd.Chain.Add(b)
+		node2.Chain.Add(b)
+		prv = b.Hash()
+	}
+	StartCluster([]*pkg.Node{genNd, node2, node3})
+	node3.ConnectToPeer(genNd.Addr)
+	node3.ConnectToPeer(node2.Addr)
+	time.Sleep(time.Second)
+
+	if err := node3.Bootstrap(); err != nil {
+		t.Fatalf("could not bootstrap: %v", err)
+	}
+	ChkMnChnLen(t, node3, pkg.MxHdrs+21)
+	ChkMnChnCons(t, []*pkg.Node{genNd, node2, node3})
+}
+
+// TestBootstrapBadPeer has a node bootstrap from two
+// peers. The one whose chain has more work has an invalid
+// block, so the node should drop it and sync the other
+// peer's chain.
+func TestBootstrapBadPeer(t *testing.T) {
+	mkNd := func(c *pkg.Config) *pkg.Node {
+		c.ChainConf.RtrgtIntvl = 0
+		return pkg.New(c)
+	}
+	good := mkNd(GenConf(GetFreePort()))
+	bad := mkNd(pkg.DefaultConfig(GetFreePort()))
+	nd := mkNd(pkg.DefaultConfig(GetFreePort()))
+	gen := good.Chain.GetLastBlock().Hash()
+	for i, prv := 0, gen; i < 2; i++ {
+		b := MkTstBlk(prv, uint32(i))
+		good.Chain.Add(b)
+		prv = b.Hash()
+	}
+	// The coinbase pays more than the subsidy
+	cb := tx.Deserialize(proto.NewTx(0, nil,
+		[]*proto.TransactionOutput{proto.NewTxOutpt(1000, blockchain.GENPK)}, 100))
+	inv := block.New(gen, []*tx.Transaction{cb}, utils.CalcPOWD(1))
+	for !inv.SatisfiesPOW(inv.Hdr.DiffTarg) {
+		inv.Hdr.Nonce++
+	}
+	bad.Chain.Add(inv)
+	for i, prv := 0, inv.Hash(); i < 2; i++ {
+		b := MkTstBlk(prv, uint32(101+i))
+		bad.Chain.Add(b)
+		prv = b.Hash()
+	}
+	StartCluster([]*pkg.Node{good, bad, nd})
+	nd.ConnectToPeer(good.Addr)
+	nd.ConnectToPeer(bad.Addr)
+	time.Sleep(time.Second)
+
+	if err := nd.Bootstrap(); err != nil {
+		t.Fatalf("could not bootstrap: %v", err)
+	}
+	ChkMnChnCons(t, []*pkg.Node{good, nd})
+	if p := nd.PeerDb.Get(bad.Addr); p == nil || p.Scrs()[peer.BadBlk] != peer.Pnlts[peer.BadBlk] {
+		t.Errorf("peer with an invalid block was not scored")
+	}
+}
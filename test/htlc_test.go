@@ -0,0 +1,229 @@
+package test
+
+import (
+	"BrunoCoin/pkg"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// TestHTLCScr checks who can unlock a hash time locked
+// contract, and when.
+func TestHTLCScr(t *testing.T) {
+	rcv, _ := id.CreateSimpleID()
+	snd, _ := id.CreateSimpleID()
+	pre := []byte("preimage")
+	h := sha256.Sum256(pre)
+
+	for _, lck := range []uint32{20, script.LckTmThrsh + 20} {
+		trms := script.HTLCTrms{Hsh: h[:], Rcv: rcv.GetPublicKeyBytes(), Snd: snd.GetPublicKeyBytes(), Lck: lck}
+		rdm := script.HTLC(trms)
+		if p, ok := script.PrsHTLC(rdm); !ok || p.Lck != lck || !bytes.Equal(p.Snd, trms.Snd) {
+			t.Errorf("lock %v: contract didn't parse back to its terms", lck)
+		}
+		tt, o := mkScrTx(script.P2SH(rdm))
+		cases := []struct {
+			lckTm uint32
+			unlck func() string
+			valid bool
+		}{
+			{0, func() string { return script.HTLCClm(sigB(t, tt, o, rcv), pre, rdm) }, true},
+			{0, func() string { return script.HTLCClm(sigB(t, tt, o, rcv), []byte("guess"), rdm) }, false},
+			{0, func() string { return script.HTLCClm(sigB(t, tt, o, snd), pre, rdm) }, false},
+			{lck, func() string { return script.HTLCRfnd(sigB(t, tt, o, snd), rdm) }, true},
+			{lck + 1, func() string { return script.HTLCRfnd(sigB(t, tt, o, snd), rdm) }, true},
+			{lck - 1, func() string { return script.HTLCRfnd(sigB(t, tt, o, snd), rdm) }, false},
+			{lck, func() string { return script.HTLCRfnd(sigB(t, tt, o, rcv), rdm) }, false},
+			{lck ^ script.LckTmThrsh, func() string { return script.HTLCRfnd(sigB(t, tt, o, snd), rdm) }, false},
+		}
+		for i, c := range cases {
+			tt.LockTime = c.lckTm
+			tt.Inputs[0].UnlockingScript = c.unlck()
+			if err := tt.UnlckErr(0, o); (err == nil) != c.valid {
+				t.Errorf("lock %v case %v: Expected: %v - Actual: %v", lck, i, c.valid, err)
+			}
+		}
+	}
+}
+
+// TestHTLCRfnd has a wallet pay into a contract and take
+// it back, and checks that the refund can't be on a
+// block before the contract's lock time, even with an
+// earlier lock time of its own.
+func TestHTLCRfnd(t *testing.T) {
+	n := NewGenNd()
+	rcv, _ := id.CreateSimpleID()
+	h := sha256.Sum256([]byte("preimage"))
+	sent := make(chan *tx.Transaction, 2)
+	go func() {
+		for {
+			sent <- <-n.Wallet.SendTx
+		}
+	}()
+	addBlk := func(txs ...*tx.Transaction) *block.Block {
+		cb := tx.Deserialize(proto.NewTx(0, nil,
+			[]*proto.TransactionOutput{proto.NewTxOutpt(1, blockchain.GENPK)}, uint32(n.Chain.Length())))
+		b := block.New(n.Chain.GetLastBlock().Hash(), append([]*tx.Transaction{cb}, txs...), utils.CalcPOWD(1))
+		for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+			b.Hdr.Nonce++
+		}
+		return b
+	}
+
+	ft, rdm, err := n.Wallet.MkHTLC(h[:], rcv.GetPublicKeyBytes(), 3, 100, 10)
+	if err != nil {
+		t.Fatalf("could not make the contract: %v", err)
+	}
+	<-sent
+	n.Chain.Add(addBlk(ft))
+	// Looking for the contract's utxo doesn't reserve it
+	for i := 0; i < 2; i++ {
+		if u, _ := n.Chain.UTXOForScr(script.P2SH(rdm)); len(u) != 1 {
+			t.Fatalf("Expected: 1 contract utxo - Actual: %v", len(u))
+		}
+	}
+
+	if _, err := n.Wallet.ClmHTLC(rdm, []byte("preimage"), 10); err == nil {
+		t.Errorf("sender claimed its own contract")
+	}
+	rf, err := n.Wallet.RfndHTLC(rdm, 10)
+	if err != nil {
+		t.Fatalf("could not refund: %v", err)
+	}
+	<-sent
+	if err := n.TxErr(rf); err != nil {
+		t.Errorf("node rejected the refund: %v", err)
+	}
+	if err := n.BlkErr(addBlk(rf)); valerr.CodeOf(err) != valerr.NonFinal {
+		t.Errorf("Expected: %v - Actual: %v", valerr.NonFinal, err)
+	}
+	// A sender who mines can't take the contract back
+	// early by giving the refund an earlier lock time
+	early := tx.Deserialize(rf.Serialize())
+	early.LockTime = 0
+	o := n.Chain.GetUTXO(early.Inputs[0])
+	sig, _ := early.MkSig(0, o, n.Id, tx.SigHshAll)
+	sigB, _ := hex.DecodeString(sig)
+	early.Inputs[0].UnlockingScript = script.HTLCRfnd(sigB, rdm)
+	if err := n.BlkErr(addBlk(early)); valerr.CodeOf(err) != valerr.BadSig {
+		t.Errorf("Expected: %v - Actual: %v", valerr.BadSig, err)
+	}
+	for n.Chain.Length() < 4 {
+		n.Chain.Add(addBlk())
+	}
+	if err := n.BlkErr(addBlk(rf)); err != nil {
+		t.Errorf("refund after the lock time was rejected: %v", err)
+	}
+}
+
+// wtChnLen (waitChainLength) waits until every node's
+// main chain is at least l blocks long.
+func wtChnLen(t *testing.T, ns []*pkg.Node, l int) {
+	t.Helper()
+	for end := time.Now().Add(15 * time.Second); time.Now().Before(end); time.Sleep(100 * time.Millisecond) {
+		done := true
+		for _, n := range ns {
+			done = done && n.Chain.Length() >= l
+		}
+		if done {
+			return
+		}
+	}
+	t.Fatalf("main chains didn't reach a length of %v", l)
+}
+
+// TestHTLCSwap swaps money on one network for money on
+// another. Alice has money on network A and Bob has
+// money on network B. Alice locks money to Bob on A with
+// the hash of a secret, and Bob locks money to Alice on
+// B with the same hash. Alice claims on B, which
+// reveals the secret, which Bob then uses to claim on A.
+func TestHTLCSwap(t *testing.T) {
+	utils.SetDebug(true)
+
+	// Alice and Bob each have their own key, which they
+	// use on both networks. Network B has its own chain
+	// id, and its genesis block pays Bob.
+	aliceId, _ := id.LoadInSmplID(blockchain.GENPK, blockchain.GENPVK)
+	bobId, _ := id.CreateSimpleID()
+	bobPK := hex.EncodeToString(bobId.GetPublicKeyBytes())
+	mkNd := func(c *pkg.Config, i id.ID, chn uint32, genPK string) *pkg.Node {
+		c.CstmID, c.CstmIDObj = true, i
+		c.ChainID = chn
+		c.ChainConf.GenPK = genPK
+		return pkg.New(c)
+	}
+
+	// The genesis node of each network is Alice on A and
+	// Bob on B. The other node is the one the other person
+	// uses on that network.
+	alice := mkNd(GenConf(GetFreePort()), aliceId, pkg.MainNet, blockchain.GENPK)
+	bobA := mkNd(pkg.DefaultConfig(GetFreePort()), bobId, pkg.MainNet, blockchain.GENPK)
+	bob := mkNd(GenConf(GetFreePort()), bobId, pkg.MainNet+1, bobPK)
+	aliceB := mkNd(pkg.DefaultConfig(GetFreePort()), aliceId, pkg.MainNet+1, bobPK)
+	a := []*pkg.Node{alice, bobA}
+	b := []*pkg.Node{bob, aliceB}
+	StartCluster(a)
+	StartCluster(b)
+	ConnectCluster(a)
+	ConnectCluster(b)
+	alice.StartMiner()
+	bob.StartMiner()
+	time.Sleep(1 * time.Second)
+	if err := aliceB.ConnectToPeer(alice.Addr); err == nil {
+		t.Fatalf("nodes on different networks peered")
+	}
+	AsrtBal(t, aliceB, 0)
+	AsrtBal(t, bobA, 0)
+
+	secret := []byte("swap secret")
+	h := sha256.Sum256(secret)
+
+	// Alice's lock is longer than Bob's, so Bob can't wait
+	// out his contract and claim hers.
+	_, rdmA, err := alice.Wallet.MkHTLC(h[:], bobA.Id.GetPublicKeyBytes(), 40, 100, 20)
+	if err != nil {
+		t.Fatalf("Alice could not make her contract: %v", err)
+	}
+	wtChnLen(t, a, 2)
+	_, rdmB, err := bob.Wallet.MkHTLC(h[:], aliceB.Id.GetPublicKeyBytes(), 20, 100, 20)
+	if err != nil {
+		t.Fatalf("Bob could not make his contract: %v", err)
+	}
+	wtChnLen(t, b, 2)
+
+	if _, err := aliceB.Wallet.ClmHTLC(rdmB, secret, 20); err != nil {
+		t.Fatalf("Alice could not claim: %v", err)
+	}
+	wtChnLen(t, b, 3)
+
+	pre, ok := bob.Wallet.FndHTLCPre(h[:])
+	if !ok || !bytes.Equal(pre, secret) {
+		t.Fatalf("Bob didn't find the secret on network B")
+	}
+	if _, err := bobA.Wallet.ClmHTLC(rdmA, pre, 20); err != nil {
+		t.Fatalf("Bob could not claim: %v", err)
+	}
+	wtChnLen(t, a, 3)
+
+	AsrtBal(t, bobA, 80)
+	AsrtBal(t, aliceB, 80)
+	for i, c := range []struct {
+		n   *pkg.Node
+		rdm []byte
+	}{{alice, rdmA}, {bob, rdmB}} {
+		if u, _ := c.n.Chain.UTXOForScr(script.P2SH(c.rdm)); len(u) != 0 {
+			t.Errorf("contract on network %v wasn't spent", i)
+		}
+	}
+}
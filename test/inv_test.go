@@ -0,0 +1,93 @@
+package test
+
+import (
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"testing"
+	"time"
+)
+
+// TestInvRelay has a node announce a payment to a
+// cluster. Every node should get it, and each node's
+// peers should be known to have it afterwards.
+func TestInvRelay(t *testing.T) {
+	utils.SetDebug(true)
+	c := NewCluster(3)
+	StartCluster(c)
+	ConnectCluster(c)
+	time.Sleep(time.Second)
+
+	pt, err := c[0].Wallet.PayScr(script.P2PKH(c[1].Id.GetPublicKeyBytes()), 10, 5)
+	if err != nil {
+		t.Fatalf("could not pay: %v", err)
+	}
+	time.Sleep(2 * time.Second)
+	for _, n := range c {
+		ChkTxSeenLen(t, n, 1)
+		for _, p := range n.PeerDb.List() {
+			if !p.Known.Has(pt.Hash()) {
+				t.Errorf("%v does not know that %v has %v", n.Addr, p.Addr.Addr, pt.NameTag())
+			}
+		}
+	}
+}
+
+// TestGetDataInv checks that a node sends the announced
+// transactions and blocks it has, and leaves out the
+// ones it doesn't.
+func TestGetDataInv(t *testing.T) {
+	c := NewCluster(2)
+	StartCluster(c)
+	ConnectCluster(c)
+	time.Sleep(time.Second)
+	pt, err := c[0].Wallet.PayScr(script.P2PKH(c[1].Id.GetPublicKeyBytes()), 10, 5)
+	if err != nil {
+		t.Fatalf("could not pay: %v", err)
+	}
+	time.Sleep(time.Second)
+
+	gen := c[0].Chain.GetLastBlock().Hash()
+	res, err := address.New(c[0].Addr, 0).GetDataRPC(&proto.GetDataRequest{Items: []*proto.InvItem{
+		{Type: proto.InvType_TX, Hash: pt.Hash()},
+		{Type: proto.InvType_TX, Hash: "unknown"},
+		{Type: proto.InvType_BLOCK, Hash: gen},
+		{Type: proto.InvType_BLOCK, Hash: "unknown"},
+	}})
+	if err != nil {
+		t.Fatalf("could not get data: %v", err)
+	}
+	if len(res.Transactions) != 1 || len(res.Blocks) != 1 || res.Block != nil {
+		t.Fatalf("Expected: 1 tx and 1 block - Actual: %v txs and %v blocks", len(res.Transactions), len(res.Blocks))
+	}
+	ChkTxSeenLen(t, c[1], 1)
+}
+
+// TestAnnounceUnreached has a node announce to a peer
+// that can't be reached. The peer should not be known to
+// have the inventory until an announcement gets through.
+func TestAnnounceUnreached(t *testing.T) {
+	c := NewCluster(2)
+	StartCluster(c)
+	ConnectCluster(c)
+	time.Sleep(time.Second)
+	p := c[0].PeerDb.Get(c[1].Addr)
+	items := []*proto.InvItem{{Type: proto.InvType_TX, Hash: "ff"}}
+
+	c[1].PauseNetwork()
+	c[0].Announce(items)
+	time.Sleep(time.Second)
+	if p.Known.Has("ff") {
+		t.Errorf("unreached peer is known to have the inventory")
+	}
+
+	c[1].ResumeNetwork()
+	for end := time.Now().Add(10 * time.Second); !p.Known.Has("ff") && time.Now().Before(end); {
+		c[0].Announce(items)
+		time.Sleep(500 * time.Millisecond)
+	}
+	if !p.Known.Has("ff") {
+		t.Errorf("announcement to the peer never got through")
+	}
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"BrunoCoin/pkg"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"BrunoCoin/pkg/wallet"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAddHdr checks that a light chain only adds headers
+// on a known block with valid proof of work, and that the
+// headers without transactions become the main chain.
+func TestAddHdr(t *testing.T) {
+	bc := blockchain.New(blockchain.LightConfig())
+	gen := bc.GetLastBlock().Hash()
+	b1 := MkTstBlk(gen, 1)
+	if tip, err := bc.AddHdr(b1.Hdr); err != nil || !tip {
+		t.Fatalf("valid header was rejected: %v", err)
+	}
+	if tip, err := bc.AddHdr(b1.Hdr); err != nil || tip {
+		t.Errorf("known header was added again: %v", err)
+	}
+
+	bad := MkTstBlk(b1.Hash(), 2)
+	for bad.SatisfiesPOW(bad.Hdr.DiffTarg) {
+		bad.Hdr.Nonce++
+	}
+	cases := []struct {
+		h   block.Header
+		rsn string
+	}{
+		{MkTstBlk("unknown", 3).Hdr, "previous block"},
+		{bad.Hdr, "difficulty target"},
+	}
+	for _, c := range cases {
+		_, err := bc.AddHdr(c.h)
+		if err == nil || !strings.Contains(err.Error(), c.rsn) {
+			t.Errorf("Expected: %v - Actual: %v", c.rsn, err)
+		}
+	}
+	if bc.Length() != 2 || bc.GetLastBlock().Hash() != b1.Hash() {
+		t.Errorf("Expected: %v - Actual: %v", b1.NameTag(), bc.GetLastBlock().NameTag())
+	}
+}
+
+// TestLghtDrft (TestLightDrift) has a light node get a
+// header too far in the future, which it should reject
+// without adding.
+func TestLghtDrft(t *testing.T) {
+	n := pkg.New(pkg.LightConfig(GetFreePort()))
+	b := MkTstBlk(n.Chain.GetLastBlock().Hash(), 1)
+	b.Hdr.Timestamp = uint32(time.Now().Add(n.Conf.MxTmDrft + time.Hour).Unix())
+	for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+		b.Hdr.Nonce++
+	}
+	if err := n.HndlNwHdr(b, ""); valerr.CodeOf(err) != valerr.TimeTooNew {
+		t.Errorf("Expected: %v - Actual: %v", valerr.TimeTooNew, err)
+	}
+	if n.Chain.Length() != 1 {
+		t.Errorf("header from the future was added")
+	}
+}
+
+// TestLghtRllbck (TestLightRollback) has a light wallet
+// add a payment and a spend of it, then roll the spend
+// back after a reorganization took its block off of the
+// main chain.
+func TestLghtRllbck(t *testing.T) {
+	pk := blockchain.GENPK
+	l := wallet.NewLghtUTXO(pk)
+	pay := &tx.Transaction{
+		Inputs:  []*txi.TransactionInput{{TransactionHash: "aa", Amount: 50}},
+		Outputs: []*txo.TransactionOutput{{Amount: 50, LockingScript: pk}},
+	}
+	spnd := &tx.Transaction{
+		Inputs:  []*txi.TransactionInput{{TransactionHash: pay.Hash(), Amount: 50}},
+		Outputs: []*txo.TransactionOutput{{Amount: 20, LockingScript: pk}},
+	}
+	l.Add(pay, 1)
+	l.Add(spnd, 2)
+	l.Adv(1, []string{"b1", "b2"})
+	if l.Hght != 2 || l.Bal() != 20 {
+		t.Fatalf("Expected: height 2 and 20 - Actual: %v and %v", l.Hght, l.Bal())
+	}
+
+	main := map[uint32]string{1: "b1", 2: "fork"}
+	f := l.Frk(func(h uint32) string { return main[h] })
+	if f != 1 {
+		t.Fatalf("Expected: fork at 1 - Actual: %v", f)
+	}
+	off := l.Rllbck(f)
+	if len(off) != 1 || off[0].Hash() != spnd.Hash() {
+		t.Errorf("Expected: %v taken off - Actual: %v", spnd.NameTag(), off)
+	}
+	if l.Hght != 1 || l.Bal() != 50 {
+		t.Errorf("Expected: height 1 and 50 - Actual: %v and %v", l.Hght, l.Bal())
+	}
+	// The spend is likely to be mined again, so the
+	// payment can't be spent twice
+	if _, _, ok := l.GetUTXOForAmt(10, pk); ok {
+		t.Errorf("utxo of a rolled back spend was spent again")
+	}
+	// The spend can be added again at its new height
+	if !l.Add(spnd, 2) || l.Bal() != 20 {
+		t.Errorf("rolled back spend could not be added again")
+	}
+}
+
+// TestLghtNd has a full node pay a light node, which then
+// syncs its headers and finds the payment through merkle
+// proofs. The light node then pays the full node back
+// without having a utxo set.
+func TestLghtNd(t *testing.T) {
+	utils.SetDebug(true)
+	genNd := NewGenNd()
+	genNd.Conf.MnrConf.InitPOWD = utils.CalcPOWD(1)
+	// One proof at a time, so the wallet syncs in pages
+	genNd.Conf.PrfLim = 1
+	lght := pkg.New(pkg.LightConfig(GetFreePort()))
+	StartCluster([]*pkg.Node{genNd, lght})
+	genNd.StartMiner()
+
+	genNd.SendTx(50, 100, lght.Id.GetPublicKeyBytes())
+	time.Sleep(3 * time.Second)
+	ChkMnChnLen(t, genNd, 2)
+
+	lght.ConnectToPeer(genNd.Addr)
+	time.Sleep(time.Second)
+	if err := lght.Bootstrap(); err != nil {
+		t.Fatalf("could not bootstrap the light node: %v", err)
+	}
+	ChkMnChnLen(t, lght, 2)
+	if lght.Chain.GetLastBlock().Hash() != genNd.Chain.GetLastBlock().Hash() {
+		t.Errorf("light node is not on the full node's main chain")
+	}
+	AsrtBal(t, lght, 50)
+
+	lght.SendTx(20, 10, genNd.Id.GetPublicKeyBytes())
+	time.Sleep(3 * time.Second)
+	ChkMnChnLen(t, genNd, 3)
+	if err := lght.Bootstrap(); err != nil {
+		t.Fatalf("could not sync the light node: %v", err)
+	}
+	ChkMnChnLen(t, lght, 3)
+	AsrtBal(t, lght, 20)
+}
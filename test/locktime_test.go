@@ -0,0 +1,118 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/miner"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"testing"
+)
+
+// TestIsFinal checks lock times that are heights and
+// lock times that are timestamps.
+func TestIsFinal(t *testing.T) {
+	ts := script.LckTmThrsh + 1000
+	cases := []struct {
+		lck   uint32
+		h, tm uint32
+		final bool
+	}{
+		{0, 0, 0, true},
+		{5, 5, ts + 10, false},
+		{5, 6, 0, true},
+		{ts, 1000000, ts, false},
+		{ts, 0, ts + 1, true},
+	}
+	for i, c := range cases {
+		tt := &tx.Transaction{LockTime: c.lck}
+		if tt.IsFinal(c.h, c.tm) != c.final {
+			t.Errorf("case %v: Expected: %v - Actual: %v", i, c.final, !c.final)
+		}
+	}
+}
+
+// TestLckdTxBlk checks that a block can't have a
+// transaction whose lock time has not passed.
+func TestLckdTxBlk(t *testing.T) {
+	n := NewGenNd()
+	gen := n.Chain.GetLastBlock()
+	cb := gen.Transactions[0]
+	utxo := cb.Outputs[0]
+	mkBlk := func(lck uint32) *block.Block {
+		tt := &tx.Transaction{
+			Version: tx.EncVer,
+			Inputs: []*txi.TransactionInput{
+				{TransactionHash: cb.Hash(), OutputIndex: 0, Amount: utxo.Amount},
+			},
+			Outputs:  []*txo.TransactionOutput{{Amount: utxo.Amount - 1, LockingScript: blockchain.GENPK}},
+			LockTime: lck,
+		}
+		tt.Inputs[0].UnlockingScript, _ = tt.MkSig(0, utxo, n.Id, tx.SigHshAll)
+		mcb := &tx.Transaction{Version: tx.EncVer, Outputs: []*txo.TransactionOutput{{Amount: 1, LockingScript: "01"}}}
+		b := block.New(gen.Hash(), []*tx.Transaction{mcb, tt}, utils.CalcPOWD(1))
+		for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+			b.Hdr.Nonce++
+		}
+		return b
+	}
+
+	for _, lck := range []uint32{1, script.LckTmThrsh + 10} {
+		if err := n.BlkErr(mkBlk(lck)); valerr.CodeOf(err) != valerr.NonFinal {
+			t.Errorf("lock time %v: Expected: %v - Actual: %v", lck, valerr.NonFinal, err)
+		}
+	}
+	if err := n.BlkErr(mkBlk(0)); err != nil {
+		t.Errorf("block with a final transaction was rejected: %v", err)
+	}
+}
+
+// TestLckdTxPool checks that the miner leaves transactions
+// in the pool until they are final.
+func TestLckdTxPool(t *testing.T) {
+	i, _ := id.CreateSimpleID()
+	m := miner.New(miner.DefaultConfig(1), blockchain.DefaultConfig(), i)
+	mk := func(tag string, lck uint32) *tx.Transaction {
+		return &tx.Transaction{
+			Inputs:   []*txi.TransactionInput{{TransactionHash: tag, Amount: 20}},
+			Outputs:  []*txo.TransactionOutput{{Amount: 10, LockingScript: "01"}},
+			LockTime: lck,
+		}
+	}
+	free := mk("aa", 0)
+	byHt := mk("bb", 5)
+	byTm := mk("cc", script.LckTmThrsh+100)
+	for _, tt := range []*tx.Transaction{free, byHt, byTm} {
+		m.TxP.Add(tt)
+	}
+
+	has := func(p miner.MiningPool, tt *tx.Transaction) bool {
+		for _, pt := range p {
+			if pt.Hash() == tt.Hash() {
+				return true
+			}
+		}
+		return false
+	}
+	m.SetChnLen(2)
+	m.SetMedTm(script.LckTmThrsh)
+	if p := m.NewMiningPool(); !has(p, free) || has(p, byHt) || has(p, byTm) {
+		t.Errorf("mining pool has transactions that aren't final")
+	}
+	m.SetChnLen(6)
+	if p := m.NewMiningPool(); !has(p, byHt) || has(p, byTm) {
+		t.Errorf("height lock wasn't released at height 6")
+	}
+	m.SetMedTm(script.LckTmThrsh + 101)
+	if p := m.NewMiningPool(); len(p) != 3 {
+		t.Errorf("Expected: 3 - Actual: %v", len(p))
+	}
+	if m.TxP.Length() != 3 {
+		t.Errorf("locked transactions left the pool")
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"BrunoCoin/pkg"
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestMrklPrfBad checks that a merkle branch only proves
+// its own transaction at its own index.
+func TestMrklPrfBad(t *testing.T) {
+	n := NewGenNd()
+	txs := []*tx.Transaction{n.Chain.GetLastBlock().Transactions[0]}
+	for i := 0; i < 6; i++ {
+		txs = append(txs, CreateTx(n, n.Id.GetPublicKeyBytes(), uint32(i+1)))
+	}
+	hdr := block.Header{MrklRt: block.CalcMrklRt(txs)}
+	prf, _ := block.MrklPrf(txs, 2)
+	if !hdr.ChkMrklPrf(txs[2].Hash(), 2, prf) {
+		t.Fatalf("valid branch was rejected")
+	}
+	bad := append([]string{prf[0], txs[0].Hash()}, prf[2:]...)
+	cases := []struct {
+		h   string
+		i   int
+		prf []string
+	}{
+		{txs[3].Hash(), 2, prf},
+		{txs[2].Hash(), 3, prf},
+		{txs[2].Hash(), 2 + 1<<len(prf), prf},
+		{txs[2].Hash(), -1, prf},
+		{txs[2].Hash(), 2, prf[:len(prf)-1]},
+		{txs[2].Hash(), 2, bad},
+		{txs[2].Hash(), 2, append([]string{"zz"}, prf[1:]...)},
+	}
+	for i, c := range cases {
+		if hdr.ChkMrklPrf(c.h, c.i, c.prf) {
+			t.Errorf("case %v: bad branch was accepted", i)
+		}
+	}
+}
+
+// TestGetMrklPrf has a light client ask a node for the
+// proof that a payment was mined, and check it against
+// the block's header.
+func TestGetMrklPrf(t *testing.T) {
+	utils.SetDebug(true)
+	n := NewGenNd()
+	StartCluster([]*pkg.Node{n})
+	n.StartMiner()
+	rcv, _ := id.CreateSimpleID()
+	pt, err := n.Wallet.PayScr(script.P2PKH(rcv.GetPublicKeyBytes()), 10, 20)
+	if err != nil {
+		t.Fatalf("could not pay: %v", err)
+	}
+	for end := time.Now().Add(15 * time.Second); time.Now().Before(end); time.Sleep(100 * time.Millisecond) {
+		if _, _, _, ok := n.Chain.FndTx(pt.Hash()); ok {
+			break
+		}
+	}
+
+	a := address.New(n.Addr, 0)
+	p, err := a.GetMerkleProofRPC(&proto.GetMerkleProofRequest{TxHash: pt.Hash()})
+	if err != nil {
+		t.Fatalf("could not get the proof: %v", err)
+	}
+	hdr := block.DeserializeHdr(p.Header)
+	b := n.Chain.Get(hdr.PrvBlkHsh)
+	if b == nil || n.Chain.IndexOf(b.Hash())+1 != int(p.Height) {
+		t.Errorf("proof is not for a block on the main chain")
+	}
+	if tt := tx.Deserialize(p.Transaction); tt.Hash() != pt.Hash() {
+		t.Errorf("Expected: %v - Actual: %v", pt.NameTag(), tt.NameTag())
+	}
+	if !hdr.ChkMrklPrf(pt.Hash(), int(p.Index), p.Branch) {
+		t.Errorf("proof doesn't lead to the merkle root of its block")
+	}
+
+	_, err = a.GetMerkleProofRPC(&proto.GetMerkleProofRequest{TxHash: "aa"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected: %v - Actual: %v", codes.NotFound, err)
+	}
+
+	// The genesis node's wallet matches a transaction on
+	// every block, so each page stops after one block
+	n.Conf.PrfLim = 1
+	pk := n.Id.GetPublicKeyBytes()
+	lcks := []string{hex.EncodeToString(pk), script.P2PK(pk), script.P2PKH(pk)}
+	var hght uint32
+	for pg := 0; pg < 2; pg++ {
+		res, err := a.GetMerkleProofsRPC(&proto.GetMerkleProofsRequest{LockingScripts: lcks, FromHeight: hght})
+		if err != nil {
+			t.Fatalf("could not get the proofs: %v", err)
+		}
+		if len(res.Proofs) == 0 || res.Proofs[len(res.Proofs)-1].Height != res.Height || res.Height != hght {
+			t.Errorf("page from %v did not stop after its first block: %v proofs up to %v", hght, len(res.Proofs), res.Height)
+		}
+		hght = res.Height + 1
+	}
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/wallet"
+	"testing"
+)
+
+// TestMltSigScr checks which signatures unlock a 2-of-3
+// multisig script, both as the locking script and as
+// the redeem script of a pay to script hash output.
+func TestMltSigScr(t *testing.T) {
+	ids := make([]id.ID, 3)
+	pks := make([][]byte, 3)
+	for i := range ids {
+		ids[i], _ = id.CreateSimpleID()
+		pks[i] = ids[i].GetPublicKeyBytes()
+	}
+	rdm, err := script.MltSig(2, pks)
+	if err != nil {
+		t.Fatalf("could not make the script: %v", err)
+	}
+	if _, err := script.MltSig(4, pks); err == nil {
+		t.Errorf("made a 4-of-3 script")
+	}
+
+	for _, p2sh := range []bool{false, true} {
+		tt, o := mkScrTx(wallet.MsLck(rdm, p2sh))
+		var r []byte
+		if p2sh {
+			r = rdm
+		}
+		s := func(i int) []byte { return sigB(t, tt, o, ids[i]) }
+		cases := []struct {
+			sigs  [][]byte
+			valid bool
+		}{
+			{[][]byte{s(0), s(1)}, true},
+			{[][]byte{s(0), s(2)}, true},
+			{[][]byte{s(1), s(2)}, true},
+			{[][]byte{s(1), s(0)}, false},
+			{[][]byte{s(0), s(0)}, false},
+			{[][]byte{s(0)}, false},
+		}
+		for i, c := range cases {
+			tt.Inputs[0].UnlockingScript = script.MltSigUnlck(c.sigs, r)
+			if err := tt.UnlckErr(0, o); (err == nil) != c.valid {
+				t.Errorf("p2sh %v case %v: Expected: %v - Actual: %v", p2sh, i, c.valid, err)
+			}
+		}
+	}
+}
+
+// TestMltSigWallet has two of three co-signers spend
+// multisig utxo through their wallets, and checks that
+// the node accepts the finished transaction.
+func TestMltSigWallet(t *testing.T) {
+	n := NewGenNd()
+	ids := make([]id.ID, 3)
+	pks := make([][]byte, 3)
+	for i := range ids {
+		ids[i], _ = id.CreateSimpleID()
+		pks[i] = ids[i].GetPublicKeyBytes()
+	}
+	rdm, _ := script.MltSig(2, pks)
+	cb := tx.Deserialize(proto.NewTx(tx.EncVer, nil,
+		[]*proto.TransactionOutput{proto.NewTxOutpt(10, wallet.MsLck(rdm, true))}, 0))
+	b := block.New(n.Chain.GetLastBlock().Hash(), []*tx.Transaction{cb}, utils.CalcPOWD(1))
+	for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+		b.Hdr.Nonce++
+	}
+	n.Chain.Add(b)
+
+	wa := wallet.New(wallet.DefaultConfig(), ids[0], n.Chain)
+	wc := wallet.New(wallet.DefaultConfig(), ids[2], n.Chain)
+	dest, _ := id.CreateSimpleID()
+	s, err := wa.MkMsSpnd(rdm, true, &wallet.TxReq{PubK: dest.GetPublicKeyBytes(), Amt: 6, Fee: 1})
+	if err != nil {
+		t.Fatalf("could not make the spend: %v", err)
+	}
+	if _, err := wa.MkMsSpnd(rdm, true, &wallet.TxReq{PubK: dest.GetPublicKeyBytes(), Amt: 1}); err == nil {
+		t.Errorf("utxo of a pending spend was spent again")
+	}
+
+	if _, err := wa.SignMs(s); err != nil {
+		t.Fatalf("co-signer could not sign: %v", err)
+	}
+	if s.Ready() {
+		t.Errorf("spend with 1 of 2 signatures is ready")
+	}
+	if _, err := wa.FnlzMs(s); err == nil {
+		t.Errorf("spend with 1 of 2 signatures was finalized")
+	}
+	stranger := wallet.New(wallet.DefaultConfig(), dest, n.Chain)
+	if _, err := stranger.SignMs(s); err == nil {
+		t.Errorf("wallet that isn't a co-signer signed")
+	}
+
+	// The other co-signer loads the spend on its own and
+	// sends back its signatures
+	cs, err := wc.LdMsSpnd(s.Tx, rdm, true)
+	if err != nil {
+		t.Fatalf("co-signer could not load the spend: %v", err)
+	}
+	sigs, err := wc.SignMs(cs)
+	if err != nil {
+		t.Fatalf("co-signer could not sign: %v", err)
+	}
+	for i, sig := range sigs {
+		if err := s.AddSig(i, sig); err != nil {
+			t.Fatalf("could not add the co-signer's signature: %v", err)
+		}
+	}
+	if !s.Ready() {
+		t.Fatalf("spend with 2 of 2 signatures isn't ready")
+	}
+
+	sent := make(chan *tx.Transaction, 1)
+	go func() { sent <- <-wa.SendTx }()
+	ft, err := wa.FnlzMs(s)
+	if err != nil {
+		t.Fatalf("could not finalize: %v", err)
+	}
+	if st := <-sent; st.Hash() != ft.Hash() {
+		t.Errorf("finalized transaction wasn't sent")
+	}
+	if err := n.TxErr(ft); err != nil {
+		t.Errorf("node rejected the multisig spend: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/blockchain"
+	"testing"
+)
+
+// TestOrphanPool checks that orphans are found by the
+// block they are missing, that the oldest orphan is
+// evicted when the pool is full, and that the missing
+// ancestor of a chain of orphans is found.
+func TestOrphanPool(t *testing.T) {
+	op := blockchain.NewOrphanPool(2)
+	b1 := MkTstBlk("missing", 1)
+	b2 := MkTstBlk(b1.Hash(), 2)
+	b3 := MkTstBlk(b2.Hash(), 3)
+	if !op.Add(b2) || op.Add(b2) {
+		t.Fatalf("an orphan should only be added once")
+	}
+	op.Add(b3)
+	if h := op.Missing(b3); h != b1.Hash() {
+		t.Errorf("Expected: %v - Actual: %v", b1.Hash(), h)
+	}
+	op.Add(b1)
+	if op.Len() != 2 || op.Has(b2.Hash()) {
+		t.Errorf("oldest orphan was not evicted")
+	}
+	if h := op.Missing(b3); h != b2.Hash() {
+		t.Errorf("Expected: %v - Actual: %v", b2.Hash(), h)
+	}
+	if c := op.Rmv("missing"); len(c) != 1 || c[0].Hash() != b1.Hash() {
+		t.Errorf("wrong orphans removed")
+	}
+	if op.Len() != 1 {
+		t.Errorf("Expected: %v - Actual: %v", 1, op.Len())
+	}
+}
+
+// TestOrphanConnects sends a node two blocks before
+// the block they build on. Both should wait in the
+// orphan pool and be added to the chain once their
+// ancestor arrives.
+func TestOrphanConnects(t *testing.T) {
+	n := NewGenNd()
+	gen := n.Chain.GetLastBlock().Hash()
+	b1 := MkTstBlk(gen, 1)
+	b2 := MkTstBlk(b1.Hash(), 2)
+	b3 := MkTstBlk(b2.Hash(), 3)
+	for _, b := range []*block.Block{b3, b2} {
+		if err := n.HndlNwBlk(b, ""); err != nil {
+			t.Fatalf("orphan was rejected: %v", err)
+		}
+	}
+	if n.Orphans.Len() != 2 || n.Chain.Length() != 1 {
+		t.Fatalf("orphans should not be on the chain yet")
+	}
+	if err := n.HndlNwBlk(b1, ""); err != nil {
+		t.Fatalf("block was rejected: %v", err)
+	}
+	ChkMnChnLen(t, n, 4)
+	if n.Orphans.Len() != 0 {
+		t.Errorf("Expected: %v - Actual: %v", 0, n.Orphans.Len())
+	}
+	if n.Chain.GetLastBlock().Hash() != b3.Hash() {
+		t.Errorf("orphans were not connected in order")
+	}
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/blockchain"
+	"testing"
+)
+
+// TestReorgHeavierFork builds a main chain of two
+// blocks and a competing fork of three blocks from
+// the genesis block. Once the fork has more work, it
+// should become the main chain and the utxo of the
+// old main chain should be gone.
+func TestReorgHeavierFork(t *testing.T) {
+	bc := blockchain.New(blockchain.DefaultConfig())
+	gen := bc.GetLastBlock().Hash()
+	b1 := MkTstBlk(gen, 1)
+	b2 := MkTstBlk(b1.Hash(), 2)
+	if u := bc.Add(b1); u == nil || len(u.Connected) != 1 || len(u.Disconnected) != 0 {
+		t.Fatalf("extending the main chain should connect exactly one block")
+	}
+	bc.Add(b2)
+
+	f1 := MkTstBlk(gen, 11)
+	f2 := MkTstBlk(f1.Hash(), 12)
+	f3 := MkTstBlk(f2.Hash(), 13)
+	if u := bc.Add(f1); u != nil {
+		t.Errorf("a lighter fork should not change the main chain")
+	}
+	if u := bc.Add(f2); u != nil {
+		t.Errorf("a fork with equal work should not change the main chain")
+	}
+	u := bc.Add(f3)
+	if u == nil {
+		t.Fatalf("heavier fork did not become the main chain")
+	}
+	if len(u.Disconnected) != 2 || u.Disconnected[0].Hash() != b2.Hash() || u.Disconnected[1].Hash() != b1.Hash() {
+		t.Errorf("wrong disconnected blocks")
+	}
+	if len(u.Connected) != 3 || u.Connected[0].Hash() != f1.Hash() || u.Connected[2].Hash() != f3.Hash() {
+		t.Errorf("wrong connected blocks")
+	}
+	if bc.GetLastBlock().Hash() != f3.Hash() || bc.Length() != 4 {
+		t.Errorf("main chain does not end with the fork")
+	}
+	old := &txi.TransactionInput{TransactionHash: b1.Transactions[0].Hash(), OutputIndex: 0}
+	if bc.GetUTXO(old) != nil {
+		t.Errorf("utxo from the old main chain was not rolled back")
+	}
+	nw := &txi.TransactionInput{TransactionHash: f3.Transactions[0].Hash(), OutputIndex: 0}
+	if bc.GetUTXO(nw) == nil {
+		t.Errorf("utxo from the new main chain is missing")
+	}
+}
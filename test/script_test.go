@@ -0,0 +1,212 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+// mkScrTx makes a transaction spending a single output
+// locked by lck.
+func mkScrTx(lck string) (*tx.Transaction, *txo.TransactionOutput) {
+	o := &txo.TransactionOutput{Amount: 50, LockingScript: lck}
+	t := &tx.Transaction{
+		Version: tx.EncVer,
+		Inputs: []*txi.TransactionInput{
+			{TransactionHash: "aa", OutputIndex: 0, Amount: 50},
+		},
+		Outputs: []*txo.TransactionOutput{{Amount: 40, LockingScript: "01"}},
+	}
+	return t, o
+}
+
+// sigB signs input 0 of t and returns the signature as bytes.
+func sigB(t *testing.T, tt *tx.Transaction, o *txo.TransactionOutput, i id.ID) []byte {
+	sig, err := tt.MkSig(0, o, i, tx.SigHshAll)
+	if err != nil {
+		t.Fatalf("could not sign: %v", err)
+	}
+	b, _ := hex.DecodeString(sig)
+	return b
+}
+
+// TestScrTemplates checks that the standard scripts are
+// only unlocked by the right signatures and keys.
+func TestScrTemplates(t *testing.T) {
+	a, _ := id.CreateSimpleID()
+	b, _ := id.CreateSimpleID()
+	aPK := a.GetPublicKeyBytes()
+
+	// Legacy public key and pay to public key
+	for _, lck := range []string{hex.EncodeToString(aPK), script.P2PK(aPK)} {
+		tt, o := mkScrTx(lck)
+		sig := hex.EncodeToString(sigB(t, tt, o, a))
+		tt.Inputs[0].UnlockingScript, _ = script.Unlck(lck, sig, aPK)
+		if err := tt.UnlckErr(0, o); err != nil {
+			t.Errorf("signature from the key didn't unlock: %v", err)
+		}
+		bSig := hex.EncodeToString(sigB(t, tt, o, b))
+		tt.Inputs[0].UnlockingScript, _ = script.Unlck(lck, bSig, aPK)
+		if tt.IsUnlckd(0, o) {
+			t.Errorf("signature from another key unlocked")
+		}
+		if !script.Pays(lck, hex.EncodeToString(aPK)) {
+			t.Errorf("script doesn't pay its key")
+		}
+	}
+
+	// Pay to public key hash
+	lck := script.P2PKH(aPK)
+	tt, o := mkScrTx(lck)
+	tt.Inputs[0].UnlockingScript = script.New().Push(sigB(t, tt, o, a)).Push(aPK).Hex()
+	if err := tt.UnlckErr(0, o); err != nil {
+		t.Errorf("signature and key didn't unlock: %v", err)
+	}
+	tt.Inputs[0].UnlockingScript = script.New().Push(sigB(t, tt, o, b)).Push(b.GetPublicKeyBytes()).Hex()
+	if tt.IsUnlckd(0, o) {
+		t.Errorf("key with another hash unlocked")
+	}
+	if script.Pays(lck, hex.EncodeToString(b.GetPublicKeyBytes())) {
+		t.Errorf("script pays a key it doesn't lock to")
+	}
+
+	// Unlocking scripts can only push
+	tt.Inputs[0].UnlockingScript = script.New().Push(sigB(t, tt, o, a)).Push(aPK).Op(script.OpNop).Hex()
+	if tt.IsUnlckd(0, o) {
+		t.Errorf("unlocking script with an opcode unlocked")
+	}
+}
+
+func hsh(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+// TestScrP2SH checks that pay to script hash runs the
+// redeem script.
+func TestScrP2SH(t *testing.T) {
+	a, _ := id.CreateSimpleID()
+	b, _ := id.CreateSimpleID()
+	secret := []byte("secret")
+
+	// Either a's signature, or b's signature and the secret
+	rdm := script.New().
+		Op(script.OpIf).
+		Push(a.GetPublicKeyBytes()).
+		Op(script.OpElse).
+		Op(script.OpSha256).Push(hsh(secret)).Op(script.OpEqlVfy).
+		Push(b.GetPublicKeyBytes()).
+		Op(script.OpEndIf, script.OpChkSig).Bytes()
+	lck := script.P2SH(rdm)
+
+	tt, o := mkScrTx(lck)
+	cases := []struct {
+		unlck *script.Bldr
+		valid bool
+	}{
+		{script.New().Push(sigB(t, tt, o, a)).Op(script.Op1).Push(rdm), true},
+		{script.New().Push(sigB(t, tt, o, b)).Op(script.Op1).Push(rdm), false},
+		{script.New().Push(sigB(t, tt, o, b)).Push(secret).Op(script.OpFalse).Push(rdm), true},
+		{script.New().Push(sigB(t, tt, o, b)).Push([]byte("guess")).Op(script.OpFalse).Push(rdm), false},
+		{script.New().Push(sigB(t, tt, o, a)).Op(script.Op1).Push(append(rdm, script.OpNop)), false},
+		{script.New().Push(sigB(t, tt, o, a)).Op(script.Op1), false},
+	}
+	for i, c := range cases {
+		tt.Inputs[0].UnlockingScript = c.unlck.Hex()
+		if err := tt.UnlckErr(0, o); (err == nil) != c.valid {
+			t.Errorf("case %v: Expected: %v - Actual: %v", i, c.valid, err)
+		}
+	}
+}
+
+// TestScrLimits checks scripts that can never unlock.
+func TestScrLimits(t *testing.T) {
+	cases := []string{
+		script.New().Op(script.OpRet).Hex(),
+		script.New().Op(script.Op1, script.OpIf).Hex(),
+		script.New().Op(script.OpEndIf, script.Op1).Hex(),
+		script.New().Op(0xff).Hex(),
+		"4c05aa",
+		"zz",
+	}
+	for i, lck := range cases {
+		tt, o := mkScrTx(lck)
+		if err := tt.UnlckErr(0, o); err == nil {
+			t.Errorf("case %v: script %v unlocked", i, lck)
+		}
+	}
+	tt, o := mkScrTx(script.New().Op(script.Op1).Hex())
+	if err := tt.UnlckErr(0, o); err != nil {
+		t.Errorf("true script didn't unlock: %v", err)
+	}
+}
+
+// TestBlkScr checks that a block is rejected if any of
+// its inputs doesn't unlock the utxo it spends, whether
+// the signature is forged or a hash time locked
+// contract is refunded before its lock time.
+func TestBlkScr(t *testing.T) {
+	n := NewGenNd()
+	gid, _ := id.LoadInSmplID(blockchain.GENPK, blockchain.GENPVK)
+	rcv, _ := id.CreateSimpleID()
+	mkBlk := func(txs ...*tx.Transaction) *block.Block {
+		cb := tx.Deserialize(proto.NewTx(0, nil,
+			[]*proto.TransactionOutput{proto.NewTxOutpt(1, blockchain.GENPK)}, uint32(n.Chain.Length())))
+		b := block.New(n.Chain.GetLastBlock().Hash(), append([]*tx.Transaction{cb}, txs...), utils.CalcPOWD(1))
+		for !b.SatisfiesPOW(b.Hdr.DiffTarg) {
+			b.Hdr.Nonce++
+		}
+		return b
+	}
+	spnd := func(src *tx.Transaction, idx uint32, lckTm uint32, outs ...*txo.TransactionOutput) *tx.Transaction {
+		return &tx.Transaction{
+			Version:  tx.EncVer,
+			Inputs:   []*txi.TransactionInput{{TransactionHash: src.Hash(), OutputIndex: idx, Amount: src.Outputs[idx].Amount}},
+			Outputs:  outs,
+			LockTime: lckTm,
+		}
+	}
+
+	// The genesis utxo pays into a contract, with change
+	gen := n.Chain.GetLastBlock().Transactions[0]
+	rdm := script.HTLC(script.HTLCTrms{Hsh: hsh([]byte("preimage")), Rcv: rcv.GetPublicKeyBytes(),
+		Snd: gid.GetPublicKeyBytes(), Lck: 50})
+	fnd := spnd(gen, 0, 0,
+		&txo.TransactionOutput{Amount: 100, LockingScript: script.P2SH(rdm)},
+		&txo.TransactionOutput{Amount: gen.Outputs[0].Amount - 110, LockingScript: blockchain.GENPK})
+	fnd.Inputs[0].UnlockingScript, _ = fnd.MkSig(0, gen.Outputs[0], gid, tx.SigHshAll)
+	b := mkBlk(fnd)
+	if err := n.BlkErr(b); err != nil {
+		t.Fatalf("block paying the contract was rejected: %v", err)
+	}
+	n.Chain.Add(b)
+
+	// Spending the change takes the genesis key's signature
+	chg := spnd(fnd, 1, 0, &txo.TransactionOutput{Amount: 10, LockingScript: blockchain.GENPK})
+	chg.Inputs[0].UnlockingScript, _ = chg.MkSig(0, fnd.Outputs[1], rcv, tx.SigHshAll)
+	if err := n.BlkErr(mkBlk(chg)); valerr.CodeOf(err) != valerr.BadSig {
+		t.Errorf("forged input: Expected: %v - Actual: %v", valerr.BadSig, err)
+	}
+	chg.Inputs[0].UnlockingScript, _ = chg.MkSig(0, fnd.Outputs[1], gid, tx.SigHshAll)
+	if err := n.BlkErr(mkBlk(chg)); err != nil {
+		t.Errorf("signed input was rejected: %v", err)
+	}
+
+	// A refund that is final at the block's height still
+	// has to carry the contract's lock time
+	rf := spnd(fnd, 0, 0, &txo.TransactionOutput{Amount: 90, LockingScript: blockchain.GENPK})
+	rf.Inputs[0].UnlockingScript = script.HTLCRfnd(sigB(t, rf, fnd.Outputs[0], gid), rdm)
+	if err := n.BlkErr(mkBlk(rf)); valerr.CodeOf(err) != valerr.BadSig {
+		t.Errorf("early refund: Expected: %v - Actual: %v", valerr.BadSig, err)
+	}
+}
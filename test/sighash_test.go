@@ -0,0 +1,201 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/block/tx/txo"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/script"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// mkSigTx makes a transaction spending two outputs
+// owned by the genesis key, with two outputs of its own.
+func mkSigTx() (*tx.Transaction, []*txo.TransactionOutput) {
+	spnt := []*txo.TransactionOutput{
+		{Amount: 30, LockingScript: blockchain.GENPK},
+		{Amount: 20, LockingScript: blockchain.GENPK},
+	}
+	t := &tx.Transaction{
+		Inputs: []*txi.TransactionInput{
+			{TransactionHash: "aa", OutputIndex: 0, Amount: 30},
+			{TransactionHash: "bb", OutputIndex: 1, Amount: 20},
+		},
+		Outputs: []*txo.TransactionOutput{
+			{Amount: 40, LockingScript: "01"},
+			{Amount: 5, LockingScript: "02"},
+		},
+	}
+	return t, spnt
+}
+
+// TestSigHshFlags checks which changes to a transaction
+// each sighash flag allows after signing.
+func TestSigHshFlags(t *testing.T) {
+	gid, _ := id.LoadInSmplID(blockchain.GENPK, blockchain.GENPVK)
+	cases := []struct {
+		ht    byte
+		chg   func(t *tx.Transaction)
+		valid bool
+	}{
+		{tx.SigHshAll, func(t *tx.Transaction) {}, true},
+		{tx.SigHshAll, func(t *tx.Transaction) { t.Outputs[0].LockingScript = "03" }, false},
+		{tx.SigHshNone, func(t *tx.Transaction) { t.Outputs[0].LockingScript = "03" }, true},
+		{tx.SigHshNone, func(t *tx.Transaction) { t.Inputs[1].OutputIndex = 2 }, false},
+		{tx.SigHshSingle, func(t *tx.Transaction) { t.Outputs[1].Amount = 1 }, true},
+		{tx.SigHshSingle, func(t *tx.Transaction) { t.Outputs[0].Amount = 1 }, false},
+		{tx.SigHshAll | tx.SigHshACP, func(t *tx.Transaction) { t.Inputs[1].Amount = 25 }, true},
+		{tx.SigHshAll, func(t *tx.Transaction) { t.Inputs[1].Amount = 25 }, false},
+	}
+	for i, c := range cases {
+		tt, spnt := mkSigTx()
+		sig, err := tt.MkSig(0, spnt[0], gid, c.ht)
+		if err != nil {
+			t.Fatalf("could not sign: %v", err)
+		}
+		tt.Inputs[0].UnlockingScript = sig
+		c.chg(tt)
+		if tt.IsUnlckd(0, spnt[0]) != c.valid {
+			t.Errorf("case %v: Expected: %v - Actual: %v", i, c.valid, !c.valid)
+		}
+	}
+
+	// A signature can't unlock a different output
+	tt, spnt := mkSigTx()
+	tt.Inputs[0].UnlockingScript, _ = tt.MkSig(0, spnt[0], gid, tx.SigHshAll)
+	if tt.IsUnlckd(0, spnt[1]) {
+		t.Errorf("signature unlocked an output it didn't sign")
+	}
+	// Signatures that don't cover the transaction don't work
+	oh, _ := hex.DecodeString(spnt[0].Hash())
+	tt.Inputs[0].UnlockingScript, _ = utils.Sign(gid.GetPrivateKey(), oh)
+	if tt.IsUnlckd(0, spnt[0]) {
+		t.Errorf("signature without a sighash unlocked the output")
+	}
+}
+
+// TestSigForged checks that a node rejects a transaction
+// spending the genesis utxo with a signature from another
+// key, or with the genesis key's signature of another
+// transaction.
+func TestSigForged(t *testing.T) {
+	n := NewGenNd()
+	cb := n.Chain.GetLastBlock().Transactions[0]
+	o := cb.Outputs[0]
+	other, _ := id.CreateSimpleID()
+
+	tt := mkSpnd(cb, o.Amount-10, blockchain.GENPK)
+	tt.Inputs[0].UnlockingScript, _ = tt.MkSig(0, o, other, tx.SigHshAll)
+	if err := n.TxErr(tt); valerr.CodeOf(err) != valerr.BadSig {
+		t.Errorf("other key: Expected: %v - Actual: %v", valerr.BadSig, err)
+	}
+
+	signed := mkSpnd(cb, o.Amount-20, blockchain.GENPK)
+	signed.Inputs[0].UnlockingScript, _ = signed.MkSig(0, o, n.Id, tx.SigHshAll)
+	tt.Inputs[0].UnlockingScript = signed.Inputs[0].UnlockingScript
+	if err := n.TxErr(tt); valerr.CodeOf(err) != valerr.BadSig {
+		t.Errorf("replayed signature: Expected: %v - Actual: %v", valerr.BadSig, err)
+	}
+	if err := n.TxErr(signed); err != nil {
+		t.Errorf("signed transaction was rejected: %v", err)
+	}
+}
+
+// TestRnwSig has the wallet send a payment out again
+// after it wasn't mined. The payment gets a new lock
+// time, so its inputs have to be signed again.
+func TestRnwSig(t *testing.T) {
+	n := NewGenNd()
+	sent := make(chan *tx.Transaction, 10)
+	go func() {
+		for {
+			sent <- <-n.Wallet.SendTx
+		}
+	}()
+	rcv, _ := id.CreateSimpleID()
+	pt, err := n.Wallet.PayScr(script.P2PKH(rcv.GetPublicKeyBytes()), 10, 20)
+	if err != nil {
+		t.Fatalf("could not pay: %v", err)
+	}
+	<-sent
+
+	b := MkTstBlk(n.Chain.GetLastBlock().Hash(), 1)
+	n.Chain.Add(b)
+	for i := uint32(0); i <= n.Wallet.Conf.TxRplyThresh; i++ {
+		n.Wallet.HndlBlk(b)
+	}
+	select {
+	case rt := <-sent:
+		if rt.Hash() == pt.Hash() || rt.LockTime != 1 {
+			t.Errorf("payment was sent out again unchanged")
+		}
+		if err := n.TxErr(rt); err != nil {
+			t.Errorf("payment sent out again is invalid: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("payment was not sent out again")
+	}
+}
+
+// TestRnwCnfl (TestRenewConflict) has the wallet send a
+// payment out again while the first one is still in the
+// node's pool. The renewed payment conflicts with the
+// first one, but the utxo it spends is still spent by
+// the pool, so the wallet must not pay with it again.
+func TestRnwCnfl(t *testing.T) {
+	n := NewGenNd()
+	sent := make(chan *tx.Transaction, 10)
+	go func() {
+		for {
+			sent <- <-n.Wallet.SendTx
+		}
+	}()
+	rcv, _ := id.CreateSimpleID()
+	lck := script.P2PKH(rcv.GetPublicKeyBytes())
+	pt, err := n.Wallet.PayScr(lck, 10, 20)
+	if err != nil {
+		t.Fatalf("could not pay: %v", err)
+	}
+	<-sent
+	if err := n.Mnr.TxP.Add(pt); err != nil {
+		t.Fatalf("payment was not pooled: %v", err)
+	}
+
+	b := MkTstBlk(n.Chain.GetLastBlock().Hash(), 1)
+	n.Chain.Add(b)
+	for i := uint32(0); i <= n.Wallet.Conf.TxRplyThresh; i++ {
+		n.Wallet.HndlBlk(b)
+	}
+	var rt *tx.Transaction
+	select {
+	case rt = <-sent:
+	case <-time.After(time.Second):
+		t.Fatalf("payment was not sent out again")
+	}
+	if err := n.Mnr.TxP.Add(rt); err == nil {
+		t.Fatalf("renewed payment did not conflict with the pooled one")
+	}
+	n.HndlCnfl(<-n.Mnr.TxP.Conflicts)
+	if !n.Wallet.LmnlTxs.Has(pt) {
+		t.Errorf("wallet stopped waiting on the pooled payment")
+	}
+
+	spnt := make(map[string]bool)
+	for _, i := range pt.Inputs {
+		spnt[txo.MkTXOLoc(i.TransactionHash, i.OutputIndex)] = true
+	}
+	nt, err := n.Wallet.PayScr(lck, 10, 20)
+	if err != nil {
+		return
+	}
+	for _, i := range nt.Inputs {
+		if spnt[txo.MkTXOLoc(i.TransactionHash, i.OutputIndex)] {
+			t.Errorf("new payment spends utxo of the pooled payment")
+		}
+	}
+}
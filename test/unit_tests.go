@@ -18,12 +18,9 @@ func CreateTx(n *pkg.Node, toPK []byte, factor uint32) *tx.Transaction {
 		LockingScript: hex.EncodeToString(n.Id.GetPublicKeyBytes()),
 	}
 
-	sig1, _ := inUTXO1.MkSig(n.Id)
-
 	TxI1 := &txi.TransactionInput{
 		TransactionHash: inUTXO1.Hash(),
 		OutputIndex:     0,
-		UnlockingScript: sig1,
 		Amount:          inUTXO1.Amount,
 	}
 
@@ -32,12 +29,9 @@ func CreateTx(n *pkg.Node, toPK []byte, factor uint32) *tx.Transaction {
 		LockingScript: hex.EncodeToString(n.Id.GetPublicKeyBytes()),
 	}
 
-	sig2, _ := inUTXO2.MkSig(n.Id)
-
 	TxI2 := &txi.TransactionInput{
 		TransactionHash: inUTXO2.Hash(),
 		OutputIndex:     1,
-		UnlockingScript: sig2,
 		Amount:          inUTXO2.Amount,
 	}
 
@@ -51,12 +45,15 @@ func CreateTx(n *pkg.Node, toPK []byte, factor uint32) *tx.Transaction {
 		LockingScript: hex.EncodeToString(n.Id.GetPublicKeyBytes()),
 	}
 
-	return &tx.Transaction{
+	t := &tx.Transaction{
 		Version:  n.Wallet.Conf.TxVer,
 		Inputs:   []*txi.TransactionInput{TxI1, TxI2},
 		Outputs:  []*txo.TransactionOutput{TxO1, TxO2},
 		LockTime: n.Wallet.Conf.DefLckTm,
 	}
+	TxI1.UnlockingScript, _ = t.MkSig(0, inUTXO1, n.Id, tx.SigHshAll)
+	TxI2.UnlockingScript, _ = t.MkSig(1, inUTXO2, n.Id, tx.SigHshAll)
+	return t
 }
 
 func TestNoDupsChkTxs(t *testing.T) {
@@ -292,7 +289,7 @@ func TestHndlMinerActive(t *testing.T) {
 	genNode := NewGenNd()
 	genNode.Start()
 
-	m := miner.New(genNode.Mnr.Conf, genNode.Id)
+	m := miner.New(genNode.Mnr.Conf, genNode.Conf.ChainConf, genNode.Id)
 	m.Active.Store(true)
 
 	tx := CreateTx(genNode, genNode.Id.GetPublicKeyBytes(), 10)
@@ -311,7 +308,7 @@ func TestHndlMinerInactive(t *testing.T) {
 
 	genNode.Start()
 
-	m := miner.New(genNode.Mnr.Conf, genNode.Id)
+	m := miner.New(genNode.Mnr.Conf, genNode.Conf.ChainConf, genNode.Id)
 	m.Active.Store(false)
 
 	tx := CreateTx(genNode, genNode.Id.GetPublicKeyBytes(), 10)
@@ -362,12 +359,9 @@ func TestHndlZeroFeesGenCB(t *testing.T) {
 		LockingScript: hex.EncodeToString(genNode.Id.GetPublicKeyBytes()),
 	}
 
-	sig1, _ := inUTXO1.MkSig(genNode.Id)
-
 	TxI1 := &txi.TransactionInput{
 		TransactionHash: inUTXO1.Hash(),
 		OutputIndex:     0,
-		UnlockingScript: sig1,
 		Amount:          inUTXO1.Amount,
 	}
 
@@ -376,12 +370,9 @@ func TestHndlZeroFeesGenCB(t *testing.T) {
 		LockingScript: hex.EncodeToString(genNode.Id.GetPublicKeyBytes()),
 	}
 
-	sig2, _ := inUTXO2.MkSig(genNode.Id)
-
 	TxI2 := &txi.TransactionInput{
 		TransactionHash: inUTXO2.Hash(),
 		OutputIndex:     1,
-		UnlockingScript: sig2,
 		Amount:          inUTXO2.Amount,
 	}
 
@@ -400,10 +391,12 @@ func TestHndlZeroFeesGenCB(t *testing.T) {
 		Outputs:  []*txo.TransactionOutput{TxO1, TxO2},
 		LockTime: genNode.Wallet.Conf.DefLckTm,
 	}
+	TxI1.UnlockingScript, _ = transaction.MkSig(0, inUTXO1, genNode.Id, tx.SigHshAll)
+	TxI2.UnlockingScript, _ = transaction.MkSig(1, inUTXO2, genNode.Id, tx.SigHshAll)
 
 	tx := genNode.Mnr.GenCBTx([]*tx.Transaction{transaction})
 
-	if tx.SumOutputs() != genNode.Mnr.Conf.InitSubsdy {
+	if tx.SumOutputs() != genNode.Conf.ChainConf.MntRwrd {
 		t.Errorf("Outputs not equal to initial")
 	}
 }
@@ -421,7 +414,7 @@ func TestHndlLowFeesGenCB(t *testing.T) {
 
 	CBTX := genNode.Mnr.GenCBTx([]*tx.Transaction{transaction})
 
-	if CBTX.SumOutputs() > genNode.Mnr.Conf.InitSubsdy {
+	if CBTX.SumOutputs() > genNode.Conf.ChainConf.MntRwrd {
 		t.Errorf("Outputs are larger than the initial")
 	}
 }
@@ -451,7 +444,7 @@ func TestHndlTxNil(t *testing.T) {
 
 	genNode.Start()
 
-	mnr := miner.New(genNode.Mnr.Conf, genNode.Id)
+	mnr := miner.New(genNode.Mnr.Conf, genNode.Conf.ChainConf, genNode.Id)
 
 	mnr.Active.Store(true)
 	mnr.HndlTx(nil)
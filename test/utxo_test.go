@@ -0,0 +1,70 @@
+package test
+
+import (
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/block/tx/txi"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/id"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"testing"
+)
+
+// TestUTXOForkUndo spends a coinbase on a fork and
+// checks that the utxo of the fork is only seen when
+// checking against the fork, and that switching the main
+// chain back and forth leaves the right utxo set and
+// transaction index.
+func TestUTXOForkUndo(t *testing.T) {
+	bc := blockchain.New(blockchain.DefaultConfig())
+	gen := bc.GetLastBlock().Hash()
+	b1 := MkTstBlk(gen, 1)
+	bc.Add(b1)
+	f1 := MkTstBlk(gen, 11)
+	bc.Add(f1)
+
+	cb := f1.Transactions[0]
+	spnd := tx.Deserialize(proto.NewTx(0,
+		[]*proto.TransactionInput{proto.NewTxInpt(cb.Hash(), 0, "", 10)},
+		[]*proto.TransactionOutput{proto.NewTxOutpt(10, "ab")}, 0))
+	gid, _ := id.LoadInSmplID(blockchain.GENPK, blockchain.GENPVK)
+	spnd.Inputs[0].UnlockingScript, _ = spnd.MkSig(0, cb.Outputs[0], gid, tx.SigHshAll)
+	if bc.ChkChainsUTXO([]*tx.Transaction{spnd}, b1.Hash()) {
+		t.Errorf("fork utxo should not be spendable on the main chain")
+	}
+	if !bc.ChkChainsUTXO([]*tx.Transaction{spnd}, f1.Hash()) {
+		t.Errorf("fork utxo should be spendable on the fork")
+	}
+
+	f2 := block.New(f1.Hash(), []*tx.Transaction{MkTstBlk("", 12).Transactions[0], spnd}, utils.CalcPOWD(1))
+	if u := bc.Add(f2); u == nil || len(u.Disconnected) != 1 {
+		t.Fatalf("fork did not become the main chain")
+	}
+	spent := &txi.TransactionInput{TransactionHash: cb.Hash(), OutputIndex: 0}
+	if bc.GetUTXO(spent) != nil || bc.GetBalance("ab") != 10 {
+		t.Errorf("utxo set does not match the fork")
+	}
+	if _, h, i, ok := bc.FndTx(spnd.Hash()); !ok || h != 2 || i != 1 {
+		t.Errorf("Expected: %v at 2 1 - Actual: %v %v %v", spnd.NameTag(), h, i, ok)
+	}
+
+	b2 := MkTstBlk(b1.Hash(), 2)
+	b3 := MkTstBlk(b2.Hash(), 3)
+	bc.Add(b2)
+	if u := bc.Add(b3); u == nil || len(u.Disconnected) != 2 {
+		t.Fatalf("old main chain did not come back")
+	}
+	if bc.GetUTXO(spent) != nil || bc.GetBalance("ab") != 0 {
+		t.Errorf("fork utxo was not rolled back")
+	}
+	if bc.GetUTXO(&txi.TransactionInput{TransactionHash: b3.Transactions[0].Hash()}) == nil {
+		t.Errorf("utxo from the main chain is missing")
+	}
+	if _, _, _, ok := bc.FndTx(spnd.Hash()); ok {
+		t.Errorf("%v is still found after its block left the main chain", spnd.NameTag())
+	}
+	if _, h, _, ok := bc.FndTx(b3.Transactions[0].Hash()); !ok || h != 3 {
+		t.Errorf("Expected: %v at 3 - Actual: %v %v", b3.NameTag(), h, ok)
+	}
+}
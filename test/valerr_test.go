@@ -0,0 +1,62 @@
+package test
+
+import (
+	"BrunoCoin/pkg/address"
+	"BrunoCoin/pkg/block"
+	"BrunoCoin/pkg/block/tx"
+	"BrunoCoin/pkg/blockchain"
+	"BrunoCoin/pkg/proto"
+	"BrunoCoin/pkg/utils"
+	"BrunoCoin/pkg/valerr"
+	"fmt"
+	"testing"
+)
+
+// TestRejectionStatus sends a node a transaction that
+// spends utxo that doesn't exist and a block whose merkle
+// root is wrong, and an invalid orphan. The node should
+// reply with rejections carrying the right reason codes,
+// and count them, which it serves to the Admin service.
+func TestRejectionStatus(t *testing.T) {
+	n := NewGenNd()
+	n.Conf.AdmnPort = GetFreePort()
+	n.Start()
+	defer n.Kill()
+	a := address.New(n.Addr, 0)
+
+	ptx := proto.NewTx(0,
+		[]*proto.TransactionInput{proto.NewTxInpt("ab", 0, "", 10)},
+		[]*proto.TransactionOutput{proto.NewTxOutpt(5, "ab")}, 0)
+	_, err := a.ForwardTransactionRPC(ptx)
+	if r := valerr.FromStatus(err); r == nil || r.Code != string(valerr.MissingInput) {
+		t.Errorf("Expected: %v - Actual: %v", valerr.MissingInput, err)
+	}
+
+	b := MkTstBlk(n.Chain.GetLastBlock().Hash(), 1)
+	b.Hdr.MrklRt = "00"
+	_, err = a.ForwardBlockRPC(b.Serialize())
+	r := valerr.FromStatus(err)
+	if r == nil || r.Code != string(valerr.BadMrkl) || r.Hash != b.Hash() {
+		t.Errorf("Expected: %v - Actual: %v", valerr.BadMrkl, err)
+	}
+
+	// An orphan whose coinbase pays too much can only be
+	// rejected once its parent comes
+	prnt := MkTstBlk(n.Chain.GetLastBlock().Hash(), 1)
+	cb := tx.Deserialize(proto.NewTx(0, nil,
+		[]*proto.TransactionOutput{proto.NewTxOutpt(1000, blockchain.GENPK)}, 2))
+	orph := block.New(prnt.Hash(), []*tx.Transaction{cb}, utils.CalcPOWD(1))
+	for !orph.SatisfiesPOW(orph.Hdr.DiffTarg) {
+		orph.Hdr.Nonce++
+	}
+	n.HndlNwBlk(orph, "")
+	n.HndlNwBlk(prnt, "")
+
+	if n.Rjcts.Get(valerr.MissingInput) != 1 || n.Rjcts.Get(valerr.BadMrkl) != 1 || n.Rjcts.Get(valerr.CBValue) != 1 {
+		t.Errorf("rejections were not counted: %v", n.Rjcts.Snapshot())
+	}
+	res, err := address.New(fmt.Sprintf("127.0.0.1:%v", n.Conf.AdmnPort), 0).GetRejectionsRPC(&proto.Empty{})
+	if err != nil || res.Counts[string(valerr.CBValue)] != 1 {
+		t.Errorf("Expected: 1 %v - Actual: %v (%v)", valerr.CBValue, res, err)
+	}
+}